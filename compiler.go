@@ -0,0 +1,105 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package gohipath
+
+import (
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal"
+	"github.com/healthiop/hipath/internal/expression"
+	"github.com/healthiop/hipath/internal/parser"
+)
+
+type FunctionRegistry struct {
+	registry *expression.FunctionRegistry
+}
+
+type CompileOptions struct {
+	Functions *FunctionRegistry
+	Limits    *hipathsys.Limits
+}
+
+type Compiler struct {
+	options CompileOptions
+}
+
+var defaultCompiler = NewCompiler(CompileOptions{})
+
+func NewFunctionRegistry() *FunctionRegistry {
+	return &FunctionRegistry{expression.NewFunctionRegistry()}
+}
+
+func (r *FunctionRegistry) Register(executor hipathsys.FunctionExecutor) error {
+	return r.registry.Register(executor)
+}
+
+func (r *FunctionRegistry) Lookup(name string) (hipathsys.FunctionExecutor, bool) {
+	return r.registry.Lookup(name)
+}
+
+func NewCompiler(options CompileOptions) *Compiler {
+	return &Compiler{options}
+}
+
+func (c *Compiler) Compile(pathString string) (*Path, *hipathsys.Error) {
+	errorItemCollection := internal.NewErrorItemCollection()
+	errorListener := internal.NewErrorListener(errorItemCollection)
+
+	is := antlr.NewInputStream(pathString)
+	lexer := parser.NewFHIRPathLexer(is)
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(errorListener)
+
+	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	p := parser.NewFHIRPathParser(stream)
+	p.RemoveErrorListeners()
+	p.AddErrorListener(errorListener)
+
+	var functions *expression.FunctionRegistry
+	if c.options.Functions != nil {
+		functions = c.options.Functions.registry
+	}
+
+	v := internal.NewVisitorWithOptions(errorItemCollection, internal.VisitorOptions{
+		Functions:       functions,
+		SourcePositions: true,
+	})
+	res := p.Expression().Accept(v)
+	v.VerifyVariables()
+
+	if errorItemCollection.HasErrors() {
+		return nil, hipathsys.NewError(
+			"error when parsing path expression", errorItemCollection.Items())
+	}
+
+	return &Path{
+		evaluator: expression.NewCollectionExpression(res.(hipathsys.Evaluator)),
+		limits:    c.options.Limits,
+	}, nil
+}
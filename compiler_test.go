@@ -0,0 +1,297 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package gohipath
+
+import (
+	"errors"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type testDoubleFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newTestDoubleFunction() *testDoubleFunction {
+	return &testDoubleFunction{
+		BaseFunction: hipathsys.NewBaseFunction("double", -1, 0, 1),
+	}
+}
+
+func (f *testDoubleFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	factor := int32(2)
+	if len(args) > 0 {
+		factor = args[0].(hipathsys.IntegerAccessor).Int()
+	}
+	return hipathsys.NewInteger(node.(hipathsys.IntegerAccessor).Int() * factor), nil
+}
+
+type testCountMatchesFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newTestCountMatchesFunction() *testCountMatchesFunction {
+	return &testCountMatchesFunction{
+		BaseFunction: hipathsys.NewBaseFunction("countMatches", 0, 1, 1),
+	}
+}
+
+func (f *testCountMatchesFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, loop hipathsys.Looper) (interface{}, error) {
+	col := node.(hipathsys.ColAccessor)
+	count := 0
+	for i := 0; i < col.Count(); i++ {
+		this := col.Get(i)
+		loop.IncIndex(this)
+		res, err := loop.Evaluator().Evaluate(ctx, this, loop)
+		if err != nil {
+			return nil, err
+		}
+		if b, ok := res.(hipathsys.BooleanAccessor); ok && b.Bool() {
+			count++
+		}
+	}
+	return hipathsys.NewInteger(int32(count)), nil
+}
+
+func TestFunctionRegistryRegister(t *testing.T) {
+	r := NewFunctionRegistry()
+	err := r.Register(newTestDoubleFunction())
+	assert.NoError(t, err, "no error expected")
+
+	f, found := r.Lookup("double")
+	assert.True(t, found, "function expected")
+	assert.Equal(t, "double", f.Name())
+}
+
+func TestFunctionRegistryLookupBuiltin(t *testing.T) {
+	r := NewFunctionRegistry()
+	f, found := r.Lookup("where")
+	assert.True(t, found, "function expected")
+	assert.Equal(t, "where", f.Name())
+}
+
+func TestFunctionRegistryRegisterBuiltin(t *testing.T) {
+	r := NewFunctionRegistry()
+	err := r.Register(&testDoubleFunction{
+		BaseFunction: hipathsys.NewBaseFunction("where", -1, 0, 0),
+	})
+	assert.EqualError(t, err, "executor has already been defined: where")
+}
+
+func TestFunctionRegistryRegisterDuplicate(t *testing.T) {
+	r := NewFunctionRegistry()
+	assert.NoError(t, r.Register(newTestDoubleFunction()))
+	assert.EqualError(t, r.Register(newTestDoubleFunction()),
+		"executor has already been defined: double")
+}
+
+func TestFunctionRegistryRegisterInvalidParams(t *testing.T) {
+	r := NewFunctionRegistry()
+	err := r.Register(&testDoubleFunction{
+		BaseFunction: hipathsys.NewBaseFunction("test", -1, 2, 1),
+	})
+	assert.EqualError(t, err, "executor test has invalid parameter bounds 2..1")
+}
+
+func TestFunctionRegistryRegisterInvalidEvaluatorParam(t *testing.T) {
+	r := NewFunctionRegistry()
+	err := r.Register(&testDoubleFunction{
+		BaseFunction: hipathsys.NewBaseFunction("test", 1, 0, 1),
+	})
+	assert.EqualError(t, err, "executor test has invalid evaluator parameter 1")
+}
+
+func TestFunctionRegistryRegisterNoName(t *testing.T) {
+	r := NewFunctionRegistry()
+	err := r.Register(&testDoubleFunction{
+		BaseFunction: hipathsys.NewBaseFunction("", -1, 0, 0),
+	})
+	assert.EqualError(t, err, "executor name must not be empty")
+}
+
+func TestFunctionRegistryRegisterConcurrentCompile(t *testing.T) {
+	r := NewFunctionRegistry()
+	c := NewCompiler(CompileOptions{Functions: r})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, r.Register(&testDoubleFunction{
+				BaseFunction: hipathsys.NewBaseFunction("double"+strconv.Itoa(i), -1, 0, 1),
+			}))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := c.Compile("2.where($this > 1)")
+			assert.Nil(t, err, "no error expected")
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 10; i++ {
+		_, found := r.Lookup("double" + strconv.Itoa(i))
+		assert.True(t, found, "function expected")
+	}
+}
+
+func TestCompilerCustomFunction(t *testing.T) {
+	r := NewFunctionRegistry()
+	assert.NoError(t, r.Register(newTestDoubleFunction()))
+	c := NewCompiler(CompileOptions{Functions: r})
+
+	path, err := c.Compile("double() + double(3)")
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, path, "path expected") {
+		res, err := path.Execute(test.NewTestContext(t), hipathsys.NewInteger(7))
+		assert.Nil(t, err, "no error expected")
+		if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+			assert.Equal(t, hipathsys.NewInteger(35), res.Get(0))
+		}
+	}
+}
+
+func TestCompilerCustomLoopFunction(t *testing.T) {
+	r := NewFunctionRegistry()
+	assert.NoError(t, r.Register(newTestCountMatchesFunction()))
+	c := NewCompiler(CompileOptions{Functions: r})
+
+	path, err := c.Compile("(1 | 2 | 3 | 4).countMatches($this > 2)")
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, path, "path expected") {
+		res, err := path.Execute(test.NewTestContext(t), nil)
+		assert.Nil(t, err, "no error expected")
+		if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+			assert.Equal(t, hipathsys.NewInteger(2), res.Get(0))
+		}
+	}
+}
+
+func TestCompilerCustomFunctionTooManyArgs(t *testing.T) {
+	r := NewFunctionRegistry()
+	assert.NoError(t, r.Register(newTestDoubleFunction()))
+	c := NewCompiler(CompileOptions{Functions: r})
+
+	path, err := c.Compile("double(1, 2)")
+	assert.Nil(t, path, "no path expected")
+	if assert.NotNil(t, err, "error expected") && assert.Len(t, err.Items(), 1) {
+		assert.Equal(t, "executor double accepts at most 1 parameters", err.Items()[0].Msg())
+	}
+}
+
+func TestCompilerCustomFunctionScope(t *testing.T) {
+	r := NewFunctionRegistry()
+	assert.NoError(t, r.Register(newTestDoubleFunction()))
+	NewCompiler(CompileOptions{Functions: r})
+
+	path, err := NewCompiler(CompileOptions{}).Compile("double()")
+	assert.Nil(t, path, "no path expected")
+	if assert.NotNil(t, err, "error expected") && assert.Len(t, err.Items(), 1) {
+		assert.Equal(t, "executor has not been defined: double", err.Items()[0].Msg())
+	}
+
+	path, err = Compile("double()")
+	assert.Nil(t, path, "no path expected")
+	assert.NotNil(t, err, "error expected")
+}
+
+func TestCompilerLimitsCollectionSize(t *testing.T) {
+	c := NewCompiler(CompileOptions{Limits: &hipathsys.Limits{MaxCollectionSize: 3}})
+
+	path, err := c.Compile("1 | 2 | 3 | 4")
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, path, "path expected") {
+		res, err := path.Execute(test.NewTestContext(t), nil)
+		assert.Nil(t, res, "no result expected")
+		if assert.NotNil(t, err, "error expected") {
+			var limitErr *hipathsys.LimitError
+			if assert.True(t, errors.As(err, &limitErr)) {
+				assert.Equal(t, hipathsys.CollectionSizeLimit, limitErr.Kind())
+				assert.Equal(t, 3, limitErr.Max())
+			}
+			assert.Equal(t, "evaluation limit exceeded: maximum collection size of 3", err.Error())
+		}
+	}
+}
+
+func TestCompilerLimitsNotExceeded(t *testing.T) {
+	c := NewCompiler(CompileOptions{Limits: &hipathsys.Limits{
+		MaxCollectionSize: 3, MaxFunctionCalls: 2, MaxStringLength: 6}})
+
+	path, err := c.Compile("(1 | 2 | 3).where($this > 1).count()")
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, path, "path expected") {
+		for i := 0; i < 2; i++ {
+			res, err := path.Execute(test.NewTestContext(t), nil)
+			assert.Nil(t, err, "no error expected")
+			if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+				assert.Equal(t, hipathsys.NewInteger(2), res.Get(0))
+			}
+		}
+	}
+}
+
+func TestCompilerLimitsFunctionCalls(t *testing.T) {
+	c := NewCompiler(CompileOptions{Limits: &hipathsys.Limits{MaxFunctionCalls: 3}})
+
+	path, err := c.Compile("(1 | 2 | 3).select($this.toString()).count()")
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, path, "path expected") {
+		res, err := path.Execute(test.NewTestContext(t), nil)
+		assert.Nil(t, res, "no result expected")
+		if assert.NotNil(t, err, "error expected") {
+			var limitErr *hipathsys.LimitError
+			if assert.True(t, errors.As(err, &limitErr)) {
+				assert.Equal(t, hipathsys.FunctionCallsLimit, limitErr.Kind())
+			}
+		}
+	}
+}
+
+func TestCompilerLimitsStringLength(t *testing.T) {
+	c := NewCompiler(CompileOptions{Limits: &hipathsys.Limits{MaxStringLength: 5}})
+
+	path, err := c.Compile("'abc' + 'def'")
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, path, "path expected") {
+		res, err := path.Execute(test.NewTestContext(t), nil)
+		assert.Nil(t, res, "no result expected")
+		if assert.NotNil(t, err, "error expected") {
+			var limitErr *hipathsys.LimitError
+			if assert.True(t, errors.As(err, &limitErr)) {
+				assert.Equal(t, hipathsys.StringLengthLimit, limitErr.Kind())
+			}
+		}
+	}
+}
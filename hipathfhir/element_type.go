@@ -0,0 +1,322 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathfhir
+
+import "github.com/healthiop/hipath/hipathsys"
+
+const backboneElementTypeName = "BackboneElement"
+
+// elementTypeNames contains the types of the elements of the commonly used
+// data types and resources, keyed by the path of the element. Elements that
+// are not contained are typed by their JSON value only.
+var elementTypeNames = map[string]string{
+	"Element.id":                        "string",
+	"Element.extension":                 "Extension",
+	"BackboneElement.modifierExtension": "Extension",
+	"Extension.url":                     "uri",
+
+	"Resource.id":                      "id",
+	"Resource.meta":                    "Meta",
+	"Resource.implicitRules":           "uri",
+	"Resource.language":                "code",
+	"DomainResource.text":              "Narrative",
+	"DomainResource.extension":         "Extension",
+	"DomainResource.modifierExtension": "Extension",
+
+	"Address.use":                  "code",
+	"Address.type":                 "code",
+	"Address.text":                 "string",
+	"Address.line":                 "string",
+	"Address.city":                 "string",
+	"Address.district":             "string",
+	"Address.state":                "string",
+	"Address.postalCode":           "string",
+	"Address.country":              "string",
+	"Address.period":               "Period",
+	"Annotation.authorReference":   "Reference",
+	"Annotation.time":              "dateTime",
+	"Annotation.text":              "markdown",
+	"Attachment.contentType":       "code",
+	"Attachment.language":          "code",
+	"Attachment.data":              "base64Binary",
+	"Attachment.url":               "url",
+	"Attachment.size":              "unsignedInt",
+	"Attachment.hash":              "base64Binary",
+	"Attachment.title":             "string",
+	"Attachment.creation":          "dateTime",
+	"CodeableConcept.coding":       "Coding",
+	"CodeableConcept.text":         "string",
+	"Coding.system":                "uri",
+	"Coding.version":               "string",
+	"Coding.code":                  "code",
+	"Coding.display":               "string",
+	"Coding.userSelected":          "boolean",
+	"ContactDetail.name":           "string",
+	"ContactDetail.telecom":        "ContactPoint",
+	"ContactPoint.system":          "code",
+	"ContactPoint.value":           "string",
+	"ContactPoint.use":             "code",
+	"ContactPoint.rank":            "positiveInt",
+	"ContactPoint.period":          "Period",
+	"Dosage.sequence":              "integer",
+	"Dosage.text":                  "string",
+	"Dosage.additionalInstruction": "CodeableConcept",
+	"Dosage.patientInstruction":    "string",
+	"Dosage.timing":                "Timing",
+	"Dosage.site":                  "CodeableConcept",
+	"Dosage.route":                 "CodeableConcept",
+	"Dosage.method":                "CodeableConcept",
+	"HumanName.use":                "code",
+	"HumanName.text":               "string",
+	"HumanName.family":             "string",
+	"HumanName.given":              "string",
+	"HumanName.prefix":             "string",
+	"HumanName.suffix":             "string",
+	"HumanName.period":             "Period",
+	"Identifier.use":               "code",
+	"Identifier.type":              "CodeableConcept",
+	"Identifier.system":            "uri",
+	"Identifier.value":             "string",
+	"Identifier.period":            "Period",
+	"Identifier.assigner":          "Reference",
+	"Meta.versionId":               "id",
+	"Meta.lastUpdated":             "instant",
+	"Meta.source":                  "uri",
+	"Meta.profile":                 "canonical",
+	"Meta.security":                "Coding",
+	"Meta.tag":                     "Coding",
+	"Money.value":                  "decimal",
+	"Money.currency":               "code",
+	"Narrative.status":             "code",
+	"Narrative.div":                "string",
+	"Period.start":                 "dateTime",
+	"Period.end":                   "dateTime",
+	"Quantity.value":               "decimal",
+	"Quantity.comparator":          "code",
+	"Quantity.unit":                "string",
+	"Quantity.system":              "uri",
+	"Quantity.code":                "code",
+	"Range.low":                    "Quantity",
+	"Range.high":                   "Quantity",
+	"Ratio.numerator":              "Quantity",
+	"Ratio.denominator":            "Quantity",
+	"Reference.reference":          "string",
+	"Reference.type":               "uri",
+	"Reference.identifier":         "Identifier",
+	"Reference.display":            "string",
+	"Signature.type":               "Coding",
+	"Signature.when":               "instant",
+	"Signature.who":                "Reference",
+	"Signature.onBehalfOf":         "Reference",
+	"Signature.targetFormat":       "code",
+	"Signature.sigFormat":          "code",
+	"Signature.data":               "base64Binary",
+	"Timing.event":                 "dateTime",
+	"Timing.repeat":                backboneElementTypeName,
+	"Timing.repeat.count":          "positiveInt",
+	"Timing.repeat.countMax":       "positiveInt",
+	"Timing.repeat.duration":       "decimal",
+	"Timing.repeat.durationMax":    "decimal",
+	"Timing.repeat.durationUnit":   "code",
+	"Timing.repeat.frequency":      "positiveInt",
+	"Timing.repeat.frequencyMax":   "positiveInt",
+	"Timing.repeat.period":         "decimal",
+	"Timing.repeat.periodMax":      "decimal",
+	"Timing.repeat.periodUnit":     "code",
+	"Timing.repeat.dayOfWeek":      "code",
+	"Timing.repeat.timeOfDay":      "time",
+	"Timing.repeat.when":           "code",
+	"Timing.repeat.offset":         "unsignedInt",
+	"Timing.code":                  "CodeableConcept",
+	"UsageContext.code":            "Coding",
+
+	"Bundle.identifier":                      "Identifier",
+	"Bundle.type":                            "code",
+	"Bundle.timestamp":                       "instant",
+	"Bundle.total":                           "unsignedInt",
+	"Bundle.link":                            backboneElementTypeName,
+	"Bundle.link.relation":                   "string",
+	"Bundle.link.url":                        "uri",
+	"Bundle.entry":                           backboneElementTypeName,
+	"Bundle.entry.fullUrl":                   "uri",
+	"Bundle.entry.search":                    backboneElementTypeName,
+	"Bundle.entry.search.mode":               "code",
+	"Bundle.entry.search.score":              "decimal",
+	"Bundle.entry.request":                   backboneElementTypeName,
+	"Bundle.entry.request.method":            "code",
+	"Bundle.entry.request.url":               "uri",
+	"Bundle.entry.request.ifNoneMatch":       "string",
+	"Bundle.entry.request.ifModifiedSince":   "instant",
+	"Bundle.entry.request.ifMatch":           "string",
+	"Bundle.entry.request.ifNoneExist":       "string",
+	"Bundle.entry.response":                  backboneElementTypeName,
+	"Bundle.entry.response.status":           "string",
+	"Bundle.entry.response.location":         "uri",
+	"Bundle.entry.response.etag":             "string",
+	"Bundle.entry.response.lastModified":     "instant",
+	"Bundle.signature":                       "Signature",
+	"Condition.identifier":                   "Identifier",
+	"Condition.clinicalStatus":               "CodeableConcept",
+	"Condition.verificationStatus":           "CodeableConcept",
+	"Condition.category":                     "CodeableConcept",
+	"Condition.severity":                     "CodeableConcept",
+	"Condition.code":                         "CodeableConcept",
+	"Condition.bodySite":                     "CodeableConcept",
+	"Condition.subject":                      "Reference",
+	"Condition.encounter":                    "Reference",
+	"Condition.recordedDate":                 "dateTime",
+	"Condition.recorder":                     "Reference",
+	"Condition.asserter":                     "Reference",
+	"Condition.note":                         "Annotation",
+	"Encounter.identifier":                   "Identifier",
+	"Encounter.status":                       "code",
+	"Encounter.class":                        "Coding",
+	"Encounter.type":                         "CodeableConcept",
+	"Encounter.serviceType":                  "CodeableConcept",
+	"Encounter.priority":                     "CodeableConcept",
+	"Encounter.subject":                      "Reference",
+	"Encounter.basedOn":                      "Reference",
+	"Encounter.period":                       "Period",
+	"Encounter.reasonCode":                   "CodeableConcept",
+	"Encounter.reasonReference":              "Reference",
+	"Encounter.serviceProvider":              "Reference",
+	"Encounter.partOf":                       "Reference",
+	"Observation.identifier":                 "Identifier",
+	"Observation.basedOn":                    "Reference",
+	"Observation.partOf":                     "Reference",
+	"Observation.status":                     "code",
+	"Observation.category":                   "CodeableConcept",
+	"Observation.code":                       "CodeableConcept",
+	"Observation.subject":                    "Reference",
+	"Observation.focus":                      "Reference",
+	"Observation.encounter":                  "Reference",
+	"Observation.issued":                     "instant",
+	"Observation.performer":                  "Reference",
+	"Observation.dataAbsentReason":           "CodeableConcept",
+	"Observation.interpretation":             "CodeableConcept",
+	"Observation.note":                       "Annotation",
+	"Observation.bodySite":                   "CodeableConcept",
+	"Observation.method":                     "CodeableConcept",
+	"Observation.specimen":                   "Reference",
+	"Observation.device":                     "Reference",
+	"Observation.referenceRange":             backboneElementTypeName,
+	"Observation.referenceRange.low":         "Quantity",
+	"Observation.referenceRange.high":        "Quantity",
+	"Observation.referenceRange.type":        "CodeableConcept",
+	"Observation.referenceRange.appliesTo":   "CodeableConcept",
+	"Observation.referenceRange.age":         "Range",
+	"Observation.referenceRange.text":        "string",
+	"Observation.hasMember":                  "Reference",
+	"Observation.derivedFrom":                "Reference",
+	"Observation.component":                  backboneElementTypeName,
+	"Observation.component.code":             "CodeableConcept",
+	"Observation.component.dataAbsentReason": "CodeableConcept",
+	"Observation.component.interpretation":   "CodeableConcept",
+	"Organization.identifier":                "Identifier",
+	"Organization.active":                    "boolean",
+	"Organization.type":                      "CodeableConcept",
+	"Organization.name":                      "string",
+	"Organization.alias":                     "string",
+	"Organization.telecom":                   "ContactPoint",
+	"Organization.address":                   "Address",
+	"Organization.partOf":                    "Reference",
+	"Patient.identifier":                     "Identifier",
+	"Patient.active":                         "boolean",
+	"Patient.name":                           "HumanName",
+	"Patient.telecom":                        "ContactPoint",
+	"Patient.gender":                         "code",
+	"Patient.birthDate":                      "date",
+	"Patient.address":                        "Address",
+	"Patient.maritalStatus":                  "CodeableConcept",
+	"Patient.photo":                          "Attachment",
+	"Patient.contact":                        backboneElementTypeName,
+	"Patient.contact.relationship":           "CodeableConcept",
+	"Patient.contact.name":                   "HumanName",
+	"Patient.contact.telecom":                "ContactPoint",
+	"Patient.contact.address":                "Address",
+	"Patient.contact.gender":                 "code",
+	"Patient.contact.organization":           "Reference",
+	"Patient.contact.period":                 "Period",
+	"Patient.communication":                  backboneElementTypeName,
+	"Patient.communication.language":         "CodeableConcept",
+	"Patient.communication.preferred":        "boolean",
+	"Patient.generalPractitioner":            "Reference",
+	"Patient.managingOrganization":           "Reference",
+	"Patient.link":                           backboneElementTypeName,
+	"Patient.link.other":                     "Reference",
+	"Patient.link.type":                      "code",
+	"Practitioner.identifier":                "Identifier",
+	"Practitioner.active":                    "boolean",
+	"Practitioner.name":                      "HumanName",
+	"Practitioner.telecom":                   "ContactPoint",
+	"Practitioner.address":                   "Address",
+	"Practitioner.gender":                    "code",
+	"Practitioner.birthDate":                 "date",
+	"Practitioner.photo":                     "Attachment",
+	"Practitioner.qualification":             backboneElementTypeName,
+	"Practitioner.qualification.identifier":  "Identifier",
+	"Practitioner.qualification.code":        "CodeableConcept",
+	"Practitioner.qualification.period":      "Period",
+	"Practitioner.qualification.issuer":      "Reference",
+	"Practitioner.communication":             "CodeableConcept",
+}
+
+// elementTypeSpec returns the type of the element with the specified name
+// and the path that is used to look up its own elements. The path of the
+// parent is looked up before the names of the parent type and its base types.
+func elementTypeSpec(path string, parent hipathsys.TypeSpecAccessor, name string) (hipathsys.TypeSpecAccessor, string) {
+	if len(path) > 0 {
+		if typeSpec, p := elementTypeSpecByPath(path + "." + name); typeSpec != nil {
+			return typeSpec, p
+		}
+	}
+	for t := parent; t != nil; t = t.Base() {
+		if t.FQName().Namespace() != NamespaceName {
+			break
+		}
+		if typeSpec, p := elementTypeSpecByPath(t.FQName().Name() + "." + name); typeSpec != nil {
+			return typeSpec, p
+		}
+	}
+	return nil, ""
+}
+
+func elementTypeSpecByPath(path string) (hipathsys.TypeSpecAccessor, string) {
+	typeName, found := elementTypeNames[path]
+	if !found {
+		return nil, ""
+	}
+	if typeName == backboneElementTypeName {
+		return BackboneElementTypeSpec, path
+	}
+	if typeSpec := complexTypeSpecs[typeName]; typeSpec != nil {
+		return typeSpec, typeName
+	}
+	return primitiveTypeSpecs[typeName], typeName
+}
@@ -0,0 +1,502 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathfhir
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const resourceTypeName = "resourceType"
+const primitiveElementPrefix = "_"
+
+type jsonAdapter struct {
+}
+
+type jsonPrimitive struct {
+	value    interface{}
+	element  map[string]interface{}
+	typeSpec hipathsys.TypeSpecAccessor
+}
+
+type jsonObject struct {
+	value    map[string]interface{}
+	typeSpec hipathsys.TypeSpecAccessor
+	path     string
+}
+
+type JSONPrimitiveAccessor interface {
+	JSONValue() interface{}
+	JSONElement() map[string]interface{}
+}
+
+func NewJSONAdapter() hipathsys.ModelAdapter {
+	return &jsonAdapter{}
+}
+
+func ParseJSON(data []byte) (interface{}, error) {
+	return DecodeJSON(bytes.NewReader(data))
+}
+
+func DecodeJSON(r io.Reader) (interface{}, error) {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+
+	var res interface{}
+	if err := d.Decode(&res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (p *jsonPrimitive) JSONValue() interface{} {
+	return p.value
+}
+
+func (p *jsonPrimitive) JSONElement() map[string]interface{} {
+	return p.element
+}
+
+func (o *jsonObject) ModelNode() interface{} {
+	return o.value
+}
+
+func (a *jsonAdapter) AsType(node interface{}, name hipathsys.FQTypeNameAccessor) (interface{}, error) {
+	if a.TypeSpec(node).ExtendsName(name) {
+		return node, nil
+	}
+	if name.Namespace() == hipathsys.NamespaceName {
+		return a.CastToSystem(node)
+	}
+	return nil, nil
+}
+
+func (a *jsonAdapter) CastToSystem(node interface{}) (hipathsys.AnyAccessor, error) {
+	if n, ok := node.(hipathsys.AnyAccessor); ok {
+		return n, nil
+	}
+	return nil, nil
+}
+
+func (a *jsonAdapter) TypeSpec(node interface{}) hipathsys.TypeSpecAccessor {
+	switch n := node.(type) {
+	case hipathsys.AnyAccessor:
+		if p, ok := n.Source().(*jsonPrimitive); ok {
+			return p.typeSpec
+		}
+	case *jsonPrimitive:
+		if n.typeSpec != nil {
+			return n.typeSpec
+		}
+		return ElementTypeSpec
+	case *jsonObject:
+		return n.typeSpec
+	case map[string]interface{}:
+		if rt, ok := n[resourceTypeName].(string); ok {
+			return ResourceTypeSpecOf(rt)
+		}
+		return ElementTypeSpec
+	}
+	return hipathsys.UndefinedTypeSpec
+}
+
+func (a *jsonAdapter) Equal(node1 interface{}, node2 interface{}) bool {
+	return reflect.DeepEqual(jsonValue(node1), jsonValue(node2))
+}
+
+func (a *jsonAdapter) Equivalent(node1 interface{}, node2 interface{}) bool {
+	return a.Equal(node1, node2)
+}
+
+func (a *jsonAdapter) Navigate(node interface{}, name string) (interface{}, error) {
+	if col, ok := node.(hipathsys.ColAccessor); ok {
+		res := hipathsys.NewCol(a)
+		count := col.Count()
+		for i := 0; i < count; i++ {
+			n, err := a.Navigate(col.Get(i), name)
+			if err != nil {
+				return nil, err
+			}
+			addItems(res, n)
+		}
+		return res, nil
+	}
+
+	obj := jsonObjectValue(node)
+	if obj == nil {
+		return hipathsys.EmptyCol, nil
+	}
+
+	if rt, ok := obj[resourceTypeName].(string); ok && rt == name {
+		return node, nil
+	}
+
+	value, found := obj[name]
+	element, elementFound := obj[primitiveElementPrefix+name]
+	if found || elementFound {
+		typeSpec, path := a.elementTypeSpec(node, name)
+		return a.convert(value, element, typeSpec, path)
+	}
+
+	for key, value := range obj {
+		if len(key) > len(name) && strings.HasPrefix(key, name) {
+			if typeSpec := ChoiceTypeSpec(key[len(name):]); typeSpec != nil {
+				return a.convert(value, obj[primitiveElementPrefix+key], typeSpec, typeSpec.FQName().Name())
+			}
+		}
+	}
+	elementName := primitiveElementPrefix + name
+	for key, element := range obj {
+		if len(key) > len(elementName) && strings.HasPrefix(key, elementName) {
+			if typeSpec := ChoiceTypeSpec(key[len(elementName):]); typeSpec != nil {
+				return a.convert(nil, element, typeSpec, typeSpec.FQName().Name())
+			}
+		}
+	}
+
+	return hipathsys.EmptyCol, nil
+}
+
+func (a *jsonAdapter) elementTypeSpec(node interface{}, name string) (hipathsys.TypeSpecAccessor, string) {
+	var path string
+	switch n := node.(type) {
+	case map[string]interface{}:
+		path, _ = n[resourceTypeName].(string)
+	case *jsonObject:
+		path = n.path
+	}
+	return elementTypeSpec(path, a.TypeSpec(node), name)
+}
+
+func (a *jsonAdapter) Children(node interface{}) (hipathsys.ColAccessor, error) {
+	obj := jsonObjectValue(node)
+	if obj == nil {
+		return nil, nil
+	}
+
+	res := hipathsys.NewCol(a)
+	for _, key := range elementKeys(obj) {
+		typeSpec, path := a.elementTypeSpec(node, key)
+		n, err := a.convert(obj[key], obj[primitiveElementPrefix+key], typeSpec, path)
+		if err != nil {
+			return nil, err
+		}
+		addItems(res, n)
+	}
+	return res, nil
+}
+
+func (a *jsonAdapter) Resource(node interface{}) bool {
+	var obj map[string]interface{}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		obj = n
+	case *jsonObject:
+		obj = n.value
+	default:
+		return false
+	}
+
+	_, ok := obj[resourceTypeName].(string)
+	return ok
+}
+
+func (a *jsonAdapter) PrimitiveValue(node interface{}) (hipathsys.AnyAccessor, bool) {
+	if n, ok := node.(hipathsys.AnyAccessor); ok {
+		if _, ok := n.Source().(*jsonPrimitive); ok {
+			return n, true
+		}
+	}
+	return nil, false
+}
+
+func (a *jsonAdapter) Extensions(node interface{}) (hipathsys.ColAccessor, error) {
+	if _, ok := node.(hipathsys.ColAccessor); ok {
+		return nil, fmt.Errorf("extensions cannot be determined for a collection")
+	}
+	if jsonObjectValue(node) == nil {
+		return nil, nil
+	}
+
+	extensions, err := a.Navigate(node, "extension")
+	if err != nil {
+		return nil, err
+	}
+	if col, ok := extensions.(hipathsys.ColAccessor); ok {
+		return col, nil
+	}
+	return hipathsys.NewColWithItem(a, extensions), nil
+}
+
+func (a *jsonAdapter) ClassInfo(node interface{}) hipathsys.ClassInfoAccessor {
+	var obj map[string]interface{}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		obj = n
+	case *jsonObject:
+		obj = n.value
+	default:
+		return nil
+	}
+
+	keys := elementKeys(obj)
+	elements := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		value, element := obj[key], obj[primitiveElementPrefix+key]
+		elementTypeSpec, path := a.elementTypeSpec(node, key)
+		n, err := a.convert(value, element, elementTypeSpec, path)
+		if err != nil {
+			return nil
+		}
+
+		item := n
+		if col, ok := n.(hipathsys.ColAccessor); ok {
+			item = nil
+			if col.Count() > 0 {
+				item = col.Get(0)
+			}
+		}
+		typeName := a.TypeSpec(item).String()
+
+		var oneBased hipathsys.BooleanAccessor
+		_, valuesArray := value.([]interface{})
+		_, elementsArray := element.([]interface{})
+		if valuesArray || elementsArray {
+			typeName = "List<" + typeName + ">"
+			oneBased = hipathsys.False
+		}
+
+		elements = append(elements, hipathsys.NewClassInfoElement(
+			hipathsys.NewString(key), hipathsys.NewString(typeName), oneBased))
+	}
+
+	typeSpec := a.TypeSpec(node)
+	var baseType hipathsys.StringAccessor
+	if fqBaseName := typeSpec.FQBaseName(); fqBaseName != nil {
+		baseType = hipathsys.NewString(fqBaseName.String())
+	}
+
+	return hipathsys.NewClassInfo(hipathsys.NewString(typeSpec.FQName().Namespace()),
+		hipathsys.NewString(typeSpec.FQName().Name()), baseType,
+		hipathsys.NewSysArrayCol(hipathsys.ClassInfoElementTypeSpec, elements))
+}
+
+func (a *jsonAdapter) convert(value interface{}, element interface{}, typeSpec hipathsys.TypeSpecAccessor, path string) (interface{}, error) {
+	values, valuesArray := value.([]interface{})
+	elements, elementsArray := element.([]interface{})
+	if !valuesArray && !elementsArray {
+		e, _ := element.(map[string]interface{})
+		return a.convertItem(value, e, typeSpec, path)
+	}
+
+	count := len(values)
+	if len(elements) > count {
+		count = len(elements)
+	}
+
+	res := hipathsys.NewCol(a)
+	for i := 0; i < count; i++ {
+		var v interface{}
+		if i < len(values) {
+			v = values[i]
+		}
+		var e map[string]interface{}
+		if i < len(elements) {
+			e, _ = elements[i].(map[string]interface{})
+		}
+
+		n, err := a.convertItem(v, e, typeSpec, path)
+		if err != nil {
+			return nil, err
+		}
+		if n != nil {
+			res.Add(n)
+		}
+	}
+	return res, nil
+}
+
+func (a *jsonAdapter) convertItem(value interface{}, element map[string]interface{}, typeSpec hipathsys.TypeSpecAccessor, path string) (interface{}, error) {
+	if m, ok := value.(map[string]interface{}); ok {
+		if typeSpec == nil || primitiveTypeSpec(typeSpec) {
+			return m, nil
+		}
+		if _, ok := m[resourceTypeName]; ok {
+			return m, nil
+		}
+		return &jsonObject{m, typeSpec, path}, nil
+	}
+
+	if typeSpec != nil && !primitiveTypeSpec(typeSpec) {
+		typeSpec = nil
+	}
+	switch v := value.(type) {
+	case nil:
+		if element == nil {
+			return nil, nil
+		}
+		return &jsonPrimitive{nil, element, typeSpec}, nil
+	case bool:
+		return hipathsys.NewBooleanWithSource(v, &jsonPrimitive{v, element, BooleanTypeSpec}), nil
+	case json.Number:
+		return convertNumber(v.String(), v, element, typeSpec)
+	case float64:
+		return convertNumber(strconv.FormatFloat(v, 'f', -1, 64), v, element, typeSpec)
+	case string:
+		return convertString(v, element, typeSpec), nil
+	}
+	return nil, fmt.Errorf("unsupported JSON value: %T", value)
+}
+
+func convertNumber(value string, raw interface{}, element map[string]interface{}, typeSpec hipathsys.TypeSpecAccessor) (interface{}, error) {
+	if typeSpec == nil || !typeSpec.ExtendsName(DecimalTypeSpec.FQName()) {
+		if !strings.ContainsAny(value, ".eE") {
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				if typeSpec == Integer64TypeSpec || i < math.MinInt32 || i > math.MaxInt32 {
+					if typeSpec == nil {
+						typeSpec = Integer64TypeSpec
+					}
+					return hipathsys.NewLongWithSource(i, &jsonPrimitive{raw, element, typeSpec}), nil
+				}
+				if typeSpec == nil {
+					typeSpec = IntegerTypeSpec
+				}
+				return hipathsys.NewIntegerWithSource(int32(i), &jsonPrimitive{raw, element, typeSpec}), nil
+			}
+		}
+	}
+
+	if typeSpec == nil {
+		typeSpec = DecimalTypeSpec
+	}
+	return hipathsys.ParseDecimalWithSource(value, &jsonPrimitive{raw, element, typeSpec})
+}
+
+func convertString(value string, element map[string]interface{}, typeSpec hipathsys.TypeSpecAccessor) interface{} {
+	if typeSpec == nil {
+		typeSpec = StringTypeSpec
+	}
+
+	source := &jsonPrimitive{value, element, typeSpec}
+	switch typeSpec {
+	case DateTypeSpec:
+		if d, err := hipathsys.ParseDateWithSource(value, source); err == nil {
+			return d
+		}
+	case DateTimeTypeSpec, InstantTypeSpec:
+		if d, err := hipathsys.ParseDateTimeWithSource(value, source); err == nil {
+			return d
+		}
+	case TimeTypeSpec:
+		if t, err := hipathsys.ParseTimeWithSource(value, source); err == nil {
+			return t
+		}
+	case Integer64TypeSpec:
+		if l, err := hipathsys.ParseLongWithSource(value, source); err == nil {
+			return l
+		}
+	}
+
+	if !typeSpec.ExtendsName(StringTypeSpec.FQName()) && !typeSpec.ExtendsName(URITypeSpec.FQName()) &&
+		!typeSpec.ExtendsName(Base64BinaryTypeSpec.FQName()) {
+		source.typeSpec = StringTypeSpec
+	}
+	return hipathsys.NewStringWithSource(value, source)
+}
+
+func primitiveTypeSpec(typeSpec hipathsys.TypeSpecAccessor) bool {
+	return primitiveTypeSpecs[typeSpec.FQName().Name()] == typeSpec
+}
+
+func jsonObjectValue(node interface{}) map[string]interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		return n
+	case *jsonObject:
+		return n.value
+	case *jsonPrimitive:
+		return n.element
+	case hipathsys.AnyAccessor:
+		if p, ok := n.Source().(*jsonPrimitive); ok {
+			return p.element
+		}
+	}
+	return nil
+}
+
+func jsonValue(node interface{}) interface{} {
+	switch n := node.(type) {
+	case *jsonObject:
+		return n.value
+	case *jsonPrimitive:
+		return n.element
+	case hipathsys.AnyAccessor:
+		if p, ok := n.Source().(*jsonPrimitive); ok {
+			return p.value
+		}
+	}
+	return node
+}
+
+func elementKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		if key == resourceTypeName {
+			continue
+		}
+		if strings.HasPrefix(key, primitiveElementPrefix) {
+			if _, found := obj[key[len(primitiveElementPrefix):]]; found {
+				continue
+			}
+			key = key[len(primitiveElementPrefix):]
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func addItems(col hipathsys.ColModifier, node interface{}) {
+	if node == nil {
+		return
+	}
+	if c, ok := node.(hipathsys.ColAccessor); ok {
+		col.AddAll(c)
+	} else {
+		col.Add(node)
+	}
+}
@@ -0,0 +1,813 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathfhir
+
+import (
+	"encoding/json"
+	"github.com/healthiop/hipath"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const testPatient = `{
+  "resourceType": "Patient",
+  "id": "example",
+  "active": true,
+  "multipleBirthInteger": 2,
+  "name": [
+    {
+      "use": "official",
+      "family": "Chalmers",
+      "given": ["Peter", "James"],
+      "_given": [null, {"extension": [{"url": "http://example.org/x", "valueString": "ext"}]}]
+    },
+    {
+      "use": "usual",
+      "given": ["Jim"]
+    }
+  ],
+  "birthDate": "1974-12-25",
+  "_birthDate": {
+    "extension": [{"url": "http://hl7.org/fhir/StructureDefinition/patient-birthTime", "valueDateTime": "1974-12-25T14:35:45-05:00"}]
+  },
+  "_gender": {
+    "extension": [{"url": "http://example.org/absent", "valueCode": "unknown"}]
+  }
+}`
+
+const testObservation = `{
+  "resourceType": "Observation",
+  "status": "final",
+  "valueQuantity": {"value": 185.10, "unit": "lbs", "system": "http://unitsofmeasure.org", "code": "[lb_av]"},
+  "effectiveDateTime": "2016-03-28"
+}`
+
+func parseTestJSON(t *testing.T, data string) interface{} {
+	node, err := ParseJSON([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return node
+}
+
+func evaluateJSON(t *testing.T, path string, data string) hipathsys.ColAccessor {
+	node := parseTestJSON(t, data)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Node(node).Build()
+	res, err := gohipath.Execute(ctx, path, node)
+	if err != nil {
+		t.Fatalf("evaluation of %s failed: %v", path, err)
+	}
+	return res
+}
+
+func TestDecodeJSONNumber(t *testing.T) {
+	node, err := DecodeJSON(strings.NewReader(`{"value": 1.10}`))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, json.Number("1.10"), node.(map[string]interface{})["value"])
+}
+
+func TestParseJSONInvalid(t *testing.T) {
+	node, err := ParseJSON([]byte(`{"value"`))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, node)
+}
+
+func TestJSONAdapterResourceType(t *testing.T) {
+	res := evaluateJSON(t, "Patient.id", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("example"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterResourceTypeDiffers(t *testing.T) {
+	res := evaluateJSON(t, "Observation.id", testPatient)
+	assert.Equal(t, 0, res.Count())
+}
+
+func TestJSONAdapterNavigateCollection(t *testing.T) {
+	res := evaluateJSON(t, "Patient.name.given", testPatient)
+	if assert.Equal(t, 3, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Peter"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("James"), res.Get(1))
+		assertSystemEqual(t, hipathsys.NewString("Jim"), res.Get(2))
+	}
+}
+
+func TestJSONAdapterNavigateMissing(t *testing.T) {
+	res := evaluateJSON(t, "Patient.contact.name.family", testPatient)
+	assert.Equal(t, 0, res.Count())
+}
+
+func TestJSONAdapterBoolean(t *testing.T) {
+	res := evaluateJSON(t, "active = true", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterDate(t *testing.T) {
+	res := evaluateJSON(t, "birthDate < @1980-01-01", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+const testTypedPatient = `{
+  "resourceType": "Patient",
+  "identifier": [{"system": "http://example.org/ids", "value": "2020-01-01"}],
+  "telecom": [{"system": "phone", "value": "12:30:00"}],
+  "name": [{"family": "Smith", "period": {"start": "2010-02"}}],
+  "birthDate": "1980-05",
+  "contact": [{"name": {"family": "Doe"}}],
+  "unknown": "2020-01-01"
+}`
+
+func TestJSONAdapterDateByElementName(t *testing.T) {
+	res := evaluateJSON(t, "birthDate < @1990-01 and birthDate is FHIR.date and name.period.start < @2011-01T", testTypedPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterStringByElementName(t *testing.T) {
+	res := evaluateJSON(t, "identifier.value.length() = 10 and identifier.value.startsWith('2020') and "+
+		"telecom.value.startsWith('12') and unknown.startsWith('2020')", testTypedPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+	res = evaluateJSON(t, "identifier.value is FHIR.string", testTypedPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterComplexTypeByElementName(t *testing.T) {
+	res := evaluateJSON(t, "name.first() is HumanName and identifier.first() is FHIR.Identifier and "+
+		"telecom.first() is ContactPoint and name.period is Period and contact.first() is BackboneElement and "+
+		"contact.name is HumanName", testTypedPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+	res = evaluateJSON(t, "contact.name.family", testTypedPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Doe"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterDateTimeChoice(t *testing.T) {
+	res := evaluateJSON(t, "effective", testObservation)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Implements(t, (*hipathsys.DateTimeAccessor)(nil), res.Get(0))
+	}
+}
+
+func TestJSONAdapterDecimal(t *testing.T) {
+	res := evaluateJSON(t, "value.value", testObservation)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, "185.10", res.Get(0).(hipathsys.DecimalAccessor).String())
+	}
+}
+
+func TestJSONAdapterInteger(t *testing.T) {
+	res := evaluateJSON(t, "multipleBirth + 1", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewInteger(3), res.Get(0))
+	}
+}
+
+func TestJSONAdapterInteger64(t *testing.T) {
+	res := evaluateJSON(t, "value + 1", `{"resourceType": "Observation", "valueInteger64": "8000000000"}`)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Implements(t, (*hipathsys.LongAccessor)(nil), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewLong(8_000_000_001), res.Get(0))
+	}
+	res = evaluateJSON(t, "value is integer64", `{"resourceType": "Observation", "valueInteger64": 12}`)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterLargeInteger(t *testing.T) {
+	res := evaluateJSON(t, "size", `{"resourceType": "Attachment", "size": 8000000000}`)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Implements(t, (*hipathsys.LongAccessor)(nil), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewLong(8_000_000_000), res.Get(0))
+	}
+}
+
+func TestJSONAdapterChoiceType(t *testing.T) {
+	res := evaluateJSON(t, "value is Quantity", testObservation)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+	res = evaluateJSON(t, "value.ofType(FHIR.Quantity).unit", testObservation)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("lbs"), res.Get(0))
+	}
+	res = evaluateJSON(t, "value.as(string)", testObservation)
+	assert.Equal(t, 0, res.Count())
+}
+
+func TestJSONAdapterPrimitiveType(t *testing.T) {
+	res := evaluateJSON(t, "birthDate is FHIR.date", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+	res = evaluateJSON(t, "birthDate.as(FHIR.date)", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewDateYMD(1974, 12, 25), res.Get(0))
+	}
+	res = evaluateJSON(t, "name.use.ofType(string)", testPatient)
+	assert.Equal(t, 2, res.Count())
+	res = evaluateJSON(t, "('a' | 1).ofType('String')", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("a"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterResourceIs(t *testing.T) {
+	res := evaluateJSON(t, "is(DomainResource)", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterPrimitiveExtension(t *testing.T) {
+	res := evaluateJSON(t, "birthDate.extension.value", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Implements(t, (*hipathsys.DateTimeAccessor)(nil), res.Get(0))
+	}
+	res = evaluateJSON(t, "name.given.extension.value", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("ext"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterPrimitiveExtensionOnly(t *testing.T) {
+	res := evaluateJSON(t, "gender", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Nil(t, unwrapSystem(res.Get(0)))
+	}
+	res = evaluateJSON(t, "gender.extension.url", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("http://example.org/absent"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterChildren(t *testing.T) {
+	res := evaluateJSON(t, "name.first().children()", testPatient)
+	assert.Equal(t, 4, res.Count())
+	res = evaluateJSON(t, "children().count()", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewInteger(7), res.Get(0))
+	}
+}
+
+func TestJSONAdapterEqual(t *testing.T) {
+	res := evaluateJSON(t, "name.first() = name.first()", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+	res = evaluateJSON(t, "name.first() = name.last()", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.False, res.Get(0))
+	}
+}
+
+func TestJSONAdapterTypeSpecSystem(t *testing.T) {
+	a := NewJSONAdapter()
+	assert.Same(t, hipathsys.UndefinedTypeSpec, a.TypeSpec(hipathsys.NewString("test")))
+	assert.Same(t, hipathsys.UndefinedTypeSpec, a.TypeSpec(10))
+}
+
+func TestJSONAdapterCastToSystem(t *testing.T) {
+	a := NewJSONAdapter()
+	s := hipathsys.NewString("test")
+	res, err := a.CastToSystem(s)
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, s, res)
+
+	res, err = a.CastToSystem(map[string]interface{}{})
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestJSONAdapterUnsupportedValue(t *testing.T) {
+	a := NewJSONAdapter()
+	res, err := a.Navigate(map[string]interface{}{"value": 10}, "value")
+	assert.EqualError(t, err, "unsupported JSON value: int")
+	assert.Nil(t, res)
+}
+
+func TestJSONAdapterFloat(t *testing.T) {
+	a := NewJSONAdapter()
+	res, err := a.Navigate(map[string]interface{}{"value": 10.5, "count": 10.0}, "value")
+	assert.NoError(t, err, "no error expected")
+	assertSystemEqual(t, hipathsys.NewDecimalFloat64(10.5), res)
+
+	res, err = a.Navigate(map[string]interface{}{"count": 10.0}, "count")
+	assert.NoError(t, err, "no error expected")
+	assertSystemEqual(t, hipathsys.NewInteger(10), res)
+}
+
+func TestJSONPrimitiveAccessor(t *testing.T) {
+	a := NewJSONAdapter()
+	res, err := a.Navigate(parseTestJSON(t, testPatient), "birthDate")
+	assert.NoError(t, err, "no error expected")
+	if p, ok := res.(hipathsys.AnyAccessor).Source().(JSONPrimitiveAccessor); assert.True(t, ok) {
+		assert.Equal(t, "1974-12-25", p.JSONValue())
+		assert.Contains(t, p.JSONElement(), "extension")
+	}
+}
+
+func unwrapSystem(node interface{}) hipathsys.AnyAccessor {
+	if n, ok := node.(hipathsys.AnyAccessor); ok {
+		return n
+	}
+	return nil
+}
+
+func assertSystemEqual(t *testing.T, expected hipathsys.AnyAccessor, actual interface{}) {
+	assert.True(t, expected.Equal(actual), "expected %v, but was %v", expected, actual)
+}
+
+func TestJSONAdapterType(t *testing.T) {
+	res := evaluateJSON(t, "type().namespace | type().name | type().baseType", testPatient)
+	if assert.Equal(t, 3, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("FHIR"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("Patient"), res.Get(1))
+		assertSystemEqual(t, hipathsys.NewString("FHIR.DomainResource"), res.Get(2))
+	}
+}
+
+func TestJSONAdapterTypeElement(t *testing.T) {
+	res := evaluateJSON(t, "type().element.name", testPatient)
+	if assert.Equal(t, 6, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("active"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("birthDate"), res.Get(1))
+		assertSystemEqual(t, hipathsys.NewString("gender"), res.Get(2))
+	}
+	res = evaluateJSON(t, "type().element.where(name = 'name').select(type | isOneBased)", testPatient)
+	if assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("List<FHIR.HumanName>"), res.Get(0))
+		assertSystemEqual(t, hipathsys.False, res.Get(1))
+	}
+	res = evaluateJSON(t, "type().element.where(name = 'active').select(type | isOneBased)", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("FHIR.boolean"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterTypeChoice(t *testing.T) {
+	res := evaluateJSON(t, "value.type().name | value.type().baseType", testObservation)
+	if assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Quantity"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("FHIR.Element"), res.Get(1))
+	}
+}
+
+func TestJSONAdapterClassInfoPrimitive(t *testing.T) {
+	a := NewJSONAdapter().(hipathsys.ClassInfoAdapter)
+	assert.Nil(t, a.ClassInfo(hipathsys.NewString("test")))
+}
+
+const testContainedPatient = `{
+  "resourceType": "Patient",
+  "id": "container",
+  "contained": [
+    {
+      "resourceType": "Organization",
+      "id": "org1",
+      "name": "Test Organization"
+    }
+  ],
+  "managingOrganization": {
+    "reference": "#org1"
+  }
+}`
+
+const testBundle = `{
+  "resourceType": "Bundle",
+  "id": "bundle",
+  "type": "collection",
+  "entry": [
+    {
+      "resource": {
+        "resourceType": "Patient",
+        "id": "entry1",
+        "contained": [
+          {
+            "resourceType": "Organization",
+            "id": "org2"
+          }
+        ]
+      }
+    }
+  ]
+}`
+
+func TestJSONAdapterResource(t *testing.T) {
+	a := NewJSONAdapter().(hipathsys.ResourceAdapter)
+	assert.True(t, a.Resource(parseTestJSON(t, testPatient)))
+	assert.True(t, a.Resource(&jsonObject{value: map[string]interface{}{"resourceType": "Patient"}}))
+	assert.False(t, a.Resource(map[string]interface{}{"id": "test"}))
+	assert.False(t, a.Resource(hipathsys.NewString("test")))
+}
+
+func TestJSONAdapterContainedResource(t *testing.T) {
+	root := parseTestJSON(t, testContainedPatient)
+	org := root.(map[string]interface{})["contained"].([]interface{})[0]
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).RootResource(root).Node(org).Build()
+
+	res, err := gohipath.Execute(ctx, "%resource.id | %rootResource.id | %context.name", org)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 3, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("org1"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("container"), res.Get(1))
+		assertSystemEqual(t, hipathsys.NewString("Test Organization"), res.Get(2))
+	}
+}
+
+func TestJSONAdapterBundleEntryResource(t *testing.T) {
+	root := parseTestJSON(t, testBundle)
+	entry := root.(map[string]interface{})["entry"].([]interface{})[0].(map[string]interface{})
+	patient := entry["resource"]
+	org := patient.(map[string]interface{})["contained"].([]interface{})[0]
+
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).RootResource(root).Node(patient).Build()
+	res, err := gohipath.Execute(ctx, "%resource.id | %rootResource.id", patient)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("entry1"), res.Get(0))
+	}
+
+	ctx = hipathsys.NewContextBuilder(NewJSONAdapter()).RootResource(root).Node(org).Build()
+	res, err = gohipath.Execute(ctx, "%resource.id | %rootResource.id", org)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("org2"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("entry1"), res.Get(1))
+	}
+}
+
+func TestJSONAdapterResourceNodeNotFound(t *testing.T) {
+	root := parseTestJSON(t, testPatient)
+	node := parseTestJSON(t, testObservation)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).RootResource(root).Node(node).Build()
+
+	res, err := gohipath.Execute(ctx, "%resource.resourceType | %rootResource.resourceType", node)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Observation"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("Patient"), res.Get(1))
+	}
+}
+
+func TestJSONAdapterResourceEnvVarWithoutNode(t *testing.T) {
+	root := parseTestJSON(t, testPatient)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Build()
+
+	res, err := gohipath.Execute(ctx, "%context.id | %resource.id | %rootResource.id", root)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("example"), res.Get(0))
+	}
+
+	name := root.(map[string]interface{})["name"].([]interface{})[0]
+	res, err = gohipath.Execute(ctx, "%context.exists() and %resource.empty() and %rootResource.empty()", name)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterResourceEnvVar(t *testing.T) {
+	res := evaluateJSON(t, "%resource.id = 'example' and %rootResource.id = 'example' and "+
+		"%context.id = 'example'", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.True, res.Get(0))
+	}
+}
+
+const testReferenceBundle = `{
+  "resourceType": "Bundle",
+  "id": "bundle",
+  "type": "collection",
+  "entry": [
+    {
+      "fullUrl": "http://example.com/fhir/Patient/p1",
+      "resource": {
+        "resourceType": "Patient",
+        "id": "p1",
+        "contained": [
+          {
+            "resourceType": "Organization",
+            "id": "org",
+            "name": "Organization P1"
+          }
+        ],
+        "managingOrganization": {
+          "reference": "#org"
+        },
+        "generalPractitioner": [
+          {
+            "reference": "Practitioner/pr1/_history/2"
+          },
+          {
+            "reference": "urn:uuid:4f7c997a-d6a4-11ea-814c-b5baa79e0f4a"
+          },
+          {
+            "reference": "Practitioner/unknown"
+          }
+        ]
+      }
+    },
+    {
+      "fullUrl": "http://example.com/fhir/Patient/p2",
+      "resource": {
+        "resourceType": "Patient",
+        "id": "p2",
+        "contained": [
+          {
+            "resourceType": "Organization",
+            "id": "org",
+            "name": "Organization P2"
+          }
+        ],
+        "managingOrganization": {
+          "reference": "#org"
+        },
+        "link": [
+          {
+            "other": {
+              "reference": "http://example.com/fhir/Patient/p1"
+            }
+          }
+        ]
+      }
+    },
+    {
+      "fullUrl": "http://example.com/fhir/Practitioner/pr1",
+      "resource": {
+        "resourceType": "Practitioner",
+        "id": "pr1"
+      }
+    },
+    {
+      "fullUrl": "urn:uuid:4f7c997a-d6a4-11ea-814c-b5baa79e0f4a",
+      "resource": {
+        "resourceType": "Practitioner",
+        "id": "pr2"
+      }
+    }
+  ]
+}`
+
+func TestJSONAdapterResolveContained(t *testing.T) {
+	res := evaluateJSON(t, "managingOrganization.resolve().name", testContainedPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Test Organization"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterResolveContainedContainer(t *testing.T) {
+	root := parseTestJSON(t, testContainedPatient)
+	org := root.(map[string]interface{})["contained"].([]interface{})[0]
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).RootResource(root).Node(org).Build()
+
+	res, err := gohipath.Execute(ctx, "'#'.resolve().id", org)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("container"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterResolveContainedBundleEntries(t *testing.T) {
+	res := evaluateJSON(t, "Bundle.entry.resource.managingOrganization.resolve().name", testReferenceBundle)
+	if assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Organization P1"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("Organization P2"), res.Get(1))
+	}
+}
+
+func TestJSONAdapterResolveBundle(t *testing.T) {
+	res := evaluateJSON(t, "Bundle.entry.resource.generalPractitioner.resolve().id", testReferenceBundle)
+	if assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("pr1"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("pr2"), res.Get(1))
+	}
+
+	res = evaluateJSON(t, "Bundle.entry.resource.link.other.resolve().id", testReferenceBundle)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("p1"), res.Get(0))
+	}
+
+	res = evaluateJSON(t, "('Patient/p2' | 'Practitioner/pr2' | 'Patient/p3').resolve().id", testReferenceBundle)
+	if assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("p2"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("pr2"), res.Get(1))
+	}
+}
+
+func TestJSONAdapterResolveBundleEntryResolver(t *testing.T) {
+	root := parseTestJSON(t, testReferenceBundle)
+	patient := root.(map[string]interface{})["entry"].([]interface{})[0].(map[string]interface{})["resource"]
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).RootResource(root).Node(patient).
+		ReferenceResolver(hipathsys.NewChainedReferenceResolver(
+			hipathsys.NewContainedReferenceResolver(), hipathsys.NewBundleReferenceResolver(root))).
+		Build()
+
+	res, err := gohipath.Execute(ctx, "(managingOrganization | generalPractitioner).resolve().id", patient)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 3, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("org"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("pr1"), res.Get(1))
+		assertSystemEqual(t, hipathsys.NewString("pr2"), res.Get(2))
+	}
+}
+
+func TestJSONAdapterResolveMap(t *testing.T) {
+	node := parseTestJSON(t, testContainedPatient)
+	practitioner := parseTestJSON(t, `{"resourceType": "Practitioner", "id": "pr1"}`)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Node(node).
+		ReferenceResolver(hipathsys.NewMapReferenceResolver(map[string]interface{}{"Practitioner/pr1": practitioner})).
+		Build()
+
+	res, err := gohipath.Execute(ctx, "('Practitioner/pr1' | managingOrganization).resolve().ofType(FHIR.Resource).id", node)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("pr1"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("org1"), res.Get(1))
+	}
+}
+
+const testMultiServerBundle = `{
+  "resourceType": "Bundle",
+  "type": "collection",
+  "entry": [
+    {
+      "fullUrl": "http://a.example.com/fhir/Patient/1",
+      "resource": {"resourceType": "Patient", "id": "1", "gender": "female"}
+    },
+    {
+      "fullUrl": "http://b.example.com/fhir/Patient/1",
+      "resource": {"resourceType": "Patient", "id": "1", "gender": "male"}
+    },
+    {
+      "fullUrl": "http://b.example.com/fhir/Observation/o1",
+      "resource": {"resourceType": "Observation", "id": "o1", "subject": {"reference": "Patient/1"}}
+    },
+    {
+      "fullUrl": "http://a.example.com/fhir/Observation/o2",
+      "resource": {"resourceType": "Observation", "id": "o2", "subject": {"reference": "Patient/1"}}
+    }
+  ]
+}`
+
+func TestJSONAdapterResolveBundleBaseURL(t *testing.T) {
+	res := evaluateJSON(t, "Bundle.entry.resource.ofType(Observation).subject.resolve().gender", testMultiServerBundle)
+	if assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("male"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("female"), res.Get(1))
+	}
+}
+
+const testMaskedObservation = `{
+  "resourceType": "Observation",
+  "status": "final",
+  "_valueString": {
+    "extension": [{"url": "http://hl7.org/fhir/StructureDefinition/data-absent-reason", "valueCode": "masked"}]
+  }
+}`
+
+func TestJSONAdapterPrimitiveValue(t *testing.T) {
+	a := NewJSONAdapter().(hipathsys.ElementAdapter)
+	node := parseTestJSON(t, testPatient)
+
+	birthDate, err := a.(hipathsys.ModelAdapter).Navigate(node, "birthDate")
+	assert.NoError(t, err, "no error expected")
+	v, found := a.PrimitiveValue(birthDate)
+	assert.True(t, found)
+	assert.Same(t, birthDate, v)
+
+	gender, err := a.(hipathsys.ModelAdapter).Navigate(node, "gender")
+	assert.NoError(t, err, "no error expected")
+	v, found = a.PrimitiveValue(gender)
+	assert.False(t, found)
+	assert.Nil(t, v)
+
+	v, found = a.PrimitiveValue(hipathsys.NewString("test"))
+	assert.False(t, found)
+	assert.Nil(t, v)
+
+	v, found = a.PrimitiveValue(node)
+	assert.False(t, found)
+	assert.Nil(t, v)
+}
+
+func TestJSONAdapterExtensions(t *testing.T) {
+	a := NewJSONAdapter().(hipathsys.ElementAdapter)
+	node := parseTestJSON(t, testPatient)
+
+	birthDate, err := a.(hipathsys.ModelAdapter).Navigate(node, "birthDate")
+	assert.NoError(t, err, "no error expected")
+	res, err := a.Extensions(birthDate)
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, res) {
+		assert.Equal(t, 1, res.Count())
+	}
+
+	res, err = a.Extensions(node)
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, res) {
+		assert.Equal(t, 0, res.Count())
+	}
+
+	res, err = a.Extensions(hipathsys.NewString("test"))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+
+	res, err = a.Extensions(hipathsys.NewColWithItem(a.(hipathsys.ModelAdapter), node))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res)
+}
+
+func TestJSONAdapterExtensionFunc(t *testing.T) {
+	res := evaluateJSON(t, "Patient.birthDate.extension('http://hl7.org/fhir/StructureDefinition/patient-birthTime').value", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		expected, _ := hipathsys.ParseDateTime("1974-12-25T14:35:45-05:00")
+		assertSystemEqual(t, expected, res.Get(0))
+	}
+
+	res = evaluateJSON(t, "Patient.name.given.extension('http://example.org/x').value", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("ext"), res.Get(0))
+	}
+
+	res = evaluateJSON(t, "Patient.birthDate.extension(%`ext-patient-birthTime`).exists()", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.True, res.Get(0))
+	}
+
+	res = evaluateJSON(t, "Patient.extension('http://example.org/x')", testPatient)
+	assert.Equal(t, 0, res.Count())
+}
+
+func TestJSONAdapterHasValueFunc(t *testing.T) {
+	res := evaluateJSON(t, "Patient.birthDate.hasValue() and Patient.active.hasValue() and "+
+		"Patient.gender.hasValue() = false and Patient.name.hasValue() = false and "+
+		"Patient.name.given.first().hasValue() and Patient.hasValue() = false", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterGetValueFunc(t *testing.T) {
+	res := evaluateJSON(t, "Patient.birthDate.getValue() | Patient.gender.getValue() | Patient.name.getValue()", testPatient)
+	if assert.Equal(t, 1, res.Count()) {
+		expected, _ := hipathsys.ParseDate("1974-12-25")
+		assertSystemEqual(t, expected, res.Get(0))
+	}
+}
+
+func TestJSONAdapterOfTypePrimitiveWithoutValue(t *testing.T) {
+	res := evaluateJSON(t, "Observation.value.ofType(FHIR.string).extension("+
+		"'http://hl7.org/fhir/StructureDefinition/data-absent-reason').value", testMaskedObservation)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("masked"), res.Get(0))
+	}
+
+	res = evaluateJSON(t, "Observation.value.ofType(string).hasValue() | Observation.value.ofType(FHIR.integer).exists()",
+		testMaskedObservation)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.False, res.Get(0))
+	}
+}
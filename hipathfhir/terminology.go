@@ -0,0 +1,687 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathfhir
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const versionSeparator = "|"
+
+type jsonTerminologyHeader struct {
+	ResourceType string `json:"resourceType"`
+}
+
+type jsonTerminologyResource struct {
+	ResourceType string                       `json:"resourceType"`
+	URL          string                       `json:"url"`
+	Version      string                       `json:"version"`
+	Name         string                       `json:"name"`
+	Concept      []jsonCodeSystemConcept      `json:"concept"`
+	Compose      *jsonValueSetCompose         `json:"compose"`
+	Expansion    *jsonValueSetExpansion       `json:"expansion"`
+	Group        []jsonConceptMapGroup        `json:"group"`
+	Entry        []jsonTerminologyBundleEntry `json:"entry"`
+}
+
+type jsonTerminologyBundleEntry struct {
+	Resource json.RawMessage `json:"resource"`
+}
+
+type jsonCodeSystemConcept struct {
+	Code     string                   `json:"code"`
+	Display  string                   `json:"display"`
+	Concept  []jsonCodeSystemConcept  `json:"concept"`
+	Property []jsonCodeSystemProperty `json:"property"`
+}
+
+type jsonCodeSystemProperty struct {
+	Code      string `json:"code"`
+	ValueCode string `json:"valueCode"`
+}
+
+type jsonValueSetCompose struct {
+	Include []jsonValueSetInclude `json:"include"`
+}
+
+type jsonValueSetInclude struct {
+	System   string                `json:"system"`
+	Concept  []jsonValueSetConcept `json:"concept"`
+	Filter   []json.RawMessage     `json:"filter"`
+	ValueSet []string              `json:"valueSet"`
+}
+
+type jsonValueSetConcept struct {
+	Code    string `json:"code"`
+	Display string `json:"display"`
+}
+
+type jsonValueSetExpansion struct {
+	Contains []jsonValueSetContains `json:"contains"`
+}
+
+type jsonValueSetContains struct {
+	System   string                 `json:"system"`
+	Code     string                 `json:"code"`
+	Display  string                 `json:"display"`
+	Contains []jsonValueSetContains `json:"contains"`
+}
+
+type jsonConceptMapGroup struct {
+	Source  string                  `json:"source"`
+	Target  string                  `json:"target"`
+	Element []jsonConceptMapElement `json:"element"`
+}
+
+type jsonConceptMapElement struct {
+	Code   string                 `json:"code"`
+	Target []jsonConceptMapTarget `json:"target"`
+}
+
+type jsonConceptMapTarget struct {
+	Code         string `json:"code"`
+	Display      string `json:"display"`
+	Equivalence  string `json:"equivalence"`
+	Relationship string `json:"relationship"`
+}
+
+type memoryCodeSystem struct {
+	url      string
+	name     string
+	codes    []string
+	parents  map[string][]string
+	displays map[string]string
+}
+
+type memoryValueSet struct {
+	url      string
+	codings  []hipathsys.Coding
+	displays map[hipathsys.Coding]string
+	systems  []string
+}
+
+type memoryConceptMap struct {
+	url    string
+	groups []jsonConceptMapGroup
+}
+
+type MemoryTerminologyProvider struct {
+	mutex       sync.RWMutex
+	codeSystems map[string]*memoryCodeSystem
+	valueSets   map[string]*memoryValueSet
+	conceptMaps map[string]*memoryConceptMap
+}
+
+func NewMemoryTerminologyProvider() *MemoryTerminologyProvider {
+	return &MemoryTerminologyProvider{
+		codeSystems: make(map[string]*memoryCodeSystem),
+		valueSets:   make(map[string]*memoryValueSet),
+		conceptMaps: make(map[string]*memoryConceptMap),
+	}
+}
+
+func (p *MemoryTerminologyProvider) LoadFile(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := p.Load(f); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func (p *MemoryTerminologyProvider) LoadJSON(data []byte) error {
+	return p.Load(bytes.NewReader(data))
+}
+
+func (p *MemoryTerminologyProvider) Load(r io.Reader) error {
+	var data json.RawMessage
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.load(data)
+}
+
+func (p *MemoryTerminologyProvider) load(data json.RawMessage) error {
+	var header jsonTerminologyHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+	switch header.ResourceType {
+	case "Bundle", "CodeSystem", "ValueSet", "ConceptMap":
+	default:
+		return fmt.Errorf("unsupported terminology resource type: %s", header.ResourceType)
+	}
+
+	var resource jsonTerminologyResource
+	if err := json.Unmarshal(data, &resource); err != nil {
+		return err
+	}
+	switch resource.ResourceType {
+	case "Bundle":
+		for _, entry := range resource.Entry {
+			if len(entry.Resource) == 0 {
+				continue
+			}
+			if err := p.load(entry.Resource); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "CodeSystem":
+		return p.loadCodeSystem(&resource)
+	case "ValueSet":
+		return p.loadValueSet(&resource)
+	}
+	return p.loadConceptMap(&resource)
+}
+
+func (p *MemoryTerminologyProvider) loadCodeSystem(resource *jsonTerminologyResource) error {
+	if len(resource.URL) == 0 {
+		return fmt.Errorf("code system has no URL")
+	}
+
+	cs := &memoryCodeSystem{
+		url:      resource.URL,
+		name:     resource.Name,
+		parents:  make(map[string][]string),
+		displays: make(map[string]string),
+	}
+	cs.addConcepts(resource.Concept, "")
+	p.codeSystems[resource.URL] = cs
+	return nil
+}
+
+func (p *MemoryTerminologyProvider) loadValueSet(resource *jsonTerminologyResource) error {
+	if len(resource.URL) == 0 {
+		return fmt.Errorf("value set has no URL")
+	}
+
+	vs := &memoryValueSet{url: resource.URL, displays: make(map[hipathsys.Coding]string)}
+	if resource.Expansion != nil {
+		vs.addContains(resource.Expansion.Contains)
+	} else if resource.Compose != nil {
+		for _, include := range resource.Compose.Include {
+			if len(include.Filter) > 0 || len(include.ValueSet) > 0 {
+				return fmt.Errorf("value set must be expanded: %s", resource.URL)
+			}
+			if len(include.Concept) == 0 {
+				vs.systems = append(vs.systems, include.System)
+			}
+			for _, concept := range include.Concept {
+				vs.add(hipathsys.Coding{System: include.System, Code: concept.Code}, concept.Display)
+			}
+		}
+	}
+
+	p.valueSets[resource.URL] = vs
+	if len(resource.Version) > 0 {
+		p.valueSets[resource.URL+versionSeparator+resource.Version] = vs
+	}
+	return nil
+}
+
+func (p *MemoryTerminologyProvider) loadConceptMap(resource *jsonTerminologyResource) error {
+	if len(resource.URL) == 0 {
+		return fmt.Errorf("concept map has no URL")
+	}
+
+	cm := &memoryConceptMap{url: resource.URL, groups: resource.Group}
+	p.conceptMaps[resource.URL] = cm
+	if len(resource.Version) > 0 {
+		p.conceptMaps[resource.URL+versionSeparator+resource.Version] = cm
+	}
+	return nil
+}
+
+func (p *MemoryTerminologyProvider) MemberOf(valueSet string, coding hipathsys.Coding) (bool, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	vs, err := p.valueSet(valueSet)
+	if err != nil {
+		return false, err
+	}
+	_, member := p.member(vs, coding)
+	return member, nil
+}
+
+func (p *MemoryTerminologyProvider) Subsumes(coding hipathsys.Coding, other hipathsys.Coding) (bool, error) {
+	system := coding.System
+	if len(system) == 0 {
+		system = other.System
+	} else if len(other.System) > 0 && other.System != system {
+		return false, nil
+	}
+	if coding.Code == other.Code {
+		return true, nil
+	}
+	if len(system) == 0 {
+		return false, fmt.Errorf("code system has not been specified")
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	cs, err := p.codeSystem(system)
+	if err != nil {
+		return false, err
+	}
+	return cs.subsumes(coding.Code, other.Code), nil
+}
+
+func (p *MemoryTerminologyProvider) Expand(valueSet string, params url.Values) (interface{}, error) {
+	count := -1
+	if value := params.Get("count"); len(value) > 0 {
+		c, err := strconv.Atoi(value)
+		if err != nil || c < 0 {
+			return nil, fmt.Errorf("invalid count: %s", value)
+		}
+		count = c
+	}
+	filter := strings.ToLower(params.Get("filter"))
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	vs, err := p.valueSet(valueSet)
+	if err != nil {
+		return nil, err
+	}
+
+	var contains []interface{}
+	add := func(coding hipathsys.Coding, display string) {
+		if len(filter) > 0 && !strings.Contains(strings.ToLower(coding.Code), filter) &&
+			!strings.Contains(strings.ToLower(display), filter) {
+			return
+		}
+		contains = append(contains, codingObject(coding, display))
+	}
+	for _, coding := range vs.codings {
+		add(coding, p.display(vs, coding))
+	}
+	for _, system := range vs.systems {
+		cs, err := p.codeSystem(system)
+		if err != nil {
+			return nil, err
+		}
+		for _, code := range cs.codes {
+			add(hipathsys.Coding{System: system, Code: code}, cs.displays[code])
+		}
+	}
+
+	total := len(contains)
+	if count >= 0 && count < len(contains) {
+		contains = contains[:count]
+	}
+	expansion := map[string]interface{}{"total": json.Number(strconv.Itoa(total))}
+	if len(contains) > 0 {
+		expansion["contains"] = contains
+	}
+	return map[string]interface{}{
+		"resourceType": "ValueSet",
+		"url":          vs.url,
+		"expansion":    expansion,
+	}, nil
+}
+
+func (p *MemoryTerminologyProvider) Lookup(coding hipathsys.Coding, _ url.Values) (interface{}, error) {
+	if len(coding.System) == 0 {
+		return nil, fmt.Errorf("code system has not been specified")
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	cs, err := p.codeSystem(coding.System)
+	if err != nil {
+		return nil, err
+	}
+	if !cs.contains(coding.Code) {
+		return nil, fmt.Errorf("code is unknown: %s%s%s", coding.System, versionSeparator, coding.Code)
+	}
+
+	name := cs.name
+	if len(name) == 0 {
+		name = cs.url
+	}
+	parameters := []interface{}{
+		parameter("name", "String", name),
+		parameter("code", "Code", coding.Code),
+		parameter("system", "Uri", coding.System),
+	}
+	if display := cs.displays[coding.Code]; len(display) > 0 {
+		parameters = append(parameters, parameter("display", "String", display))
+	}
+	for _, parent := range cs.parents[coding.Code] {
+		parameters = append(parameters, map[string]interface{}{
+			"name": "property",
+			"part": []interface{}{
+				parameter("code", "Code", "parent"),
+				parameter("value", "Code", parent),
+			},
+		})
+	}
+	return parametersObject(parameters), nil
+}
+
+func (p *MemoryTerminologyProvider) ValidateVS(valueSet string, codings []hipathsys.Coding, _ url.Values) (interface{}, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	vs, err := p.valueSet(valueSet)
+	if err != nil {
+		return nil, err
+	}
+	for _, coding := range codings {
+		if display, member := p.member(vs, coding); member {
+			return validationResult(true, display, ""), nil
+		}
+	}
+	return validationResult(false, "", fmt.Sprintf("code is not in value set %s", valueSet)), nil
+}
+
+func (p *MemoryTerminologyProvider) ValidateCS(codeSystem string, codings []hipathsys.Coding, _ url.Values) (interface{}, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	cs, err := p.codeSystem(codeSystem)
+	if err != nil {
+		return nil, err
+	}
+	for _, coding := range codings {
+		if (len(coding.System) == 0 || coding.System == cs.url) && cs.contains(coding.Code) {
+			return validationResult(true, cs.displays[coding.Code], ""), nil
+		}
+	}
+	return validationResult(false, "", fmt.Sprintf("code is not in code system %s", codeSystem)), nil
+}
+
+func (p *MemoryTerminologyProvider) Subsumption(system string, coding hipathsys.Coding, other hipathsys.Coding, _ url.Values) (string, error) {
+	for _, c := range []hipathsys.Coding{coding, other} {
+		if len(c.System) > 0 && c.System != system {
+			return "", fmt.Errorf("coding is not from code system %s: %s", system, c.System)
+		}
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	cs, err := p.codeSystem(system)
+	if err != nil {
+		return "", err
+	}
+	for _, code := range []string{coding.Code, other.Code} {
+		if !cs.contains(code) {
+			return "", fmt.Errorf("code is unknown: %s%s%s", system, versionSeparator, code)
+		}
+	}
+
+	switch {
+	case coding.Code == other.Code:
+		return "equivalent", nil
+	case cs.subsumes(coding.Code, other.Code):
+		return "subsumes", nil
+	case cs.subsumes(other.Code, coding.Code):
+		return "subsumed-by", nil
+	}
+	return "not-subsumed", nil
+}
+
+func (p *MemoryTerminologyProvider) Translate(conceptMap string, codings []hipathsys.Coding, _ url.Values) (interface{}, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	cm, found := p.conceptMaps[conceptMap]
+	if !found {
+		if i := strings.Index(conceptMap, versionSeparator); i >= 0 {
+			cm, found = p.conceptMaps[conceptMap[:i]]
+		}
+		if !found {
+			return nil, fmt.Errorf("concept map is unknown: %s", conceptMap)
+		}
+	}
+
+	result := false
+	var matches []interface{}
+	for _, coding := range codings {
+		for _, group := range cm.groups {
+			if len(coding.System) > 0 && len(group.Source) > 0 && coding.System != group.Source {
+				continue
+			}
+			for _, element := range group.Element {
+				if element.Code != coding.Code {
+					continue
+				}
+				for _, target := range element.Target {
+					relationship, relationshipType := target.Equivalence, "equivalence"
+					if len(relationship) == 0 {
+						relationship, relationshipType = target.Relationship, "relationship"
+					}
+					switch relationship {
+					case "unmatched", "disjoint", "not-related-to":
+					default:
+						result = true
+					}
+
+					parts := []interface{}{parameter(relationshipType, "Code", relationship)}
+					if len(target.Code) > 0 {
+						parts = append(parts, parameter("concept", "Coding", codingObject(
+							hipathsys.Coding{System: group.Target, Code: target.Code}, target.Display)))
+					}
+					matches = append(matches, map[string]interface{}{"name": "match", "part": parts})
+				}
+			}
+		}
+	}
+
+	parameters := []interface{}{parameter("result", "Boolean", result)}
+	if !result {
+		parameters = append(parameters, parameter("message", "String",
+			fmt.Sprintf("no translation found in concept map %s", conceptMap)))
+	}
+	return parametersObject(append(parameters, matches...)), nil
+}
+
+func (p *MemoryTerminologyProvider) codeSystem(system string) (*memoryCodeSystem, error) {
+	cs, found := p.codeSystems[system]
+	if !found {
+		if i := strings.Index(system, versionSeparator); i >= 0 {
+			cs, found = p.codeSystems[system[:i]]
+		}
+		if !found {
+			return nil, fmt.Errorf("code system is unknown: %s", system)
+		}
+	}
+	return cs, nil
+}
+
+func (p *MemoryTerminologyProvider) valueSet(valueSet string) (*memoryValueSet, error) {
+	vs, found := p.valueSets[valueSet]
+	if !found {
+		if i := strings.Index(valueSet, versionSeparator); i >= 0 {
+			vs, found = p.valueSets[valueSet[:i]]
+		}
+		if !found {
+			return nil, fmt.Errorf("value set is unknown: %s", valueSet)
+		}
+	}
+	return vs, nil
+}
+
+func (p *MemoryTerminologyProvider) member(vs *memoryValueSet, coding hipathsys.Coding) (string, bool) {
+	for _, c := range vs.codings {
+		if c.Code == coding.Code && (len(coding.System) == 0 || c.System == coding.System) {
+			return p.display(vs, c), true
+		}
+	}
+	for _, system := range vs.systems {
+		if len(coding.System) == 0 || coding.System == system {
+			if cs, found := p.codeSystems[system]; found && cs.contains(coding.Code) {
+				return cs.displays[coding.Code], true
+			}
+		}
+	}
+	return "", false
+}
+
+func (p *MemoryTerminologyProvider) display(vs *memoryValueSet, coding hipathsys.Coding) string {
+	if display := vs.displays[coding]; len(display) > 0 {
+		return display
+	}
+	if cs, found := p.codeSystems[coding.System]; found {
+		return cs.displays[coding.Code]
+	}
+	return ""
+}
+
+func (cs *memoryCodeSystem) addConcepts(concepts []jsonCodeSystemConcept, parent string) {
+	for _, concept := range concepts {
+		if !cs.contains(concept.Code) {
+			cs.codes = append(cs.codes, concept.Code)
+		}
+		if len(concept.Display) > 0 {
+			cs.displays[concept.Code] = concept.Display
+		}
+		cs.addParent(concept.Code, parent)
+		for _, property := range concept.Property {
+			switch property.Code {
+			case "parent":
+				cs.addParent(concept.Code, property.ValueCode)
+			case "child":
+				cs.addParent(property.ValueCode, concept.Code)
+			}
+		}
+		cs.addConcepts(concept.Concept, concept.Code)
+	}
+}
+
+func (cs *memoryCodeSystem) addParent(code string, parent string) {
+	parents := cs.parents[code]
+	if len(parent) > 0 {
+		for _, p := range parents {
+			if p == parent {
+				return
+			}
+		}
+		parents = append(parents, parent)
+	}
+	cs.parents[code] = parents
+}
+
+func (cs *memoryCodeSystem) contains(code string) bool {
+	_, found := cs.parents[code]
+	return found
+}
+
+func (cs *memoryCodeSystem) subsumes(code string, other string) bool {
+	visited := make(map[string]bool)
+	pending := []string{other}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for _, parent := range cs.parents[current] {
+			if parent == code {
+				return true
+			}
+			if !visited[parent] {
+				visited[parent] = true
+				pending = append(pending, parent)
+			}
+		}
+	}
+	return false
+}
+
+func (vs *memoryValueSet) add(coding hipathsys.Coding, display string) {
+	if _, found := vs.displays[coding]; !found {
+		vs.codings = append(vs.codings, coding)
+	}
+	vs.displays[coding] = display
+}
+
+func (vs *memoryValueSet) addContains(contains []jsonValueSetContains) {
+	for _, c := range contains {
+		if len(c.Code) > 0 {
+			vs.add(hipathsys.Coding{System: c.System, Code: c.Code}, c.Display)
+		}
+		vs.addContains(c.Contains)
+	}
+}
+
+func parametersObject(parameters []interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"resourceType": "Parameters",
+		"parameter":    parameters,
+	}
+}
+
+func parameter(name string, valueType string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":              name,
+		"value" + valueType: value,
+	}
+}
+
+func validationResult(result bool, display string, message string) map[string]interface{} {
+	parameters := []interface{}{parameter("result", "Boolean", result)}
+	if len(display) > 0 {
+		parameters = append(parameters, parameter("display", "String", display))
+	}
+	if len(message) > 0 {
+		parameters = append(parameters, parameter("message", "String", message))
+	}
+	return parametersObject(parameters)
+}
+
+func codingObject(coding hipathsys.Coding, display string) map[string]interface{} {
+	obj := map[string]interface{}{"code": coding.Code}
+	if len(coding.System) > 0 {
+		obj["system"] = coding.System
+	}
+	if len(display) > 0 {
+		obj["display"] = display
+	}
+	return obj
+}
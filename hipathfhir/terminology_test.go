@@ -0,0 +1,595 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathfhir
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/healthiop/hipath"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+const testConditionSystem = "http://example.org/fhir/CodeSystem/conditions"
+
+const testCondition = `{
+  "resourceType": "Condition",
+  "id": "example",
+  "code": {
+    "coding": [
+      {
+        "system": "http://example.org/fhir/CodeSystem/other",
+        "code": "x"
+      },
+      {
+        "system": "http://example.org/fhir/CodeSystem/conditions",
+        "code": "diabetes-type-2"
+      }
+    ]
+  },
+  "clinicalStatus": {
+    "coding": [
+      {
+        "system": "http://terminology.hl7.org/CodeSystem/condition-clinical",
+        "code": "active"
+      }
+    ]
+  }
+}`
+
+func newTestTerminologyProvider(t *testing.T) *MemoryTerminologyProvider {
+	p := NewMemoryTerminologyProvider()
+	if err := p.LoadFile("testdata/terminology.json"); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func testCoding(code string) hipathsys.Coding {
+	return hipathsys.Coding{System: testConditionSystem, Code: code}
+}
+
+func TestMemoryTerminologyProviderLoadFileNotFound(t *testing.T) {
+	err := NewMemoryTerminologyProvider().LoadFile("testdata/unknown.json")
+	assert.Error(t, err, "error expected")
+}
+
+func TestMemoryTerminologyProviderLoadInvalidJSON(t *testing.T) {
+	err := NewMemoryTerminologyProvider().LoadJSON([]byte(`{"resourceType": `))
+	assert.Error(t, err, "error expected")
+}
+
+func TestMemoryTerminologyProviderLoadUnsupportedResource(t *testing.T) {
+	err := NewMemoryTerminologyProvider().LoadJSON([]byte(testPatient))
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "unsupported terminology resource type: Patient", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderLoadBundleEntryError(t *testing.T) {
+	err := NewMemoryTerminologyProvider().LoadJSON([]byte(
+		`{"resourceType": "Bundle", "entry": [{}, {"resource": {"resourceType": "CodeSystem"}}]}`))
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "code system has no URL", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderLoadValueSetNoURL(t *testing.T) {
+	err := NewMemoryTerminologyProvider().LoadJSON([]byte(`{"resourceType": "ValueSet"}`))
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "value set has no URL", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderLoadValueSetFilter(t *testing.T) {
+	err := NewMemoryTerminologyProvider().LoadJSON([]byte(`{"resourceType": "ValueSet", ` +
+		`"url": "http://example.org/vs", "compose": {"include": [{"system": "http://example.org/cs", ` +
+		`"filter": [{"property": "concept", "op": "is-a", "value": "x"}]}]}}`))
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "value set must be expanded: http://example.org/vs", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderMemberOf(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+	tests := []struct {
+		valueSet string
+		coding   hipathsys.Coding
+		member   bool
+	}{
+		{"http://example.org/fhir/ValueSet/diabetes", testCoding("diabetes"), true},
+		{"http://example.org/fhir/ValueSet/diabetes", testCoding("diabetes-type-1"), true},
+		{"http://example.org/fhir/ValueSet/diabetes", testCoding("hypertension"), false},
+		{"http://example.org/fhir/ValueSet/diabetes", hipathsys.Coding{Code: "diabetes-type-2"}, true},
+		{"http://example.org/fhir/ValueSet/diabetes", hipathsys.Coding{Code: "hypertension"}, false},
+		{"http://example.org/fhir/ValueSet/diabetes", hipathsys.Coding{System: "http://other", Code: "diabetes"}, false},
+		{"http://example.org/fhir/ValueSet/diabetes|1.0.0", testCoding("diabetes"), true},
+		{"http://example.org/fhir/ValueSet/diabetes|2.0.0", testCoding("diabetes"), true},
+		{"http://example.org/fhir/ValueSet/conditions", testCoding("gestational-diabetes"), true},
+		{"http://example.org/fhir/ValueSet/conditions", hipathsys.Coding{Code: "hypertension"}, true},
+		{"http://example.org/fhir/ValueSet/conditions", testCoding("unknown"), false},
+		{"http://example.org/fhir/ValueSet/conditions", hipathsys.Coding{System: "http://other", Code: "disorder"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.valueSet+" "+tt.coding.Code, func(t *testing.T) {
+			member, err := p.MemberOf(tt.valueSet, tt.coding)
+			assert.NoError(t, err, "no error expected")
+			assert.Equal(t, tt.member, member)
+		})
+	}
+}
+
+func TestMemoryTerminologyProviderMemberOfUnknown(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	member, err := p.MemberOf("http://example.org/fhir/ValueSet/unknown", testCoding("diabetes"))
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "value set is unknown: http://example.org/fhir/ValueSet/unknown", err.Error())
+	}
+	assert.False(t, member)
+
+	_, err = p.MemberOf("http://example.org/fhir/ValueSet/unknown|1.0.0", testCoding("diabetes"))
+	assert.Error(t, err, "error expected")
+}
+
+func TestMemoryTerminologyProviderSubsumes(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+	tests := []struct {
+		coding   hipathsys.Coding
+		other    hipathsys.Coding
+		subsumes bool
+	}{
+		{testCoding("diabetes"), testCoding("diabetes"), true},
+		{testCoding("diabetes"), testCoding("diabetes-type-1"), true},
+		{testCoding("disorder"), testCoding("diabetes-type-2"), true},
+		{testCoding("diabetes"), testCoding("gestational-diabetes"), true},
+		{testCoding("diabetes-type-1"), testCoding("diabetes"), false},
+		{testCoding("hypertension"), testCoding("diabetes-type-1"), false},
+		{testCoding("disorder"), testCoding("gestational-diabetes"), true},
+		{testCoding("disorder"), hipathsys.Coding{Code: "hypertension"}, true},
+		{hipathsys.Coding{Code: "disorder"}, testCoding("hypertension"), true},
+		{testCoding("disorder"), hipathsys.Coding{System: "http://other", Code: "hypertension"}, false},
+		{hipathsys.Coding{Code: "x"}, hipathsys.Coding{Code: "x"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.coding.Code+" "+tt.other.Code, func(t *testing.T) {
+			subsumes, err := p.Subsumes(tt.coding, tt.other)
+			assert.NoError(t, err, "no error expected")
+			assert.Equal(t, tt.subsumes, subsumes)
+		})
+	}
+}
+
+func TestMemoryTerminologyProviderSubsumesNoSystem(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	subsumes, err := p.Subsumes(hipathsys.Coding{Code: "disorder"}, hipathsys.Coding{Code: "diabetes"})
+	assert.Error(t, err, "error expected")
+	assert.False(t, subsumes)
+}
+
+func TestMemoryTerminologyProviderSubsumesUnknownSystem(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	subsumes, err := p.Subsumes(hipathsys.Coding{System: "http://other", Code: "a"},
+		hipathsys.Coding{System: "http://other", Code: "b"})
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "code system is unknown: http://other", err.Error())
+	}
+	assert.False(t, subsumes)
+}
+
+func TestMemoryTerminologyProviderSubsumesCycle(t *testing.T) {
+	p := NewMemoryTerminologyProvider()
+	err := p.LoadJSON([]byte(`{"resourceType": "CodeSystem", "url": "http://example.org/cs", "concept": [` +
+		`{"code": "a", "property": [{"code": "parent", "valueCode": "b"}, {"code": "child", "valueCode": "c"}]},` +
+		`{"code": "b", "property": [{"code": "parent", "valueCode": "a"}]}, {"code": "c"}]}`))
+	if !assert.NoError(t, err, "no error expected") {
+		return
+	}
+
+	subsumes, err := p.Subsumes(hipathsys.Coding{System: "http://example.org/cs", Code: "b"},
+		hipathsys.Coding{System: "http://example.org/cs", Code: "c"})
+	assert.NoError(t, err, "no error expected")
+	assert.True(t, subsumes)
+
+	subsumes, err = p.Subsumes(hipathsys.Coding{System: "http://example.org/cs", Code: "c"},
+		hipathsys.Coding{System: "http://example.org/cs", Code: "b"})
+	assert.NoError(t, err, "no error expected")
+	assert.False(t, subsumes)
+}
+
+func evaluateTerminologyJSON(t *testing.T, path string, data string) hipathsys.ColAccessor {
+	node := parseTestJSON(t, data)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Node(node).
+		TerminologyProvider(newTestTerminologyProvider(t)).Build()
+	res, err := gohipath.Execute(ctx, path, node)
+	if err != nil {
+		t.Fatalf("evaluation of %s failed: %v", path, err)
+	}
+	return res
+}
+
+func TestJSONAdapterMemberOf(t *testing.T) {
+	res := evaluateTerminologyJSON(t, "Condition.code.memberOf('http://example.org/fhir/ValueSet/diabetes') and "+
+		"Condition.code.coding.where(code = 'diabetes-type-2').memberOf('http://example.org/fhir/ValueSet/diabetes') and "+
+		"Condition.code.coding.where(code = 'x').memberOf('http://example.org/fhir/ValueSet/diabetes') = false and "+
+		"'diabetes'.memberOf('http://example.org/fhir/ValueSet/diabetes')", testCondition)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterSubsumes(t *testing.T) {
+	res := evaluateTerminologyJSON(t, "Condition.code.coding.where(code = 'diabetes-type-2').subsumedBy("+
+		"%context.code.coding.where(code = 'diabetes-type-2')) and "+
+		"Condition.code.subsumedBy(%context.code) and Condition.code.subsumes(%context.code) and "+
+		"Condition.code.subsumes(%context.clinicalStatus) = false", testCondition)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterMemberOfUnknownValueSet(t *testing.T) {
+	node := parseTestJSON(t, testCondition)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Node(node).
+		TerminologyProvider(newTestTerminologyProvider(t)).Build()
+
+	res, err := gohipath.Execute(ctx, "Condition.code.memberOf(%`vs-unknown`)", node)
+	if assert.NotNil(t, err, "error expected") {
+		assert.Equal(t, "value set is unknown: http://hl7.org/fhir/ValueSet/unknown", err.Error())
+	}
+	assert.Nil(t, res)
+}
+
+func parameterValue(t *testing.T, parameters interface{}, name string) interface{} {
+	for _, p := range parameters.(map[string]interface{})["parameter"].([]interface{}) {
+		param := p.(map[string]interface{})
+		if param["name"] == name {
+			for key, value := range param {
+				if key != "name" {
+					return value
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestMemoryTerminologyProviderLoadConceptMapNoURL(t *testing.T) {
+	err := NewMemoryTerminologyProvider().LoadJSON([]byte(`{"resourceType": "ConceptMap"}`))
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "concept map has no URL", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderExpand(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	res, err := p.Expand("http://example.org/fhir/ValueSet/diabetes", url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, map[string]interface{}{
+		"resourceType": "ValueSet",
+		"url":          "http://example.org/fhir/ValueSet/diabetes",
+		"expansion": map[string]interface{}{
+			"total": json.Number("3"),
+			"contains": []interface{}{
+				map[string]interface{}{"system": testConditionSystem, "code": "diabetes", "display": "Diabetes mellitus"},
+				map[string]interface{}{"system": testConditionSystem, "code": "diabetes-type-1", "display": "Type 1 diabetes mellitus"},
+				map[string]interface{}{"system": testConditionSystem, "code": "diabetes-type-2", "display": "Type 2 diabetes mellitus"},
+			},
+		},
+	}, res)
+}
+
+func TestMemoryTerminologyProviderExpandCodeSystem(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	res, err := p.Expand("http://example.org/fhir/ValueSet/conditions", url.Values{"filter": {"DIABETES"}, "count": {"2"}})
+	assert.NoError(t, err, "no error expected")
+	expansion := res.(map[string]interface{})["expansion"].(map[string]interface{})
+	assert.Equal(t, json.Number("4"), expansion["total"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"system": testConditionSystem, "code": "diabetes", "display": "Diabetes mellitus"},
+		map[string]interface{}{"system": testConditionSystem, "code": "diabetes-type-1", "display": "Type 1 diabetes mellitus"},
+	}, expansion["contains"])
+
+	res, err = p.Expand("http://example.org/fhir/ValueSet/conditions", url.Values{"filter": {"unknown"}})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, map[string]interface{}{"total": json.Number("0")}, res.(map[string]interface{})["expansion"])
+}
+
+func TestMemoryTerminologyProviderExpandErrors(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	_, err := p.Expand("http://example.org/fhir/ValueSet/unknown", url.Values{})
+	assert.Error(t, err, "error expected")
+	_, err = p.Expand("http://example.org/fhir/ValueSet/diabetes", url.Values{"count": {"x"}})
+	assert.Error(t, err, "error expected")
+
+	err = p.LoadJSON([]byte(`{"resourceType": "ValueSet", "url": "http://example.org/vs", ` +
+		`"compose": {"include": [{"system": "http://example.org/cs"}]}}`))
+	assert.NoError(t, err, "no error expected")
+	_, err = p.Expand("http://example.org/vs", url.Values{})
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "code system is unknown: http://example.org/cs", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderLookup(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	res, err := p.Lookup(testCoding("diabetes-type-1"), url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, "TestConditions", parameterValue(t, res, "name"))
+	assert.Equal(t, "Type 1 diabetes mellitus", parameterValue(t, res, "display"))
+	assert.Equal(t, "diabetes-type-1", parameterValue(t, res, "code"))
+	assert.Equal(t, testConditionSystem, parameterValue(t, res, "system"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "code", "valueCode": "parent"},
+		map[string]interface{}{"name": "value", "valueCode": "diabetes"},
+	}, parameterValue(t, res, "property"))
+}
+
+func TestMemoryTerminologyProviderLookupErrors(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	_, err := p.Lookup(hipathsys.Coding{Code: "diabetes"}, url.Values{})
+	assert.Error(t, err, "error expected")
+	_, err = p.Lookup(hipathsys.Coding{System: "http://other", Code: "diabetes"}, url.Values{})
+	assert.Error(t, err, "error expected")
+	_, err = p.Lookup(testCoding("unknown"), url.Values{})
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "code is unknown: "+testConditionSystem+"|unknown", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderLookupNoName(t *testing.T) {
+	p := NewMemoryTerminologyProvider()
+	err := p.LoadJSON([]byte(`{"resourceType": "CodeSystem", "url": "http://example.org/cs", "concept": [{"code": "a"}]}`))
+	if !assert.NoError(t, err, "no error expected") {
+		return
+	}
+
+	res, err := p.Lookup(hipathsys.Coding{System: "http://example.org/cs", Code: "a"}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, "http://example.org/cs", parameterValue(t, res, "name"))
+	assert.Nil(t, parameterValue(t, res, "display"))
+}
+
+func TestMemoryTerminologyProviderValidateVS(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	res, err := p.ValidateVS("http://example.org/fhir/ValueSet/diabetes",
+		[]hipathsys.Coding{testCoding("hypertension"), testCoding("diabetes-type-2")}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, true, parameterValue(t, res, "result"))
+	assert.Equal(t, "Type 2 diabetes mellitus", parameterValue(t, res, "display"))
+
+	res, err = p.ValidateVS("http://example.org/fhir/ValueSet/conditions",
+		[]hipathsys.Coding{{Code: "hypertension"}}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, true, parameterValue(t, res, "result"))
+	assert.Equal(t, "Hypertensive disorder", parameterValue(t, res, "display"))
+
+	res, err = p.ValidateVS("http://example.org/fhir/ValueSet/diabetes",
+		[]hipathsys.Coding{testCoding("hypertension")}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, false, parameterValue(t, res, "result"))
+	assert.Equal(t, "code is not in value set http://example.org/fhir/ValueSet/diabetes", parameterValue(t, res, "message"))
+
+	_, err = p.ValidateVS("http://example.org/fhir/ValueSet/unknown", []hipathsys.Coding{testCoding("diabetes")}, url.Values{})
+	assert.Error(t, err, "error expected")
+}
+
+func TestMemoryTerminologyProviderValidateCS(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	res, err := p.ValidateCS(testConditionSystem, []hipathsys.Coding{{Code: "gestational-diabetes"}}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, true, parameterValue(t, res, "result"))
+	assert.Equal(t, "Gestational diabetes", parameterValue(t, res, "display"))
+
+	res, err = p.ValidateCS(testConditionSystem, []hipathsys.Coding{{System: "http://other", Code: "diabetes"},
+		testCoding("unknown")}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, false, parameterValue(t, res, "result"))
+	assert.Equal(t, "code is not in code system "+testConditionSystem, parameterValue(t, res, "message"))
+
+	_, err = p.ValidateCS("http://other", []hipathsys.Coding{testCoding("diabetes")}, url.Values{})
+	assert.Error(t, err, "error expected")
+}
+
+func TestMemoryTerminologyProviderSubsumption(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+	tests := []struct {
+		coding  hipathsys.Coding
+		other   hipathsys.Coding
+		outcome string
+	}{
+		{testCoding("diabetes"), testCoding("diabetes"), "equivalent"},
+		{testCoding("diabetes"), hipathsys.Coding{Code: "diabetes-type-1"}, "subsumes"},
+		{hipathsys.Coding{Code: "gestational-diabetes"}, testCoding("disorder"), "subsumed-by"},
+		{testCoding("hypertension"), testCoding("diabetes"), "not-subsumed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.coding.Code+" "+tt.other.Code, func(t *testing.T) {
+			outcome, err := p.Subsumption(testConditionSystem, tt.coding, tt.other, url.Values{})
+			assert.NoError(t, err, "no error expected")
+			assert.Equal(t, tt.outcome, outcome)
+		})
+	}
+}
+
+func TestMemoryTerminologyProviderSubsumptionErrors(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	_, err := p.Subsumption(testConditionSystem, hipathsys.Coding{System: "http://other", Code: "diabetes"},
+		testCoding("diabetes"), url.Values{})
+	assert.Error(t, err, "error expected")
+	_, err = p.Subsumption("http://other", hipathsys.Coding{Code: "a"}, hipathsys.Coding{Code: "b"}, url.Values{})
+	assert.Error(t, err, "error expected")
+	_, err = p.Subsumption(testConditionSystem, testCoding("diabetes"), testCoding("unknown"), url.Values{})
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "code is unknown: "+testConditionSystem+"|unknown", err.Error())
+	}
+}
+
+func TestMemoryTerminologyProviderTranslate(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	res, err := p.Translate("http://example.org/fhir/ConceptMap/conditions-to-icd|1.0.0",
+		[]hipathsys.Coding{testCoding("diabetes-type-2")}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, map[string]interface{}{
+		"resourceType": "Parameters",
+		"parameter": []interface{}{
+			map[string]interface{}{"name": "result", "valueBoolean": true},
+			map[string]interface{}{"name": "match", "part": []interface{}{
+				map[string]interface{}{"name": "equivalence", "valueCode": "equivalent"},
+				map[string]interface{}{"name": "concept", "valueCoding": map[string]interface{}{
+					"system": "http://hl7.org/fhir/sid/icd-10", "code": "E11", "display": "Type 2 diabetes mellitus"}},
+			}},
+			map[string]interface{}{"name": "match", "part": []interface{}{
+				map[string]interface{}{"name": "relationship", "valueCode": "source-is-broader-than-target"},
+				map[string]interface{}{"name": "concept", "valueCoding": map[string]interface{}{
+					"system": "http://hl7.org/fhir/sid/icd-10", "code": "E11.9"}},
+			}},
+		},
+	}, res)
+}
+
+func TestMemoryTerminologyProviderTranslateNoMatch(t *testing.T) {
+	p := newTestTerminologyProvider(t)
+
+	res, err := p.Translate("http://example.org/fhir/ConceptMap/conditions-to-icd",
+		[]hipathsys.Coding{{Code: "hypertension"}, {System: "http://other", Code: "diabetes-type-1"}}, url.Values{})
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, false, parameterValue(t, res, "result"))
+	assert.Equal(t, "no translation found in concept map http://example.org/fhir/ConceptMap/conditions-to-icd",
+		parameterValue(t, res, "message"))
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "equivalence", "valueCode": "unmatched"}},
+		parameterValue(t, res, "match"))
+
+	_, err = p.Translate("http://example.org/fhir/ConceptMap/unknown|1.0.0", []hipathsys.Coding{testCoding("diabetes")}, url.Values{})
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "concept map is unknown: http://example.org/fhir/ConceptMap/unknown|1.0.0", err.Error())
+	}
+}
+
+func evaluateTerminologiesJSON(t *testing.T, path string, data string) hipathsys.ColAccessor {
+	node := parseTestJSON(t, data)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Node(node).
+		TerminologyService(newTestTerminologyProvider(t)).Build()
+	res, err := gohipath.Execute(ctx, path, node)
+	if err != nil {
+		t.Fatalf("evaluation of %s failed: %v", path, err)
+	}
+	return res
+}
+
+func TestJSONAdapterTerminologiesExpand(t *testing.T) {
+	res := evaluateTerminologiesJSON(t, "%terminologies.expand('http://example.org/fhir/ValueSet/diabetes')"+
+		".expansion.contains.where(code = 'diabetes-type-1').display", testCondition)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Type 1 diabetes mellitus"), res.Get(0))
+	}
+
+	res = evaluateTerminologiesJSON(t, "%terminologies.expand('http://example.org/fhir/ValueSet/conditions', "+
+		"'filter=type&count=1').expansion.select(total.toString() + ' ' + contains.code)", testCondition)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("2 diabetes-type-1"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterTerminologiesLookup(t *testing.T) {
+	res := evaluateTerminologiesJSON(t, "%terminologies.lookup(Condition.code.coding.where(system = "+
+		"'http://example.org/fhir/CodeSystem/conditions')).parameter.where(name = 'display').value", testCondition)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("Type 2 diabetes mellitus"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterTerminologiesValidate(t *testing.T) {
+	res := evaluateTerminologiesJSON(t, "%terminologies.validateVS('http://example.org/fhir/ValueSet/diabetes', "+
+		"Condition.code).parameter.where(name = 'result').value and "+
+		"%terminologies.validateCS('http://example.org/fhir/CodeSystem/conditions', "+
+		"Condition.clinicalStatus).parameter.where(name = 'result').value = false", testCondition)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestJSONAdapterTerminologiesSubsumes(t *testing.T) {
+	res := evaluateTerminologiesJSON(t, "%terminologies.subsumes('http://example.org/fhir/CodeSystem/conditions', "+
+		"'diabetes', Condition.code.coding.where(code = 'diabetes-type-2'))", testCondition)
+	if assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("subsumes"), res.Get(0))
+	}
+}
+
+func TestJSONAdapterTerminologiesTranslate(t *testing.T) {
+	res := evaluateTerminologiesJSON(t, "%terminologies.translate('http://example.org/fhir/ConceptMap/conditions-to-icd', "+
+		"Condition.code).parameter.where(name = 'match').part.where(name = 'concept').value.code", testCondition)
+	if assert.Equal(t, 2, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewString("E11"), res.Get(0))
+		assertSystemEqual(t, hipathsys.NewString("E11.9"), res.Get(1))
+	}
+}
+
+func TestJSONAdapterTerminologiesResourceArg(t *testing.T) {
+	node := parseTestJSON(t, testCondition)
+	valueSet := parseTestJSON(t, `{"resourceType": "ValueSet", "url": "http://example.org/fhir/ValueSet/diabetes"}`)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Node(node).EnvVar("vs", valueSet).
+		TerminologyService(newTestTerminologyProvider(t)).Build()
+
+	res, err := gohipath.Execute(ctx, "%terminologies.expand(%vs).expansion.total", node)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 1, res.Count()) {
+		assertSystemEqual(t, hipathsys.NewInteger(3), res.Get(0))
+	}
+}
+
+func TestJSONAdapterTerminologiesError(t *testing.T) {
+	node := parseTestJSON(t, testCondition)
+	ctx := hipathsys.NewContextBuilder(NewJSONAdapter()).Node(node).
+		TerminologyService(newTestTerminologyProvider(t)).Build()
+
+	res, err := gohipath.Execute(ctx, "%terminologies.lookup(Condition.clinicalStatus)", node)
+	if assert.NotNil(t, err, "error expected") {
+		assert.True(t, errors.Is(err, hipathsys.ErrTerminology))
+		assert.Equal(t, "code system is unknown: http://terminology.hl7.org/CodeSystem/condition-clinical", err.Error())
+	}
+	assert.Nil(t, res)
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathfhir
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"unicode"
+)
+
+const NamespaceName = "FHIR"
+
+var ElementTypeSpec = newTypeSpec("Element", nil)
+var ResourceTypeSpec = newTypeSpec("Resource", nil)
+var DomainResourceTypeSpec = newTypeSpec("DomainResource", ResourceTypeSpec)
+
+var BooleanTypeSpec = newTypeSpec("boolean", ElementTypeSpec)
+var IntegerTypeSpec = newTypeSpec("integer", ElementTypeSpec)
+var Integer64TypeSpec = newTypeSpec("integer64", ElementTypeSpec)
+var UnsignedIntTypeSpec = newTypeSpec("unsignedInt", IntegerTypeSpec)
+var PositiveIntTypeSpec = newTypeSpec("positiveInt", IntegerTypeSpec)
+var DecimalTypeSpec = newTypeSpec("decimal", ElementTypeSpec)
+var StringTypeSpec = newTypeSpec("string", ElementTypeSpec)
+var CodeTypeSpec = newTypeSpec("code", StringTypeSpec)
+var IdTypeSpec = newTypeSpec("id", StringTypeSpec)
+var MarkdownTypeSpec = newTypeSpec("markdown", StringTypeSpec)
+var URITypeSpec = newTypeSpec("uri", ElementTypeSpec)
+var URLTypeSpec = newTypeSpec("url", URITypeSpec)
+var CanonicalTypeSpec = newTypeSpec("canonical", URITypeSpec)
+var OIDTypeSpec = newTypeSpec("oid", URITypeSpec)
+var UUIDTypeSpec = newTypeSpec("uuid", URITypeSpec)
+var Base64BinaryTypeSpec = newTypeSpec("base64Binary", ElementTypeSpec)
+var InstantTypeSpec = newTypeSpec("instant", ElementTypeSpec)
+var DateTypeSpec = newTypeSpec("date", ElementTypeSpec)
+var DateTimeTypeSpec = newTypeSpec("dateTime", ElementTypeSpec)
+var TimeTypeSpec = newTypeSpec("time", ElementTypeSpec)
+
+var BackboneElementTypeSpec = newTypeSpec("BackboneElement", ElementTypeSpec)
+var QuantityTypeSpec = newTypeSpec("Quantity", ElementTypeSpec)
+
+var primitiveTypeSpecs = createTypeSpecsByName(
+	BooleanTypeSpec, IntegerTypeSpec, Integer64TypeSpec, UnsignedIntTypeSpec,
+	PositiveIntTypeSpec, DecimalTypeSpec, StringTypeSpec, CodeTypeSpec, IdTypeSpec,
+	MarkdownTypeSpec, URITypeSpec, URLTypeSpec, CanonicalTypeSpec, OIDTypeSpec,
+	UUIDTypeSpec, Base64BinaryTypeSpec, InstantTypeSpec, DateTypeSpec,
+	DateTimeTypeSpec, TimeTypeSpec)
+
+var complexTypeSpecs = createTypeSpecsByName(
+	QuantityTypeSpec,
+	newTypeSpec("Address", ElementTypeSpec),
+	newTypeSpec("Age", QuantityTypeSpec),
+	newTypeSpec("Annotation", ElementTypeSpec),
+	newTypeSpec("Attachment", ElementTypeSpec),
+	newTypeSpec("CodeableConcept", ElementTypeSpec),
+	newTypeSpec("Coding", ElementTypeSpec),
+	newTypeSpec("ContactDetail", ElementTypeSpec),
+	newTypeSpec("ContactPoint", ElementTypeSpec),
+	newTypeSpec("Contributor", ElementTypeSpec),
+	newTypeSpec("Count", QuantityTypeSpec),
+	newTypeSpec("DataRequirement", ElementTypeSpec),
+	newTypeSpec("Distance", QuantityTypeSpec),
+	newTypeSpec("Dosage", ElementTypeSpec),
+	newTypeSpec("Duration", QuantityTypeSpec),
+	newTypeSpec("Expression", ElementTypeSpec),
+	newTypeSpec("Extension", ElementTypeSpec),
+	newTypeSpec("HumanName", ElementTypeSpec),
+	newTypeSpec("Identifier", ElementTypeSpec),
+	newTypeSpec("Meta", ElementTypeSpec),
+	newTypeSpec("Money", ElementTypeSpec),
+	newTypeSpec("Narrative", ElementTypeSpec),
+	newTypeSpec("ParameterDefinition", ElementTypeSpec),
+	newTypeSpec("Period", ElementTypeSpec),
+	newTypeSpec("Range", ElementTypeSpec),
+	newTypeSpec("Ratio", ElementTypeSpec),
+	newTypeSpec("Reference", ElementTypeSpec),
+	newTypeSpec("RelatedArtifact", ElementTypeSpec),
+	newTypeSpec("SampledData", ElementTypeSpec),
+	newTypeSpec("Signature", ElementTypeSpec),
+	newTypeSpec("Timing", ElementTypeSpec),
+	newTypeSpec("TriggerDefinition", ElementTypeSpec),
+	newTypeSpec("UsageContext", ElementTypeSpec))
+
+var nonDomainResourceTypes = map[string]bool{
+	"Binary":     true,
+	"Bundle":     true,
+	"Parameters": true,
+}
+
+func PrimitiveTypeSpec(name string) hipathsys.TypeSpecAccessor {
+	return primitiveTypeSpecs[name]
+}
+
+func ComplexTypeSpec(name string) hipathsys.TypeSpecAccessor {
+	return complexTypeSpecs[name]
+}
+
+func ResourceTypeSpecOf(resourceType string) hipathsys.TypeSpecAccessor {
+	switch resourceType {
+	case "Resource":
+		return ResourceTypeSpec
+	case "DomainResource":
+		return DomainResourceTypeSpec
+	}
+	if nonDomainResourceTypes[resourceType] {
+		return newTypeSpec(resourceType, ResourceTypeSpec)
+	}
+	return newTypeSpec(resourceType, DomainResourceTypeSpec)
+}
+
+func ChoiceTypeSpec(suffix string) hipathsys.TypeSpecAccessor {
+	if len(suffix) == 0 {
+		return nil
+	}
+
+	r := []rune(suffix)
+	if !unicode.IsUpper(r[0]) {
+		return nil
+	}
+	if ts := complexTypeSpecs[suffix]; ts != nil {
+		return ts
+	}
+
+	r[0] = unicode.ToLower(r[0])
+	return primitiveTypeSpecs[string(r)]
+}
+
+func newTypeSpec(name string, base hipathsys.TypeSpecAccessor) hipathsys.TypeSpecAccessor {
+	return hipathsys.NewTypeSpecWithBase(hipathsys.NewFQTypeName(name, NamespaceName), base)
+}
+
+func createTypeSpecsByName(typeSpecs ...hipathsys.TypeSpecAccessor) map[string]hipathsys.TypeSpecAccessor {
+	typeSpecsByName := make(map[string]hipathsys.TypeSpecAccessor, len(typeSpecs))
+	for _, ts := range typeSpecs {
+		typeSpecsByName[ts.FQName().Name()] = ts
+	}
+	return typeSpecsByName
+}
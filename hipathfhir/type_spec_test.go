@@ -0,0 +1,83 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathfhir
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrimitiveTypeSpec(t *testing.T) {
+	ts := PrimitiveTypeSpec("code")
+	if assert.NotNil(t, ts) {
+		assert.Equal(t, "FHIR.code", ts.String())
+		assert.True(t, ts.ExtendsName(hipathsys.NewFQTypeName("string", "FHIR")))
+		assert.True(t, ts.ExtendsName(hipathsys.NewTypeName("Element")))
+	}
+	assert.Nil(t, PrimitiveTypeSpec("Quantity"))
+}
+
+func TestComplexTypeSpec(t *testing.T) {
+	ts := ComplexTypeSpec("Age")
+	if assert.NotNil(t, ts) {
+		assert.Equal(t, "FHIR.Age", ts.String())
+		assert.True(t, ts.ExtendsName(hipathsys.NewFQTypeName("Quantity", "FHIR")))
+	}
+	assert.Nil(t, ComplexTypeSpec("string"))
+}
+
+func TestResourceTypeSpecOf(t *testing.T) {
+	ts := ResourceTypeSpecOf("Patient")
+	assert.Equal(t, "FHIR.Patient", ts.String())
+	assert.True(t, ts.ExtendsName(hipathsys.NewFQTypeName("DomainResource", "FHIR")))
+	assert.True(t, ts.ExtendsName(hipathsys.NewFQTypeName("Resource", "FHIR")))
+}
+
+func TestResourceTypeSpecOfNonDomainResource(t *testing.T) {
+	ts := ResourceTypeSpecOf("Bundle")
+	assert.Equal(t, "FHIR.Bundle", ts.String())
+	assert.False(t, ts.ExtendsName(hipathsys.NewFQTypeName("DomainResource", "FHIR")))
+	assert.True(t, ts.ExtendsName(hipathsys.NewFQTypeName("Resource", "FHIR")))
+}
+
+func TestResourceTypeSpecOfAbstract(t *testing.T) {
+	assert.Same(t, ResourceTypeSpec, ResourceTypeSpecOf("Resource"))
+	assert.Same(t, DomainResourceTypeSpec, ResourceTypeSpecOf("DomainResource"))
+}
+
+func TestChoiceTypeSpec(t *testing.T) {
+	assert.Same(t, QuantityTypeSpec, ChoiceTypeSpec("Quantity"))
+	assert.Same(t, StringTypeSpec, ChoiceTypeSpec("String"))
+	assert.Same(t, DateTimeTypeSpec, ChoiceTypeSpec("DateTime"))
+	assert.Same(t, Base64BinaryTypeSpec, ChoiceTypeSpec("Base64Binary"))
+	assert.Nil(t, ChoiceTypeSpec("string"))
+	assert.Nil(t, ChoiceTypeSpec("Set"))
+	assert.Nil(t, ChoiceTypeSpec(""))
+}
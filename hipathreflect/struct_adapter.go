@@ -0,0 +1,452 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathreflect
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/shopspring/decimal"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+var decimalType = reflect.TypeOf(decimal.Decimal{})
+var jsonNumberType = reflect.TypeOf(json.Number(""))
+
+type StructAdapter interface {
+	hipathsys.ModelAdapter
+	RegisterBaseType(t reflect.Type, base reflect.Type)
+}
+
+type structAdapter struct {
+	namespace string
+	lock      sync.RWMutex
+	baseTypes map[reflect.Type]reflect.Type
+	typeSpecs map[reflect.Type]hipathsys.TypeSpecAccessor
+	fields    map[reflect.Type]map[string]*structField
+}
+
+type structField struct {
+	index     []int
+	omitEmpty bool
+}
+
+func NewStructAdapter(namespace string) StructAdapter {
+	return &structAdapter{
+		namespace: namespace,
+		baseTypes: make(map[reflect.Type]reflect.Type),
+		typeSpecs: make(map[reflect.Type]hipathsys.TypeSpecAccessor),
+		fields:    make(map[reflect.Type]map[string]*structField),
+	}
+}
+
+func (a *structAdapter) RegisterBaseType(t reflect.Type, base reflect.Type) {
+	t, base = indirectType(t), indirectType(base)
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.baseTypes[t] = base
+	a.typeSpecs = make(map[reflect.Type]hipathsys.TypeSpecAccessor)
+}
+
+func (a *structAdapter) AsType(node interface{}, name hipathsys.FQTypeNameAccessor) (interface{}, error) {
+	if a.TypeSpec(node).ExtendsName(name) {
+		return node, nil
+	}
+	if name.Namespace() == hipathsys.NamespaceName {
+		if n, err := a.CastToSystem(node); n != nil || err != nil {
+			return n, err
+		}
+	}
+	return nil, nil
+}
+
+func (a *structAdapter) CastToSystem(node interface{}) (hipathsys.AnyAccessor, error) {
+	if n, ok := node.(hipathsys.AnyAccessor); ok {
+		return n, nil
+	}
+
+	v := indirectValue(reflect.ValueOf(node))
+	if !v.IsValid() {
+		return nil, nil
+	}
+	res, err := a.convert(v)
+	if n, ok := res.(hipathsys.AnyAccessor); ok {
+		return n, err
+	}
+	return nil, err
+}
+
+func (a *structAdapter) TypeSpec(node interface{}) hipathsys.TypeSpecAccessor {
+	if n, ok := node.(hipathsys.AnyAccessor); ok {
+		node = n.Source()
+		if node == nil {
+			return hipathsys.UndefinedTypeSpec
+		}
+	}
+
+	t := indirectType(reflect.TypeOf(node))
+	if t == nil || len(t.Name()) == 0 || len(t.PkgPath()) == 0 {
+		return hipathsys.UndefinedTypeSpec
+	}
+	return a.typeSpec(t)
+}
+
+func (a *structAdapter) Equal(node1 interface{}, node2 interface{}) bool {
+	v1 := indirectValue(reflect.ValueOf(node1))
+	v2 := indirectValue(reflect.ValueOf(node2))
+	if !v1.IsValid() || !v2.IsValid() {
+		return !v1.IsValid() && !v2.IsValid()
+	}
+	return reflect.DeepEqual(v1.Interface(), v2.Interface())
+}
+
+func (a *structAdapter) Equivalent(node1 interface{}, node2 interface{}) bool {
+	return a.Equal(node1, node2)
+}
+
+func (a *structAdapter) Navigate(node interface{}, name string) (interface{}, error) {
+	if col, ok := node.(hipathsys.ColAccessor); ok {
+		res := hipathsys.NewCol(a)
+		count := col.Count()
+		for i := 0; i < count; i++ {
+			n, err := a.Navigate(col.Get(i), name)
+			if err != nil {
+				return nil, err
+			}
+			addItems(res, n)
+		}
+		return res, nil
+	}
+
+	if _, ok := node.(hipathsys.AnyAccessor); ok {
+		return hipathsys.EmptyCol, nil
+	}
+
+	v := indirectValue(reflect.ValueOf(node))
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type().Name() == name {
+			return node, nil
+		}
+		f := a.structFields(v.Type())[name]
+		if f == nil {
+			return hipathsys.EmptyCol, nil
+		}
+		fv := v.FieldByIndex(f.index)
+		if f.omitEmpty && fv.IsZero() {
+			return hipathsys.EmptyCol, nil
+		}
+		return a.convertValue(fv)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key must be a string: %s", v.Type())
+		}
+		mv := v.MapIndex(reflect.ValueOf(name).Convert(v.Type().Key()))
+		if !mv.IsValid() {
+			return hipathsys.EmptyCol, nil
+		}
+		return a.convertValue(mv)
+	}
+
+	return hipathsys.EmptyCol, nil
+}
+
+func (a *structAdapter) Children(node interface{}) (hipathsys.ColAccessor, error) {
+	if _, ok := node.(hipathsys.AnyAccessor); ok {
+		return nil, nil
+	}
+
+	v := indirectValue(reflect.ValueOf(node))
+	res := hipathsys.NewCol(a)
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range reflect.VisibleFields(v.Type()) {
+			if f.Anonymous || !f.IsExported() || !valueKind(f.Type) {
+				continue
+			}
+			if name, omitEmpty, ok := fieldName(f); ok {
+				if sf := a.structFields(v.Type())[name]; sf == nil || !reflect.DeepEqual(sf.index, f.Index) {
+					continue
+				}
+				fv := v.FieldByIndex(f.Index)
+				if omitEmpty && fv.IsZero() {
+					continue
+				}
+				n, err := a.convertValue(fv)
+				if err != nil {
+					return nil, err
+				}
+				addItems(res, n)
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			n, err := a.convertValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			addItems(res, n)
+		}
+	}
+	return res, nil
+}
+
+func (a *structAdapter) convertValue(v reflect.Value) (interface{}, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Kind() == reflect.Ptr && indirectType(v.Type()).Kind() == reflect.Struct &&
+			!isSystemType(indirectType(v.Type())) {
+			return v.Interface(), nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		res := hipathsys.NewCol(a)
+		count := v.Len()
+		for i := 0; i < count; i++ {
+			n, err := a.convertValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			if n != nil {
+				res.Add(n)
+			}
+		}
+		return res, nil
+	case reflect.Struct, reflect.Map:
+		if !isSystemType(v.Type()) {
+			if v.CanAddr() {
+				return v.Addr().Interface(), nil
+			}
+			return v.Interface(), nil
+		}
+	}
+
+	return a.convert(v)
+}
+
+func (a *structAdapter) convert(v reflect.Value) (interface{}, error) {
+	source := v.Interface()
+	switch v.Kind() {
+	case reflect.Bool:
+		return hipathsys.NewBooleanWithSource(v.Bool(), source), nil
+	case reflect.String:
+		if v.Type() == jsonNumberType {
+			return convertNumber(v.String(), source)
+		}
+		return hipathsys.NewStringWithSource(v.String(), source), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		if i >= math.MinInt32 && i <= math.MaxInt32 {
+			return hipathsys.NewIntegerWithSource(int32(i), source), nil
+		}
+		return hipathsys.NewLongWithSource(i, source), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i := v.Uint()
+		if i <= math.MaxInt32 {
+			return hipathsys.NewIntegerWithSource(int32(i), source), nil
+		}
+		if i <= math.MaxInt64 {
+			return hipathsys.NewLongWithSource(int64(i), source), nil
+		}
+		return hipathsys.NewDecimalWithSource(decimal.NewFromBigInt(
+			new(big.Int).SetUint64(i), 0), source), nil
+	case reflect.Float32, reflect.Float64:
+		return hipathsys.NewDecimalFloat64WithSource(v.Float(), source), nil
+	case reflect.Slice, reflect.Array:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hipathsys.NewStringWithSource(base64.StdEncoding.EncodeToString(b), source), nil
+	case reflect.Struct:
+		switch v.Type() {
+		case timeType:
+			return hipathsys.NewDateTimeWithSource(source.(time.Time), source), nil
+		case decimalType:
+			return hipathsys.NewDecimalWithSource(source.(decimal.Decimal), source), nil
+		}
+	}
+	return nil, nil
+}
+
+func convertNumber(value string, source interface{}) (interface{}, error) {
+	if !strings.ContainsAny(value, ".eE") {
+		if i, err := hipathsys.ParseInteger(value); err == nil {
+			return hipathsys.NewIntegerWithSource(i.Int(), source), nil
+		}
+		if l, err := hipathsys.ParseLongWithSource(value, source); err == nil {
+			return l, nil
+		}
+	}
+	return hipathsys.ParseDecimalWithSource(value, source)
+}
+
+func (a *structAdapter) typeSpec(t reflect.Type) hipathsys.TypeSpecAccessor {
+	a.lock.RLock()
+	ts, found := a.typeSpecs[t]
+	a.lock.RUnlock()
+	if found {
+		return ts
+	}
+
+	var baseTypeSpec hipathsys.TypeSpecAccessor
+	if base := a.baseType(t); base != nil {
+		baseTypeSpec = a.typeSpec(base)
+	}
+	ts = hipathsys.NewTypeSpecWithBase(hipathsys.NewFQTypeName(t.Name(), a.namespace), baseTypeSpec)
+
+	a.lock.Lock()
+	a.typeSpecs[t] = ts
+	a.lock.Unlock()
+	return ts
+}
+
+func (a *structAdapter) baseType(t reflect.Type) reflect.Type {
+	a.lock.RLock()
+	base, found := a.baseTypes[t]
+	a.lock.RUnlock()
+	if found {
+		return base
+	}
+
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if ft := indirectType(f.Type); f.Anonymous && ft.Kind() == reflect.Struct && len(ft.Name()) > 0 {
+				return ft
+			}
+		}
+	}
+	return nil
+}
+
+func (a *structAdapter) structFields(t reflect.Type) map[string]*structField {
+	a.lock.RLock()
+	fields, found := a.fields[t]
+	a.lock.RUnlock()
+	if found {
+		return fields
+	}
+
+	fields = make(map[string]*structField)
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Anonymous || !f.IsExported() || !valueKind(f.Type) {
+			continue
+		}
+		if name, omitEmpty, ok := fieldName(f); ok {
+			if _, found := fields[name]; !found || len(f.Index) == 1 {
+				fields[name] = &structField{f.Index, omitEmpty}
+			}
+		}
+	}
+
+	a.lock.Lock()
+	a.fields[t] = fields
+	a.lock.Unlock()
+	return fields
+}
+
+func fieldName(f reflect.StructField) (string, bool, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if len(name) == 0 {
+		r := []rune(f.Name)
+		r[0] = unicode.ToLower(r[0])
+		name = string(r)
+	}
+
+	omitEmpty := false
+	for _, p := range parts[1:] {
+		if p == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, true
+}
+
+func valueKind(t reflect.Type) bool {
+	switch indirectType(t).Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	}
+	return true
+}
+
+func isSystemType(t reflect.Type) bool {
+	return t == timeType || t == decimalType
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func addItems(col hipathsys.ColModifier, node interface{}) {
+	if node == nil {
+		return
+	}
+	if c, ok := node.(hipathsys.ColAccessor); ok {
+		col.AddAll(c)
+	} else {
+		col.Add(node)
+	}
+}
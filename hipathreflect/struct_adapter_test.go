@@ -0,0 +1,292 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathreflect
+
+import (
+	"encoding/json"
+	"github.com/healthiop/hipath"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type Code string
+
+type Element struct {
+	ID string `json:"id,omitempty"`
+}
+
+type Resource struct {
+	ID   string `json:"id,omitempty"`
+	Meta *Meta  `json:"meta,omitempty"`
+}
+
+type DomainResource struct {
+	Resource
+	Text *string `json:"text,omitempty"`
+}
+
+type Meta struct {
+	VersionID   string     `json:"versionId"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+}
+
+type HumanName struct {
+	Element
+	Use    Code     `json:"use,omitempty"`
+	Family *string  `json:"family,omitempty"`
+	Given  []string `json:"given,omitempty"`
+}
+
+type Quantity struct {
+	Value decimal.Decimal `json:"value"`
+	Unit  string          `json:"unit"`
+}
+
+type Patient struct {
+	DomainResource
+	Active        *bool                  `json:"active,omitempty"`
+	Name          []HumanName            `json:"name,omitempty"`
+	BirthDate     time.Time              `json:"birthDate"`
+	Weight        *Quantity              `json:"weight,omitempty"`
+	Count         int64                  `json:"count"`
+	Big           uint64                 `json:"big"`
+	Score         float64                `json:"score"`
+	Number        json.Number            `json:"number"`
+	Photo         []byte                 `json:"photo,omitempty"`
+	Extra         map[string]interface{} `json:"extra,omitempty"`
+	Internal      string                 `json:"-"`
+	NoTag         string
+	unexported    string
+	Contact       []*HumanName `json:"contact,omitempty"`
+	OtherResource interface{}  `json:"otherResource,omitempty"`
+}
+
+func newTestPatient() *Patient {
+	active := true
+	family := "Chalmers"
+	updated := time.Date(2020, 5, 1, 10, 30, 0, 0, time.UTC)
+	return &Patient{
+		DomainResource: DomainResource{
+			Resource: Resource{
+				ID:   "example",
+				Meta: &Meta{VersionID: "1", LastUpdated: &updated},
+			},
+		},
+		Active: &active,
+		Name: []HumanName{
+			{Use: "official", Family: &family, Given: []string{"Peter", "James"}},
+			{Use: "usual", Given: []string{"Jim"}},
+		},
+		BirthDate:  time.Date(1974, 12, 25, 0, 0, 0, 0, time.UTC),
+		Weight:     &Quantity{Value: decimal.RequireFromString("72.50"), Unit: "kg"},
+		Count:      5000000000,
+		Big:        12,
+		Score:      1.5,
+		Number:     json.Number("12.50"),
+		Photo:      []byte{1, 2, 3},
+		Extra:      map[string]interface{}{"key": "value"},
+		Internal:   "internal",
+		NoTag:      "noTag",
+		unexported: "unexported",
+	}
+}
+
+func evaluateStruct(t *testing.T, path string, node interface{}) hipathsys.ColAccessor {
+	adapter := NewStructAdapter("Test")
+	ctx := hipathsys.NewContextBuilder(adapter).Node(node).Build()
+	res, err := gohipath.Execute(ctx, path, node)
+	if err != nil {
+		t.Fatalf("evaluation of %s failed: %v", path, err)
+	}
+	return res
+}
+
+func assertSingle(t *testing.T, expected hipathsys.AnyAccessor, res hipathsys.ColAccessor) {
+	if assert.Equal(t, 1, res.Count()) {
+		assert.True(t, expected.Equal(res.Get(0)), "expected %v, but was %v", expected, res.Get(0))
+	}
+}
+
+func TestStructAdapterTypeName(t *testing.T) {
+	assertSingle(t, hipathsys.NewString("example"), evaluateStruct(t, "Patient.id", newTestPatient()))
+	assert.Equal(t, 0, evaluateStruct(t, "Observation.id", newTestPatient()).Count())
+}
+
+func TestStructAdapterSlice(t *testing.T) {
+	res := evaluateStruct(t, "name.given", newTestPatient())
+	if assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, "Peter", res.Get(0).(hipathsys.StringAccessor).String())
+		assert.Equal(t, "James", res.Get(1).(hipathsys.StringAccessor).String())
+		assert.Equal(t, "Jim", res.Get(2).(hipathsys.StringAccessor).String())
+	}
+}
+
+func TestStructAdapterPointer(t *testing.T) {
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "active", newTestPatient()))
+	assertSingle(t, hipathsys.NewString("Chalmers"), evaluateStruct(t, "name.family", newTestPatient()))
+	assertSingle(t, hipathsys.NewString("1"), evaluateStruct(t, "meta.versionId", newTestPatient()))
+	assert.Equal(t, 0, evaluateStruct(t, "contact.family", newTestPatient()).Count())
+}
+
+func TestStructAdapterOmitEmpty(t *testing.T) {
+	assert.Equal(t, 0, evaluateStruct(t, "name.id", newTestPatient()).Count())
+}
+
+func TestStructAdapterTime(t *testing.T) {
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "birthDate < @1980-01-01T00:00:00Z", newTestPatient()))
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "meta.lastUpdated > @2020-01-01T00:00:00Z", newTestPatient()))
+}
+
+func TestStructAdapterNumbers(t *testing.T) {
+	assertSingle(t, hipathsys.NewLong(5000000000), evaluateStruct(t, "count", newTestPatient()))
+	assertSingle(t, hipathsys.NewInteger(12), evaluateStruct(t, "big", newTestPatient()))
+	assertSingle(t, hipathsys.NewDecimalFloat64(1.5), evaluateStruct(t, "score", newTestPatient()))
+	assertSingle(t, hipathsys.NewDecimalFloat64(12.5), evaluateStruct(t, "number", newTestPatient()))
+	assertSingle(t, hipathsys.NewDecimalFloat64(72.5), evaluateStruct(t, "weight.value", newTestPatient()))
+}
+
+func TestStructAdapterBytes(t *testing.T) {
+	assertSingle(t, hipathsys.NewString("AQID"), evaluateStruct(t, "photo", newTestPatient()))
+}
+
+func TestStructAdapterMap(t *testing.T) {
+	assertSingle(t, hipathsys.NewString("value"), evaluateStruct(t, "extra.key", newTestPatient()))
+	assert.Equal(t, 0, evaluateStruct(t, "extra.other", newTestPatient()).Count())
+}
+
+func TestStructAdapterFieldNames(t *testing.T) {
+	assert.Equal(t, 0, evaluateStruct(t, "internal", newTestPatient()).Count())
+	assert.Equal(t, 0, evaluateStruct(t, "unexported", newTestPatient()).Count())
+	assertSingle(t, hipathsys.NewString("noTag"), evaluateStruct(t, "noTag", newTestPatient()))
+}
+
+func TestStructAdapterEmbeddedBaseType(t *testing.T) {
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "is(Test.Patient)", newTestPatient()))
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "is(DomainResource)", newTestPatient()))
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "is(Resource)", newTestPatient()))
+	assertSingle(t, hipathsys.False, evaluateStruct(t, "is(Element)", newTestPatient()))
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "name.first() is Element", newTestPatient()))
+}
+
+func TestStructAdapterNamedPrimitiveType(t *testing.T) {
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "name.first().use is Code", newTestPatient()))
+	assert.Equal(t, 2, evaluateStruct(t, "name.use.ofType(Test.Code)", newTestPatient()).Count())
+	assertSingle(t, hipathsys.NewString("official"), evaluateStruct(t, "name.first().use.as(Code)", newTestPatient()))
+}
+
+func TestStructAdapterChildren(t *testing.T) {
+	res := evaluateStruct(t, "name.first().children()", newTestPatient())
+	assert.Equal(t, 4, res.Count())
+	res = evaluateStruct(t, "weight.children()", newTestPatient())
+	assert.Equal(t, 2, res.Count())
+}
+
+func TestStructAdapterEqual(t *testing.T) {
+	assertSingle(t, hipathsys.True, evaluateStruct(t, "name.first() = name.first()", newTestPatient()))
+	assertSingle(t, hipathsys.False, evaluateStruct(t, "name.first() = name.last()", newTestPatient()))
+}
+
+func TestStructAdapterRegisterBaseType(t *testing.T) {
+	a := NewStructAdapter("Test")
+	assert.False(t, a.TypeSpec(&Quantity{}).ExtendsName(hipathsys.NewTypeName("Element")))
+
+	a.RegisterBaseType(reflect.TypeOf(Quantity{}), reflect.TypeOf(&Element{}))
+	ts := a.TypeSpec(&Quantity{})
+	assert.Equal(t, "Test.Quantity", ts.String())
+	assert.True(t, ts.ExtendsName(hipathsys.NewFQTypeName("Element", "Test")))
+}
+
+func TestStructAdapterTypeSpecUndefined(t *testing.T) {
+	a := NewStructAdapter("Test")
+	assert.Same(t, hipathsys.UndefinedTypeSpec, a.TypeSpec(hipathsys.NewString("test")))
+	assert.Same(t, hipathsys.UndefinedTypeSpec, a.TypeSpec("test"))
+	assert.Same(t, hipathsys.UndefinedTypeSpec, a.TypeSpec(nil))
+}
+
+func TestStructAdapterCastToSystem(t *testing.T) {
+	a := NewStructAdapter("Test")
+	res, err := a.CastToSystem(Code("test"))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, "test", res.(hipathsys.StringAccessor).String())
+
+	res, err = a.CastToSystem(&Quantity{})
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestStructAdapterAsSystemTypeStruct(t *testing.T) {
+	res := evaluateStruct(t, "weight.as(System.String)", newTestPatient())
+	assert.Equal(t, 0, res.Count())
+	res = evaluateStruct(t, "name.first().as(System.String)", newTestPatient())
+	assert.Equal(t, 0, res.Count())
+}
+
+type testHandler struct {
+	Name     string `json:"name"`
+	Callback func() string
+	Events   chan string
+	Values   map[string]interface{}
+}
+
+func TestStructAdapterChildrenNonValueFields(t *testing.T) {
+	node := &testHandler{
+		Name:     "test",
+		Callback: func() string { return "test" },
+		Events:   make(chan string),
+		Values:   map[string]interface{}{"key": "value", "func": func() {}},
+	}
+	res := evaluateStruct(t, "descendants().count()", node)
+	assertSingle(t, hipathsys.NewInteger(3), res)
+	res = evaluateStruct(t, "callback | events", node)
+	assert.Equal(t, 0, res.Count())
+}
+
+func TestStructAdapterAsSystemType(t *testing.T) {
+	a := NewStructAdapter("Test")
+	res, err := a.AsType(Code("test"), hipathsys.NewFQTypeName("String", "System"))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, "test", res.(hipathsys.StringAccessor).String())
+}
+
+func TestStructAdapterNavigateInvalidMap(t *testing.T) {
+	a := NewStructAdapter("Test")
+	res, err := a.Navigate(map[int]string{}, "test")
+	assert.EqualError(t, err, "map key must be a string: map[int]string")
+	assert.Nil(t, res)
+}
+
+func TestStructAdapterNavigateInterface(t *testing.T) {
+	p := newTestPatient()
+	p.OtherResource = newTestPatient()
+	assertSingle(t, hipathsys.NewString("example"), evaluateStruct(t, "otherResource.id", p))
+}
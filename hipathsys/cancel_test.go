@@ -0,0 +1,89 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewCancelableContextNil(t *testing.T) {
+	assert.Panics(t, func() { NewCancelableContext(newTestContext(t), nil) })
+}
+
+func TestCancelableContextDelegate(t *testing.T) {
+	delegate := NewContextBuilder(newTestModel(t)).
+		Node(NewString("node")).
+		Tracer(&testTracer{}).
+		EnvVar("test", NewString("value")).
+		Build()
+	goCtx := context.Background()
+	ctx := NewCancelableContext(delegate, goCtx)
+
+	assert.Same(t, delegate, ctx.Delegate())
+	assert.Equal(t, goCtx, ctx.Context())
+	assert.Same(t, delegate.ModelAdapter(), ctx.ModelAdapter())
+	assert.Same(t, delegate.Tracer(), ctx.Tracer())
+	assert.Same(t, delegate.ContextNode(), ctx.ContextNode())
+	v, found := ctx.EnvVar("test")
+	assert.True(t, found)
+	assert.Equal(t, NewString("value"), v)
+	assert.Equal(t, 0, ctx.NewCol().Count())
+	assert.Equal(t, 1, ctx.NewColWithItem(NewString("test")).Count())
+}
+
+func TestCheckCanceledNotCancelable(t *testing.T) {
+	assert.NoError(t, CheckCanceled(newTestContext(t)))
+}
+
+func TestCheckCanceledActive(t *testing.T) {
+	ctx := NewCancelableContext(newTestContext(t), context.Background())
+	assert.NoError(t, CheckCanceled(ctx))
+}
+
+func TestCheckCanceled(t *testing.T) {
+	goCtx, cancel := context.WithCancel(context.Background())
+	ctx := NewCancelableContext(newTestContext(t), goCtx)
+	cancel()
+
+	err := CheckCanceled(ctx)
+	assert.EqualError(t, err, "evaluation has been canceled: context canceled")
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestCheckCanceledDelegated(t *testing.T) {
+	goCtx, cancel := context.WithCancel(context.Background())
+	ctx := NewLimitedContext(NewCancelableContext(newTestContext(t), goCtx), Limits{})
+	cancel()
+
+	err := CheckCanceled(ctx)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -28,6 +28,10 @@
 
 package hipathsys
 
+import (
+	"reflect"
+)
+
 type ModelAdapter interface {
 	AsType(node interface{}, name FQTypeNameAccessor) (interface{}, error)
 	CastToSystem(node interface{}) (AnyAccessor, error)
@@ -38,6 +42,25 @@ type ModelAdapter interface {
 	Children(node interface{}) (ColAccessor, error)
 }
 
+type ClassInfoAdapter interface {
+	ClassInfo(node interface{}) ClassInfoAccessor
+}
+
+type ResourceAdapter interface {
+	Resource(node interface{}) bool
+}
+
+type ElementAdapter interface {
+	PrimitiveValue(node interface{}) (AnyAccessor, bool)
+	Extensions(node interface{}) (ColAccessor, error)
+}
+
+// ModelNodeWrapper is implemented by nodes that wrap a model node with
+// additional information. Wrapped nodes are identical to the wrapped node.
+type ModelNodeWrapper interface {
+	ModelNode() interface{}
+}
+
 func ModelTypeSpec(adapter ModelAdapter, node interface{}) TypeSpecAccessor {
 	if node == nil {
 		return nil
@@ -49,6 +72,58 @@ func ModelTypeSpec(adapter ModelAdapter, node interface{}) TypeSpecAccessor {
 	return adapter.TypeSpec(node)
 }
 
+func ModelTypeInfo(adapter ModelAdapter, node interface{}) TypeInfoAccessor {
+	if node == nil {
+		return nil
+	}
+
+	if a, ok := adapter.(ClassInfoAdapter); ok {
+		if ci := a.ClassInfo(node); ci != nil {
+			return ci
+		}
+	}
+	if n, ok := node.(AnyAccessor); ok {
+		return n.TypeInfo()
+	}
+	return SimpleTypeInfoOf(adapter.TypeSpec(node))
+}
+
+func ModelNavigate(adapter ModelAdapter, node interface{}, name string) (interface{}, error) {
+	if res, ok := NavigateTypeInfo(node, name); ok {
+		return res, nil
+	}
+
+	col, ok := node.(ColAccessor)
+	if !ok || !containsTypeInfo(col) {
+		return adapter.Navigate(node, name)
+	}
+
+	res := NewCol(adapter)
+	count := col.Count()
+	for i := 0; i < count; i++ {
+		n, err := ModelNavigate(adapter, col.Get(i), name)
+		if err != nil {
+			return nil, err
+		}
+		if c, ok := n.(ColAccessor); ok {
+			res.AddAll(c)
+		} else if n != nil {
+			res.Add(n)
+		}
+	}
+	return res, nil
+}
+
+func containsTypeInfo(col ColAccessor) bool {
+	count := col.Count()
+	for i := 0; i < count; i++ {
+		if _, ok := col.Get(i).(typeInfoNavigator); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func HasModelType(adapter ModelAdapter, node interface{}, name FQTypeNameAccessor) bool {
 	if node == nil {
 		return false
@@ -82,7 +157,10 @@ func CastModelType(adapter ModelAdapter, node interface{}, name FQTypeNameAccess
 		return node, nil
 	}
 	if sys {
-		// system node cannot be casted by model adapter
+		// system node cannot be casted by model adapter, but may be of model type
+		if name.Namespace() != NamespaceName && adapter.TypeSpec(node).ExtendsName(name) {
+			return node, nil
+		}
 		return nil, nil
 	}
 
@@ -158,6 +236,16 @@ type Tracer interface {
 	Trace(name string, col ColAccessor)
 }
 
+type ContextDelegator interface {
+	Delegate() ContextAccessor
+}
+
+// DelegatingContext forwards all context accessor methods to its delegate.
+// Context wrappers embed it and override only the methods they change.
+type DelegatingContext struct {
+	delegate ContextAccessor
+}
+
 type ContextAccessor interface {
 	EnvVar(name string) (interface{}, bool)
 	ContextNode() interface{}
@@ -167,6 +255,174 @@ type ContextAccessor interface {
 	Tracer() Tracer
 }
 
+func NewDelegatingContext(delegate ContextAccessor) DelegatingContext {
+	return DelegatingContext{delegate}
+}
+
+func (c *DelegatingContext) Delegate() ContextAccessor {
+	return c.delegate
+}
+
+func (c *DelegatingContext) EnvVar(name string) (interface{}, bool) {
+	return c.delegate.EnvVar(name)
+}
+
+func (c *DelegatingContext) ContextNode() interface{} {
+	return c.delegate.ContextNode()
+}
+
+func (c *DelegatingContext) ModelAdapter() ModelAdapter {
+	return c.delegate.ModelAdapter()
+}
+
+func (c *DelegatingContext) NewCol() ColModifier {
+	return c.delegate.NewCol()
+}
+
+func (c *DelegatingContext) NewColWithItem(item interface{}) ColModifier {
+	return c.delegate.NewColWithItem(item)
+}
+
+func (c *DelegatingContext) Tracer() Tracer {
+	return c.delegate.Tracer()
+}
+
+func FindContext(ctx ContextAccessor, matches func(ctx ContextAccessor) bool) ContextAccessor {
+	for ctx != nil {
+		if matches(ctx) {
+			return ctx
+		}
+		if d, ok := ctx.(ContextDelegator); ok {
+			ctx = d.Delegate()
+		} else {
+			ctx = nil
+		}
+	}
+	return nil
+}
+
 func systemNamespace(name string) bool {
 	return len(name) == 0 || name == NamespaceName
 }
+
+func ModelResource(adapter ModelAdapter, node interface{}) bool {
+	if a, ok := adapter.(ResourceAdapter); ok {
+		return a.Resource(node)
+	}
+	return false
+}
+
+func ModelPrimitiveValue(adapter ModelAdapter, node interface{}) (AnyAccessor, bool) {
+	if a, ok := adapter.(ElementAdapter); ok {
+		return a.PrimitiveValue(node)
+	}
+	if _, ok := node.(ColAccessor); ok {
+		return nil, false
+	}
+	if n, ok := node.(AnyAccessor); ok {
+		return n, true
+	}
+	return nil, false
+}
+
+func ModelString(adapter ModelAdapter, node interface{}, name string) (string, error) {
+	value, err := adapter.Navigate(node, name)
+	if err != nil {
+		return "", err
+	}
+	value = unwrapModelItem(value)
+	if value == nil {
+		return "", nil
+	}
+	if s, ok := value.(StringAccessor); ok {
+		return s.String(), nil
+	}
+	if sys, err := adapter.CastToSystem(value); err == nil {
+		if s, ok := sys.(StringAccessor); ok {
+			return s.String(), nil
+		}
+	}
+	return "", nil
+}
+
+func ModelExtensions(adapter ModelAdapter, node interface{}) (ColAccessor, error) {
+	if a, ok := adapter.(ElementAdapter); ok {
+		return a.Extensions(node)
+	}
+
+	extensions, err := adapter.Navigate(node, "extension")
+	if extensions == nil || err != nil {
+		return nil, err
+	}
+	if col, ok := extensions.(ColAccessor); ok {
+		return col, nil
+	}
+	return NewColWithItem(adapter, extensions), nil
+}
+
+func ResolveResources(adapter ModelAdapter, root interface{}, node interface{}) (interface{}, interface{}, bool) {
+	return resolveResources(adapter, root, node, nil, nil)
+}
+
+func resolveResources(adapter ModelAdapter, current interface{}, node interface{}, resource interface{}, rootResource interface{}) (interface{}, interface{}, bool) {
+	if ModelResource(adapter, current) {
+		if resource == nil || !containedResource(adapter, resource, current) {
+			rootResource = current
+		}
+		resource = current
+	}
+	if sameNode(current, node) {
+		return resource, rootResource, true
+	}
+
+	children, err := adapter.Children(current)
+	if children == nil || err != nil {
+		return nil, nil, false
+	}
+	count := children.Count()
+	for i := 0; i < count; i++ {
+		if r, rr, found := resolveResources(adapter, children.Get(i), node, resource, rootResource); found {
+			return r, rr, true
+		}
+	}
+	return nil, nil, false
+}
+
+func containedResource(adapter ModelAdapter, resource interface{}, node interface{}) bool {
+	contained, err := adapter.Navigate(resource, "contained")
+	if err != nil {
+		return false
+	}
+	if col, ok := contained.(ColAccessor); ok {
+		count := col.Count()
+		for i := 0; i < count; i++ {
+			if sameNode(col.Get(i), node) {
+				return true
+			}
+		}
+		return false
+	}
+	return sameNode(contained, node)
+}
+
+func sameNode(node1 interface{}, node2 interface{}) bool {
+	t1, p1 := nodeIdentity(node1)
+	t2, p2 := nodeIdentity(node2)
+	return t1 != nil && t1 == t2 && p1 == p2
+}
+
+func nodeIdentity(node interface{}) (reflect.Type, uintptr) {
+	if w, ok := node.(ModelNodeWrapper); ok {
+		node = w.ModelNode()
+	}
+	if node == nil {
+		return nil, 0
+	}
+
+	v := reflect.ValueOf(node)
+	switch v.Kind() {
+	case reflect.Map, reflect.Ptr, reflect.Slice:
+		return v.Type(), v.Pointer()
+	}
+	return nil, 0
+}
@@ -0,0 +1,250 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"strings"
+)
+
+const (
+	ContextEnvVarName      = "context"
+	ResourceEnvVarName     = "resource"
+	RootResourceEnvVarName = "rootResource"
+	UCUMEnvVarName         = "ucum"
+	SCTEnvVarName          = "sct"
+	LOINCEnvVarName        = "loinc"
+	ValueSetEnvVarPrefix   = "vs-"
+	ExtensionEnvVarPrefix  = "ext-"
+)
+
+const (
+	valueSetBaseURI  = "http://hl7.org/fhir/ValueSet/"
+	extensionBaseURI = "http://hl7.org/fhir/StructureDefinition/"
+)
+
+var SCTSystemURI = NewString("http://snomed.info/sct")
+
+var LOINCSystemURI = NewString("http://loinc.org")
+
+type ContextBuilder struct {
+	modelAdapter  ModelAdapter
+	tracer        Tracer
+	node          interface{}
+	resource      interface{}
+	rootResource  interface{}
+	resolver      ReferenceResolver
+	terminology   TerminologyProvider
+	terminologies TerminologyService
+	envVars       map[string]interface{}
+}
+
+type contextType struct {
+	modelAdapter  ModelAdapter
+	tracer        Tracer
+	node          interface{}
+	resource      interface{}
+	rootResource  interface{}
+	resolver      ReferenceResolver
+	terminology   TerminologyProvider
+	terminologies MethodProvider
+	envVars       map[string]interface{}
+}
+
+func SystemEnvVarName(name string) bool {
+	switch name {
+	case ContextEnvVarName, ResourceEnvVarName, RootResourceEnvVarName, TerminologiesEnvVarName:
+		return true
+	}
+	_, found := SystemEnvVar(name)
+	return found
+}
+
+func SystemEnvVar(name string) (interface{}, bool) {
+	switch name {
+	case UCUMEnvVarName:
+		return UCUMSystemURI, true
+	case SCTEnvVarName:
+		return SCTSystemURI, true
+	case LOINCEnvVarName:
+		return LOINCSystemURI, true
+	}
+
+	if strings.HasPrefix(name, ValueSetEnvVarPrefix) && len(name) > len(ValueSetEnvVarPrefix) {
+		return NewString(valueSetBaseURI + name[len(ValueSetEnvVarPrefix):]), true
+	}
+	if strings.HasPrefix(name, ExtensionEnvVarPrefix) && len(name) > len(ExtensionEnvVarPrefix) {
+		return NewString(extensionBaseURI + name[len(ExtensionEnvVarPrefix):]), true
+	}
+	return nil, false
+}
+
+func NewContextBuilder(modelAdapter ModelAdapter) *ContextBuilder {
+	if modelAdapter == nil {
+		panic("no model adapter has been specified")
+	}
+	return &ContextBuilder{
+		modelAdapter: modelAdapter,
+		envVars:      make(map[string]interface{}),
+	}
+}
+
+func (b *ContextBuilder) Node(node interface{}) *ContextBuilder {
+	b.node = node
+	return b
+}
+
+func (b *ContextBuilder) Resource(resource interface{}) *ContextBuilder {
+	b.resource = resource
+	return b
+}
+
+func (b *ContextBuilder) RootResource(rootResource interface{}) *ContextBuilder {
+	b.rootResource = rootResource
+	return b
+}
+
+func (b *ContextBuilder) Tracer(tracer Tracer) *ContextBuilder {
+	b.tracer = tracer
+	return b
+}
+
+func (b *ContextBuilder) ReferenceResolver(resolver ReferenceResolver) *ContextBuilder {
+	b.resolver = resolver
+	return b
+}
+
+func (b *ContextBuilder) TerminologyProvider(provider TerminologyProvider) *ContextBuilder {
+	b.terminology = provider
+	return b
+}
+
+func (b *ContextBuilder) TerminologyService(service TerminologyService) *ContextBuilder {
+	b.terminologies = service
+	return b
+}
+
+func (b *ContextBuilder) EnvVar(name string, value interface{}) *ContextBuilder {
+	b.envVars[name] = value
+	return b
+}
+
+func (b *ContextBuilder) Build() ContextAccessor {
+	resource := b.resource
+	rootResource := b.rootResource
+	if resource == nil && rootResource != nil && b.node != nil {
+		if r, rr, found := ResolveResources(b.modelAdapter, rootResource, b.node); found {
+			resource, rootResource = r, rr
+		}
+	}
+	if resource == nil {
+		resource = b.node
+	}
+	if rootResource == nil {
+		rootResource = resource
+	}
+
+	var terminologies MethodProvider
+	if b.terminologies != nil {
+		terminologies = NewTerminologies(b.terminologies)
+	}
+
+	envVars := make(map[string]interface{}, len(b.envVars))
+	for name, value := range b.envVars {
+		envVars[name] = value
+	}
+
+	return &contextType{
+		modelAdapter:  b.modelAdapter,
+		tracer:        b.tracer,
+		node:          b.node,
+		resource:      resource,
+		rootResource:  rootResource,
+		resolver:      b.resolver,
+		terminology:   b.terminology,
+		terminologies: terminologies,
+		envVars:       envVars,
+	}
+}
+
+func (c *contextType) EnvVar(name string) (interface{}, bool) {
+	if value, found := c.envVars[name]; found {
+		return value, true
+	}
+
+	switch name {
+	case ContextEnvVarName:
+		if c.node != nil {
+			return c.node, true
+		}
+		return nil, false
+	case ResourceEnvVarName:
+		if c.resource != nil {
+			return c.resource, true
+		}
+		return nil, false
+	case RootResourceEnvVarName:
+		if c.rootResource != nil {
+			return c.rootResource, true
+		}
+		return nil, false
+	case TerminologiesEnvVarName:
+		if c.terminologies != nil {
+			return c.terminologies, true
+		}
+	}
+	return SystemEnvVar(name)
+}
+
+func (c *contextType) ContextNode() interface{} {
+	return c.node
+}
+
+func (c *contextType) ModelAdapter() ModelAdapter {
+	return c.modelAdapter
+}
+
+func (c *contextType) NewCol() ColModifier {
+	return NewCol(c.modelAdapter)
+}
+
+func (c *contextType) NewColWithItem(item interface{}) ColModifier {
+	return NewColWithItem(c.modelAdapter, item)
+}
+
+func (c *contextType) Tracer() Tracer {
+	return c.tracer
+}
+
+func (c *contextType) ReferenceResolver() ReferenceResolver {
+	return c.resolver
+}
+
+func (c *contextType) TerminologyProvider() TerminologyProvider {
+	return c.terminology
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testTracer struct {
+}
+
+func (t *testTracer) Enabled(string) bool {
+	return true
+}
+
+func (t *testTracer) Trace(string, ColAccessor) {
+}
+
+func TestNewContextBuilderNoAdapter(t *testing.T) {
+	assert.Panics(t, func() { NewContextBuilder(nil) })
+}
+
+func TestContextBuilderDefault(t *testing.T) {
+	adapter := newTestModel(t)
+	ctx := NewContextBuilder(adapter).Build()
+
+	assert.Same(t, adapter, ctx.ModelAdapter())
+	assert.Nil(t, ctx.Tracer())
+	assert.Nil(t, ctx.ContextNode())
+
+	v, found := ctx.EnvVar("context")
+	assert.False(t, found)
+	assert.Nil(t, v)
+	v, found = ctx.EnvVar("resource")
+	assert.False(t, found)
+	assert.Nil(t, v)
+	v, found = ctx.EnvVar("rootResource")
+	assert.False(t, found)
+	assert.Nil(t, v)
+	v, found = ctx.EnvVar("ucum")
+	assert.True(t, found)
+	assert.Equal(t, UCUMSystemURI, v)
+	v, found = ctx.EnvVar("sct")
+	assert.True(t, found)
+	assert.Equal(t, SCTSystemURI, v)
+	v, found = ctx.EnvVar("test")
+	assert.False(t, found)
+	assert.Nil(t, v)
+}
+
+func TestContextBuilderNode(t *testing.T) {
+	node := newTestModelNode(10, false, testTypeSpec)
+	ctx := NewContextBuilder(newTestModel(t)).Node(node).Build()
+
+	assert.Same(t, node, ctx.ContextNode())
+	v, found := ctx.EnvVar("context")
+	assert.True(t, found)
+	assert.Same(t, node, v)
+	v, found = ctx.EnvVar("resource")
+	assert.True(t, found)
+	assert.Same(t, node, v)
+	v, found = ctx.EnvVar("rootResource")
+	assert.True(t, found)
+	assert.Same(t, node, v)
+}
+
+func TestContextBuilderResources(t *testing.T) {
+	node := newTestModelNode(10, false, testTypeSpec)
+	resource := newTestModelNode(11, false, testTypeSpec)
+	rootResource := newTestModelNode(12, false, testTypeSpec)
+	ctx := NewContextBuilder(newTestModel(t)).Node(node).
+		Resource(resource).RootResource(rootResource).Build()
+
+	v, _ := ctx.EnvVar("context")
+	assert.Same(t, node, v)
+	v, _ = ctx.EnvVar("resource")
+	assert.Same(t, resource, v)
+	v, _ = ctx.EnvVar("rootResource")
+	assert.Same(t, rootResource, v)
+}
+
+func TestContextBuilderResourceOnly(t *testing.T) {
+	node := newTestModelNode(10, false, testTypeSpec)
+	resource := newTestModelNode(11, false, testTypeSpec)
+	ctx := NewContextBuilder(newTestModel(t)).Node(node).Resource(resource).Build()
+
+	v, _ := ctx.EnvVar("rootResource")
+	assert.Same(t, resource, v)
+}
+
+func TestContextBuilderEnvVar(t *testing.T) {
+	b := NewContextBuilder(newTestModel(t)).
+		EnvVar("test", NewString("value")).
+		EnvVar("ucum", NewString("other"))
+	ctx := b.Build()
+	b.EnvVar("test", NewString("changed"))
+
+	v, found := ctx.EnvVar("test")
+	assert.True(t, found)
+	assert.Equal(t, NewString("value"), v)
+	v, found = ctx.EnvVar("ucum")
+	assert.True(t, found)
+	assert.Equal(t, NewString("other"), v)
+}
+
+func TestContextBuilderTracer(t *testing.T) {
+	tracer := &testTracer{}
+	ctx := NewContextBuilder(newTestModel(t)).Tracer(tracer).Build()
+	assert.Same(t, tracer, ctx.Tracer())
+}
+
+func TestContextBuilderNewCol(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Build()
+
+	col := ctx.NewCol()
+	assert.Equal(t, 0, col.Count())
+
+	col = ctx.NewColWithItem(NewString("test"))
+	if assert.Equal(t, 1, col.Count()) {
+		assert.Equal(t, NewString("test"), col.Get(0))
+	}
+}
+
+func TestSystemEnvVarName(t *testing.T) {
+	assert.True(t, SystemEnvVarName(ContextEnvVarName))
+	assert.True(t, SystemEnvVarName(ResourceEnvVarName))
+	assert.True(t, SystemEnvVarName(RootResourceEnvVarName))
+	assert.True(t, SystemEnvVarName(UCUMEnvVarName))
+	assert.True(t, SystemEnvVarName(SCTEnvVarName))
+	assert.True(t, SystemEnvVarName(LOINCEnvVarName))
+	assert.True(t, SystemEnvVarName(TerminologiesEnvVarName))
+	assert.True(t, SystemEnvVarName("vs-administrative-gender"))
+	assert.True(t, SystemEnvVarName("ext-patient-birthTime"))
+	assert.False(t, SystemEnvVarName("vs-"))
+	assert.False(t, SystemEnvVarName("test"))
+}
+
+func TestSystemEnvVar(t *testing.T) {
+	v, found := SystemEnvVar("ucum")
+	assert.True(t, found)
+	assert.Equal(t, NewString("http://unitsofmeasure.org"), v)
+	v, found = SystemEnvVar("sct")
+	assert.True(t, found)
+	assert.Equal(t, NewString("http://snomed.info/sct"), v)
+	v, found = SystemEnvVar("loinc")
+	assert.True(t, found)
+	assert.Equal(t, NewString("http://loinc.org"), v)
+	v, found = SystemEnvVar("vs-administrative-gender")
+	assert.True(t, found)
+	assert.Equal(t, NewString("http://hl7.org/fhir/ValueSet/administrative-gender"), v)
+	v, found = SystemEnvVar("ext-patient-birthTime")
+	assert.True(t, found)
+	assert.Equal(t, NewString("http://hl7.org/fhir/StructureDefinition/patient-birthTime"), v)
+	v, found = SystemEnvVar("ext-")
+	assert.False(t, found)
+	assert.Nil(t, v)
+	v, found = SystemEnvVar("context")
+	assert.False(t, found)
+	assert.Nil(t, v)
+}
@@ -29,6 +29,7 @@
 package hipathsys
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -259,6 +260,24 @@ func TestCastModelTypeSystemIncompatible(t *testing.T) {
 	assert.Nil(t, res, "empty result expected")
 }
 
+func TestCastModelTypeSystemWithModelType(t *testing.T) {
+	ctx := newTestContext(t)
+	n := NewString("Test 123")
+	res, err := CastModelType(ctx.ModelAdapter(), n,
+		NewFQTypeName("string", "TEST"))
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, n, res)
+}
+
+func TestCastModelTypeSystemWithModelTypeIncompatible(t *testing.T) {
+	ctx := newTestContext(t)
+	n := NewString("Test 123")
+	res, err := CastModelType(ctx.ModelAdapter(), n,
+		NewFQTypeName("decimal", "Test"))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
 func TestCastModelTypeModelSelf(t *testing.T) {
 	ctx := newTestContext(t)
 	n := newTestModelNode(17.4, false, testTypeSpec)
@@ -296,3 +315,180 @@ func TestCastModelTypeModelError(t *testing.T) {
 	assert.Error(t, err, "error expected")
 	assert.Nil(t, res, "empty result expected")
 }
+
+func TestModelTypeInfoNil(t *testing.T) {
+	ctx := newTestContext(t)
+	assert.Nil(t, ModelTypeInfo(ctx.ModelAdapter(), nil))
+}
+
+func TestModelTypeInfoSystem(t *testing.T) {
+	ctx := newTestContext(t)
+	assert.Same(t, stringTypeInfo, ModelTypeInfo(ctx.ModelAdapter(), NewString("test")))
+}
+
+func TestModelTypeInfoModel(t *testing.T) {
+	ctx := newTestContext(t)
+	ti := ModelTypeInfo(ctx.ModelAdapter(), newTestModelNode(10, false, testTypeSpec))
+	if assert.Implements(t, (*SimpleTypeInfoAccessor)(nil), ti) {
+		assert.Equal(t, NewString("TEST"), ti.Namespace())
+		assert.Equal(t, NewString("type1"), ti.(SimpleTypeInfoAccessor).Name())
+		assert.Equal(t, NewString("TEST.base"), ti.(SimpleTypeInfoAccessor).BaseType())
+	}
+}
+
+func TestModelNavigateTypeInfo(t *testing.T) {
+	ctx := newTestContext(t)
+	res, err := ModelNavigate(ctx.ModelAdapter(), stringTypeInfo, "name")
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("String"), res)
+}
+
+func TestModelNavigateTypeInfoCol(t *testing.T) {
+	ctx := newTestContext(t)
+	col := NewCol(ctx.ModelAdapter())
+	col.Add(stringTypeInfo)
+	col.Add(classInfoTypeInfo)
+
+	res, err := ModelNavigate(ctx.ModelAdapter(), col, "name")
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*ColAccessor)(nil), res) {
+		c := res.(ColAccessor)
+		if assert.Equal(t, 2, c.Count()) {
+			assert.Equal(t, NewString("String"), c.Get(0))
+			assert.Equal(t, NewString("ClassInfo"), c.Get(1))
+		}
+	}
+
+	res, err = ModelNavigate(ctx.ModelAdapter(), col, "element")
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*ColAccessor)(nil), res) {
+		assert.Equal(t, 4, res.(ColAccessor).Count())
+	}
+}
+
+func TestModelResourceNoAdapter(t *testing.T) {
+	assert.False(t, ModelResource(newTestModel(t), NewString("test")))
+}
+
+func TestSameNode(t *testing.T) {
+	m := map[string]interface{}{"a": 1}
+	s := NewString("test")
+	assert.True(t, sameNode(m, m))
+	assert.False(t, sameNode(m, map[string]interface{}{"a": 1}))
+	assert.True(t, sameNode(s, s))
+	assert.False(t, sameNode(s, NewString("test")))
+	assert.False(t, sameNode(s, m))
+	assert.False(t, sameNode(10, 10))
+	assert.False(t, sameNode(nil, nil))
+}
+
+type testNavigateModel struct {
+	testModel
+	extensions interface{}
+}
+
+func (a *testNavigateModel) Navigate(_ interface{}, name string) (interface{}, error) {
+	if name != "extension" {
+		return nil, fmt.Errorf("unexpected name: %s", name)
+	}
+	return a.extensions, nil
+}
+
+type testElementModel struct {
+	testModel
+}
+
+func (a *testElementModel) PrimitiveValue(node interface{}) (AnyAccessor, bool) {
+	if n, ok := node.(StringAccessor); ok && n.String() == "value" {
+		return n, true
+	}
+	return nil, false
+}
+
+func (a *testElementModel) Extensions(interface{}) (ColAccessor, error) {
+	return NewColWithItem(a, NewString("ext")), nil
+}
+
+func TestModelPrimitiveValueNoAdapter(t *testing.T) {
+	adapter := newTestModel(t)
+
+	v, found := ModelPrimitiveValue(adapter, NewString("test"))
+	assert.True(t, found)
+	assert.Equal(t, NewString("test"), v)
+
+	v, found = ModelPrimitiveValue(adapter, NewColWithItem(adapter, NewString("test")))
+	assert.False(t, found)
+	assert.Nil(t, v)
+
+	v, found = ModelPrimitiveValue(adapter, newTestModelNode(10, false, testTypeSpec))
+	assert.False(t, found)
+	assert.Nil(t, v)
+}
+
+func TestModelPrimitiveValueAdapter(t *testing.T) {
+	adapter := &testElementModel{testModel{t}}
+
+	v, found := ModelPrimitiveValue(adapter, NewString("value"))
+	assert.True(t, found)
+	assert.Equal(t, NewString("value"), v)
+
+	v, found = ModelPrimitiveValue(adapter, NewString("test"))
+	assert.False(t, found)
+	assert.Nil(t, v)
+}
+
+func TestModelExtensionsAdapter(t *testing.T) {
+	adapter := &testElementModel{testModel{t}}
+
+	res, err := ModelExtensions(adapter, NewString("test"))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, NewString("ext"), res.Get(0))
+	}
+}
+
+func TestModelExtensionsNavigate(t *testing.T) {
+	adapter := &testNavigateModel{testModel: testModel{t}, extensions: NewString("ext")}
+
+	res, err := ModelExtensions(adapter, NewString("test"))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, res) && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, NewString("ext"), res.Get(0))
+	}
+}
+
+func TestModelExtensionsNavigateCol(t *testing.T) {
+	adapter := &testNavigateModel{testModel: testModel{t}}
+	adapter.extensions = NewColWithItem(adapter, NewString("ext"))
+
+	res, err := ModelExtensions(adapter, NewString("test"))
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, adapter.extensions, res)
+}
+
+func TestModelExtensionsNavigateNil(t *testing.T) {
+	adapter := &testNavigateModel{testModel: testModel{t}}
+
+	res, err := ModelExtensions(adapter, NewString("test"))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestDelegatingContext(t *testing.T) {
+	delegate := NewContextBuilder(newTestModel(t)).
+		Node(NewString("node")).
+		Tracer(&testTracer{}).
+		EnvVar("test", NewString("value")).
+		Build()
+	ctx := NewDelegatingContext(delegate)
+
+	assert.Same(t, delegate, ctx.Delegate())
+	assert.Same(t, delegate.ModelAdapter(), ctx.ModelAdapter())
+	assert.Same(t, delegate.Tracer(), ctx.Tracer())
+	assert.Same(t, delegate.ContextNode(), ctx.ContextNode())
+	v, found := ctx.EnvVar("test")
+	assert.True(t, found)
+	assert.Equal(t, NewString("value"), v)
+	assert.Equal(t, 0, ctx.NewCol().Count())
+	assert.Equal(t, 1, ctx.NewColWithItem(NewString("test")).Count())
+}
@@ -44,6 +44,7 @@ const (
 	DateTimeDataType
 	TimeDataType
 	QuantityDataType
+	LongDataType
 )
 
 var anyTypeNameString = NewString("System.Any")
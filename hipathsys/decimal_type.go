@@ -333,6 +333,12 @@ func (t *decimalType) Calc(operand DecimalValueAccessor, op ArithmeticOps) (Deci
 		return nil, fmt.Errorf("arithmetic operator not supported: %c", op)
 	}
 
+	if _, ok := operand.(NumberAccessor); ok {
+		return decimalCalc(t, operand.Value(), op), nil
+	}
+	if q, ok := operand.(QuantityAccessor); ok {
+		return calcNumberQuantity(t, q, op)
+	}
 	return operand.WithValue(decimalCalc(t, operand.Value(), op)), nil
 }
 
@@ -370,6 +376,18 @@ func decimalCalc(leftOperand NumberAccessor, rightOperand DecimalAccessor, op Ar
 	}
 }
 
+func normalizedDecimal(value DecimalAccessor) DecimalAccessor {
+	d := value.Decimal()
+	for d.Exponent() < 0 {
+		t := d.Truncate(-d.Exponent() - 1)
+		if !t.Equal(d) {
+			break
+		}
+		d = t
+	}
+	return NewDecimal(d)
+}
+
 func (t *decimalType) Abs() DecimalValueAccessor {
 	return NewDecimal(t.value.Abs())
 }
@@ -293,6 +293,14 @@ func TestDecimalCalcAddition(t *testing.T) {
 	}
 }
 
+func TestDecimalCalcAdditionInteger(t *testing.T) {
+	r, err := NewDecimalFloat64(1.5).Calc(NewInteger(1), AdditionOp)
+	assert.NoError(t, err)
+	if assert.Implements(t, (*DecimalAccessor)(nil), r) {
+		assert.Equal(t, 2.5, r.(DecimalAccessor).Float64())
+	}
+}
+
 func TestDecimalCalcSubtraction(t *testing.T) {
 	r, err := NewDecimalFloat64(150.90).Calc(NewDecimalFloat64(100.6), SubtractionOp)
 	e := NewDecimalFloat64(50.3)
@@ -385,6 +393,33 @@ func TestDecimalCalcQuantity(t *testing.T) {
 	assert.True(t, e.Equal(r))
 }
 
+func TestDecimalCalcDivisionQuantity(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(4), NewString("mg"))
+	r, err := NewDecimalFloat64(2).Calc(q, DivisionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 0.5, r.Value().Float64())
+		assert.Equal(t, "/mg", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestDecimalCalcDivisionQuantityNotInvertible(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(4), NewString("xyz"))
+	r, err := NewDecimalFloat64(2).Calc(q, DivisionOp)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r)
+}
+
+func TestDecimalCalcMultiplicationQuantity(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(4), NewString("/mg"))
+	r, err := NewDecimalFloat64(2.5).Calc(q, MultiplicationOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 10.0, r.Value().Float64())
+		assert.Equal(t, "/mg", r.(QuantityAccessor).Unit().String())
+	}
+}
+
 func TestDecimalCalcNotSupportedOp(t *testing.T) {
 	q := NewQuantity(NewDecimalFloat64(47.2), NewString("m"))
 	r, err := NewDecimalFloat64(1.5).Calc(q, ModOp)
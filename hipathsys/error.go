@@ -28,9 +28,47 @@
 
 package hipathsys
 
+import (
+	"errors"
+	"fmt"
+)
+
+type ErrorKind int
+
+const (
+	UndefinedErrorKind ErrorKind = iota
+	TypeMismatchErrorKind
+	SingletonExpectedErrorKind
+	UnknownEnvVarErrorKind
+	AdapterErrorKind
+	TerminologyErrorKind
+)
+
+var (
+	ErrTypeMismatch      = NewKindError(TypeMismatchErrorKind, "type mismatch")
+	ErrSingletonExpected = NewKindError(SingletonExpectedErrorKind, "singleton expected")
+	ErrUnknownEnvVar     = NewKindError(UnknownEnvVarErrorKind, "unknown environment variable")
+	ErrAdapter           = NewKindError(AdapterErrorKind, "model adapter failure")
+	ErrTerminology       = NewKindError(TerminologyErrorKind, "terminology failure")
+)
+
+type KindError struct {
+	kind  ErrorKind
+	msg   string
+	cause error
+}
+
+type SourceError struct {
+	line   int
+	column int
+	source string
+	cause  error
+}
+
 type Error struct {
 	msg   string
 	items []*ErrorItem
+	cause error
 }
 
 type ErrorItem struct {
@@ -40,7 +78,46 @@ type ErrorItem struct {
 }
 
 func NewError(msg string, items []*ErrorItem) *Error {
-	return &Error{msg, items}
+	return &Error{msg, items, nil}
+}
+
+func NewErrorWithCause(msg string, items []*ErrorItem, cause error) *Error {
+	return &Error{msg, items, cause}
+}
+
+func NewKindError(kind ErrorKind, msg string) *KindError {
+	return &KindError{kind, msg, nil}
+}
+
+func NewKindErrorf(kind ErrorKind, format string, a ...interface{}) *KindError {
+	err := fmt.Errorf(format, a...)
+	return &KindError{kind, err.Error(), errors.Unwrap(err)}
+}
+
+func NewAdapterError(cause error) error {
+	if cause == nil || ErrorKindOf(cause) != UndefinedErrorKind {
+		return cause
+	}
+	return &KindError{AdapterErrorKind, cause.Error(), cause}
+}
+
+func NewTerminologyError(cause error) error {
+	if cause == nil || ErrorKindOf(cause) != UndefinedErrorKind {
+		return cause
+	}
+	return &KindError{TerminologyErrorKind, cause.Error(), cause}
+}
+
+func ErrorKindOf(err error) ErrorKind {
+	var kindErr *KindError
+	if errors.As(err, &kindErr) {
+		return kindErr.kind
+	}
+	return UndefinedErrorKind
+}
+
+func NewSourceError(line int, column int, source string, cause error) *SourceError {
+	return &SourceError{line, column, source, cause}
 }
 
 func NewErrorItem(line int, column int, msg string) *ErrorItem {
@@ -55,6 +132,10 @@ func (e *Error) Items() []*ErrorItem {
 	return e.items
 }
 
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
 func (e *ErrorItem) Line() int {
 	return e.line
 }
@@ -66,3 +147,58 @@ func (e *ErrorItem) Column() int {
 func (e *ErrorItem) Msg() string {
 	return e.msg
 }
+
+func (k ErrorKind) String() string {
+	switch k {
+	case TypeMismatchErrorKind:
+		return "type mismatch"
+	case SingletonExpectedErrorKind:
+		return "singleton expected"
+	case UnknownEnvVarErrorKind:
+		return "unknown environment variable"
+	case AdapterErrorKind:
+		return "model adapter failure"
+	case TerminologyErrorKind:
+		return "terminology failure"
+	}
+	return "undefined"
+}
+
+func (e *KindError) Kind() ErrorKind {
+	return e.kind
+}
+
+func (e *KindError) Error() string {
+	return e.msg
+}
+
+func (e *KindError) Unwrap() error {
+	return e.cause
+}
+
+func (e *KindError) Is(target error) bool {
+	if t, ok := target.(*KindError); ok {
+		return t.kind == e.kind
+	}
+	return false
+}
+
+func (e *SourceError) Line() int {
+	return e.line
+}
+
+func (e *SourceError) Column() int {
+	return e.column
+}
+
+func (e *SourceError) Source() string {
+	return e.source
+}
+
+func (e *SourceError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *SourceError) Unwrap() error {
+	return e.cause
+}
@@ -29,6 +29,8 @@
 package hipathsys
 
 import (
+	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -56,3 +58,98 @@ func TestErrorItem(t *testing.T) {
 	assert.Equal(t, 12, item.Column())
 	assert.Equal(t, "Test Error", item.Msg())
 }
+
+func TestNewErrorWithCause(t *testing.T) {
+	cause := fmt.Errorf("test cause")
+	e := NewErrorWithCause("test message", nil, cause)
+	assert.Equal(t, "test message", e.Error())
+	assert.Nil(t, e.Items())
+	assert.Same(t, cause, e.Unwrap())
+	assert.True(t, errors.Is(e, cause))
+}
+
+func TestNewErrorNoCause(t *testing.T) {
+	e := NewError("test message", nil)
+	assert.Nil(t, e.Unwrap())
+}
+
+func TestKindError(t *testing.T) {
+	err := NewKindError(TypeMismatchErrorKind, "test message")
+	assert.Equal(t, TypeMismatchErrorKind, err.Kind())
+	assert.Equal(t, "test message", err.Error())
+	assert.Nil(t, err.Unwrap())
+	assert.True(t, errors.Is(err, ErrTypeMismatch))
+	assert.False(t, errors.Is(err, ErrSingletonExpected))
+	assert.False(t, errors.Is(err, fmt.Errorf("test message")))
+}
+
+func TestNewKindErrorf(t *testing.T) {
+	cause := fmt.Errorf("test cause")
+	err := NewKindErrorf(SingletonExpectedErrorKind, "test %d: %w", 10, cause)
+	assert.Equal(t, SingletonExpectedErrorKind, err.Kind())
+	assert.Equal(t, "test 10: test cause", err.Error())
+	assert.Same(t, cause, err.Unwrap())
+	assert.True(t, errors.Is(err, cause))
+	assert.True(t, errors.Is(err, ErrSingletonExpected))
+}
+
+func TestErrorKindOf(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", NewKindError(UnknownEnvVarErrorKind, "test"))
+	assert.Equal(t, UnknownEnvVarErrorKind, ErrorKindOf(err))
+	assert.Equal(t, UndefinedErrorKind, ErrorKindOf(fmt.Errorf("test")))
+	assert.Equal(t, UndefinedErrorKind, ErrorKindOf(nil))
+}
+
+func TestNewAdapterError(t *testing.T) {
+	cause := fmt.Errorf("test cause")
+	err := NewAdapterError(cause)
+	assert.Equal(t, "test cause", err.Error())
+	assert.True(t, errors.Is(err, ErrAdapter))
+	assert.True(t, errors.Is(err, cause))
+}
+
+func TestNewAdapterErrorKind(t *testing.T) {
+	cause := NewKindError(TypeMismatchErrorKind, "test")
+	assert.Same(t, cause, NewAdapterError(cause))
+}
+
+func TestNewAdapterErrorNil(t *testing.T) {
+	assert.Nil(t, NewAdapterError(nil))
+}
+
+func TestNewTerminologyError(t *testing.T) {
+	cause := fmt.Errorf("test cause")
+	err := NewTerminologyError(cause)
+	assert.Equal(t, "test cause", err.Error())
+	assert.True(t, errors.Is(err, ErrTerminology))
+	assert.True(t, errors.Is(err, cause))
+}
+
+func TestNewTerminologyErrorKind(t *testing.T) {
+	cause := NewKindError(TypeMismatchErrorKind, "test")
+	assert.Same(t, cause, NewTerminologyError(cause))
+}
+
+func TestNewTerminologyErrorNil(t *testing.T) {
+	assert.Nil(t, NewTerminologyError(nil))
+}
+
+func TestErrorKindString(t *testing.T) {
+	assert.Equal(t, "type mismatch", TypeMismatchErrorKind.String())
+	assert.Equal(t, "singleton expected", SingletonExpectedErrorKind.String())
+	assert.Equal(t, "unknown environment variable", UnknownEnvVarErrorKind.String())
+	assert.Equal(t, "model adapter failure", AdapterErrorKind.String())
+	assert.Equal(t, "terminology failure", TerminologyErrorKind.String())
+	assert.Equal(t, "undefined", UndefinedErrorKind.String())
+}
+
+func TestSourceError(t *testing.T) {
+	cause := NewKindError(TypeMismatchErrorKind, "test message")
+	err := NewSourceError(2, 7, "a + b", cause)
+	assert.Equal(t, 2, err.Line())
+	assert.Equal(t, 7, err.Column())
+	assert.Equal(t, "a + b", err.Source())
+	assert.Equal(t, "test message", err.Error())
+	assert.Same(t, cause, err.Unwrap())
+	assert.True(t, errors.Is(err, ErrTypeMismatch))
+}
@@ -85,11 +85,24 @@ type Evaluator interface {
 	Evaluate(ctx ContextAccessor, node interface{}, loop Looper) (interface{}, error)
 }
 
+type lazyArg struct {
+	evaluator Evaluator
+	ctx       ContextAccessor
+	node      interface{}
+	loop      Looper
+}
+
+type LazyArg interface {
+	Evaluator() Evaluator
+	Evaluate() (interface{}, error)
+}
+
 type BaseFunction struct {
 	name           string
 	evaluatorParam int
 	minParams      int
 	maxParams      int
+	lazyParams     []int
 }
 
 type FunctionExecutor interface {
@@ -100,8 +113,39 @@ type FunctionExecutor interface {
 	Execute(ctx ContextAccessor, node interface{}, args []interface{}, loop Looper) (interface{}, error)
 }
 
+type LazyParamsFunctionExecutor interface {
+	FunctionExecutor
+	LazyParam(pos int) bool
+}
+
+func NewLazyArg(evaluator Evaluator, ctx ContextAccessor, node interface{}, loop Looper) LazyArg {
+	return &lazyArg{evaluator, ctx, node, loop}
+}
+
+func (a *lazyArg) Evaluator() Evaluator {
+	return a.evaluator
+}
+
+func (a *lazyArg) Evaluate() (interface{}, error) {
+	if a.evaluator == nil {
+		return nil, nil
+	}
+	return a.evaluator.Evaluate(a.ctx, a.node, a.loop)
+}
+
+func EvaluateArg(arg interface{}) (interface{}, error) {
+	if a, ok := arg.(LazyArg); ok {
+		return a.Evaluate()
+	}
+	return arg, nil
+}
+
 func NewBaseFunction(name string, evaluatorParam int, minParams int, maxParams int) BaseFunction {
-	return BaseFunction{name, evaluatorParam, minParams, maxParams}
+	return BaseFunction{name, evaluatorParam, minParams, maxParams, nil}
+}
+
+func NewLazyBaseFunction(name string, evaluatorParam int, minParams int, maxParams int, lazyParams ...int) BaseFunction {
+	return BaseFunction{name, evaluatorParam, minParams, maxParams, lazyParams}
 }
 
 func (f *BaseFunction) Name() string {
@@ -119,3 +163,12 @@ func (f *BaseFunction) MinParams() int {
 func (f *BaseFunction) MaxParams() int {
 	return f.maxParams
 }
+
+func (f *BaseFunction) LazyParam(pos int) bool {
+	for _, p := range f.lazyParams {
+		if p == pos {
+			return true
+		}
+	}
+	return false
+}
@@ -81,6 +81,48 @@ func TestNewBaseFunction(t *testing.T) {
 	assert.Equal(t, 5, bf.MaxParams())
 }
 
+func TestNewLazyBaseFunction(t *testing.T) {
+	bf := NewLazyBaseFunction("test", -1, 1, 5, 1, 3)
+	assert.Equal(t, "test", bf.Name())
+	assert.Equal(t, -1, bf.EvaluatorParam())
+	assert.False(t, bf.LazyParam(0))
+	assert.True(t, bf.LazyParam(1))
+	assert.False(t, bf.LazyParam(2))
+	assert.True(t, bf.LazyParam(3))
+}
+
+func TestLazyArg(t *testing.T) {
+	ctx := newTestContext(t)
+	evaluator := &testNodeEvaluator{}
+	l := NewLoop(nil)
+	a := NewLazyArg(evaluator, ctx, NewString("test"), l)
+	assert.Same(t, evaluator, a.Evaluator())
+	assert.Equal(t, 0, evaluator.count)
+
+	res, err := a.Evaluate()
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("test"), res)
+	assert.Same(t, ctx, evaluator.ctx)
+	assert.Same(t, l, evaluator.loop)
+	assert.Equal(t, 1, evaluator.count)
+}
+
+func TestLazyArgNilEvaluator(t *testing.T) {
+	res, err := NewLazyArg(nil, newTestContext(t), NewString("test"), nil).Evaluate()
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestEvaluateArg(t *testing.T) {
+	res, err := EvaluateArg(NewString("test"))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("test"), res)
+
+	res, err = EvaluateArg(NewLazyArg(&testNodeEvaluator{}, nil, NewString("lazy"), nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("lazy"), res)
+}
+
 type testEvaluator struct {
 }
 
@@ -91,3 +133,16 @@ func newTestEvaluator() Evaluator {
 func (t testEvaluator) Evaluate(ContextAccessor, interface{}, Looper) (interface{}, error) {
 	return nil, nil
 }
+
+type testNodeEvaluator struct {
+	ctx   ContextAccessor
+	loop  Looper
+	count int
+}
+
+func (t *testNodeEvaluator) Evaluate(ctx ContextAccessor, node interface{}, loop Looper) (interface{}, error) {
+	t.ctx = ctx
+	t.loop = loop
+	t.count++
+	return node, nil
+}
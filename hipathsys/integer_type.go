@@ -60,7 +60,7 @@ func NewIntegerWithSource(value int32, source interface{}) IntegerAccessor {
 }
 
 func ParseInteger(value string) (IntegerAccessor, error) {
-	if i, err := strconv.Atoi(value); err != nil {
+	if i, err := strconv.ParseInt(value, 10, 32); err != nil {
 		return nil, fmt.Errorf("not an integer: %s", value)
 	} else {
 		return NewInteger(int32(i)), nil
@@ -148,7 +148,7 @@ func (t *integerType) ArithmeticOpSupported(ArithmeticOps) bool {
 }
 
 func (t *integerType) Negate() AnyAccessor {
-	return newInteger(-t.value, nil)
+	return integerResult(-int64(t.value))
 }
 
 func (t *integerType) Equal(node interface{}) bool {
@@ -257,11 +257,11 @@ func (t *integerType) Calc(operand DecimalValueAccessor, op ArithmeticOps) (Deci
 		pov := ov.Primitive()
 		switch op {
 		case AdditionOp:
-			return NewInteger(t.Int() + pov), nil
+			return integerResult(int64(t.Int()) + int64(pov)), nil
 		case SubtractionOp:
-			return NewInteger(t.Int() - pov), nil
+			return integerResult(int64(t.Int()) - int64(pov)), nil
 		case MultiplicationOp:
-			return NewInteger(t.Int() * pov), nil
+			return integerResult(int64(t.Int()) * int64(pov)), nil
 		case DivisionOp:
 			if pov == 0 {
 				return nil, nil
@@ -271,7 +271,7 @@ func (t *integerType) Calc(operand DecimalValueAccessor, op ArithmeticOps) (Deci
 			if pov == 0 {
 				return nil, nil
 			}
-			return NewInteger(t.Int() / pov), nil
+			return integerResult(int64(t.Int()) / int64(pov)), nil
 		case ModOp:
 			if pov == 0 {
 				return nil, nil
@@ -281,12 +281,28 @@ func (t *integerType) Calc(operand DecimalValueAccessor, op ArithmeticOps) (Deci
 			panic(fmt.Sprintf("Unhandled operator: %d", op))
 		}
 	}
+	if _, ok := operand.(LongAccessor); ok {
+		return NewLong(int64(t.value)).Calc(operand, op)
+	}
 
+	if q, ok := operand.(QuantityAccessor); ok {
+		return calcNumberQuantity(t, q, op)
+	}
 	return operand.WithValue(decimalCalc(t, operand.Value(), op)), nil
 }
 
+func integerResult(value int64) DecimalValueAccessor {
+	if value < math.MinInt32 || value > math.MaxInt32 {
+		return nil
+	}
+	return NewInteger(int32(value))
+}
+
 func (t *integerType) Abs() DecimalValueAccessor {
-	return NewInteger(int32(math.Abs(float64(t.Int()))))
+	if t.value < 0 {
+		return integerResult(-int64(t.value))
+	}
+	return t
 }
 
 func IntegerValue(node interface{}) interface{} {
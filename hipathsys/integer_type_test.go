@@ -31,6 +31,7 @@ package hipathsys
 import (
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -87,6 +88,11 @@ func TestIntegerNegateNeg(t *testing.T) {
 	}
 }
 
+func TestIntegerNegateOverflow(t *testing.T) {
+	assert.Nil(t, NewInteger(math.MinInt32).Negate())
+	assert.Equal(t, NewInteger(math.MinInt32+1), NewInteger(math.MaxInt32).Negate())
+}
+
 func TestIntegerValue(t *testing.T) {
 	o := NewInteger(-4711)
 	assert.Equal(t, int32(-4711), o.Int())
@@ -292,6 +298,21 @@ func TestIntegerCalcMultiplication(t *testing.T) {
 	}
 }
 
+func TestIntegerCalcOverflow(t *testing.T) {
+	r, err := NewInteger(math.MaxInt32).Calc(NewInteger(1), AdditionOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "addition overflow")
+	r, err = NewInteger(math.MinInt32).Calc(NewInteger(1), SubtractionOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "subtraction overflow")
+	r, err = NewInteger(math.MaxInt32).Calc(NewInteger(2), MultiplicationOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "multiplication overflow")
+	r, err = NewInteger(math.MinInt32).Calc(NewInteger(-1), DivOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "div overflow")
+}
+
 func TestIntegerCalcDivision(t *testing.T) {
 	r, err := NewInteger(150).Calc(NewInteger(8), DivisionOp)
 	e := NewDecimalFloat64(18.75)
@@ -361,6 +382,26 @@ func TestIntegerCalcQuantity(t *testing.T) {
 	assert.True(t, e.Equal(r))
 }
 
+func TestIntegerCalcDivisionQuantity(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(1), NewString("mg"))
+	r, err := NewInteger(2).Calc(q, DivisionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 2.0, r.Value().Float64())
+		assert.Equal(t, "/mg", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestIntegerCalcDivisionCalendarQuantity(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(4), NewString("days"))
+	r, err := NewInteger(2).Calc(q, DivisionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 0.5, r.Value().Float64())
+		assert.Equal(t, "/d", r.(QuantityAccessor).Unit().String())
+	}
+}
+
 func TestIntegerCalcNotSupportedOp(t *testing.T) {
 	q := NewQuantity(NewDecimalFloat64(47.2), NewString("m"))
 	r, err := NewInteger(2).Calc(q, ModOp)
@@ -423,6 +464,11 @@ func TestIntegerAbsNeg(t *testing.T) {
 	assert.Equal(t, 10.0, res.Value().Float64())
 }
 
+func TestIntegerAbsOverflow(t *testing.T) {
+	assert.Nil(t, NewInteger(math.MinInt32).Abs())
+	assert.Equal(t, NewInteger(math.MaxInt32), NewInteger(math.MinInt32+1).Abs())
+}
+
 func TestIntegerIsOne(t *testing.T) {
 	assert.True(t, NewInteger(1).One())
 }
@@ -0,0 +1,164 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import "fmt"
+
+type LimitKind int
+
+const (
+	CollectionSizeLimit LimitKind = iota + 1
+	RepeatDepthLimit
+	RepeatIterationsLimit
+	FunctionCallsLimit
+	StringLengthLimit
+)
+
+type Limits struct {
+	MaxCollectionSize   int
+	MaxRepeatDepth      int
+	MaxRepeatIterations int
+	MaxFunctionCalls    int
+	MaxStringLength     int
+}
+
+type LimitError struct {
+	kind LimitKind
+	max  int
+}
+
+type limitedContext struct {
+	DelegatingContext
+	limits        Limits
+	functionCalls int
+}
+
+type LimitedContextAccessor interface {
+	ContextAccessor
+	Limits() Limits
+	Delegate() ContextAccessor
+	IncFunctionCalls() int
+}
+
+func NewLimitError(kind LimitKind, max int) *LimitError {
+	return &LimitError{kind, max}
+}
+
+func NewLimitedContext(delegate ContextAccessor, limits Limits) LimitedContextAccessor {
+	return &limitedContext{DelegatingContext: NewDelegatingContext(delegate), limits: limits}
+}
+
+func CheckCollectionSize(ctx ContextAccessor, size int) error {
+	if c := limitedContextOf(ctx); c != nil {
+		return checkLimit(CollectionSizeLimit, c.Limits().MaxCollectionSize, size)
+	}
+	return nil
+}
+
+func CheckRepeatDepth(ctx ContextAccessor, depth int) error {
+	if c := limitedContextOf(ctx); c != nil {
+		return checkLimit(RepeatDepthLimit, c.Limits().MaxRepeatDepth, depth)
+	}
+	return nil
+}
+
+func CheckRepeatIterations(ctx ContextAccessor, iterations int) error {
+	if c := limitedContextOf(ctx); c != nil {
+		return checkLimit(RepeatIterationsLimit, c.Limits().MaxRepeatIterations, iterations)
+	}
+	return nil
+}
+
+func CheckStringLength(ctx ContextAccessor, length int) error {
+	if c := limitedContextOf(ctx); c != nil {
+		return checkLimit(StringLengthLimit, c.Limits().MaxStringLength, length)
+	}
+	return nil
+}
+
+func CountFunctionCall(ctx ContextAccessor) error {
+	if c := limitedContextOf(ctx); c != nil {
+		return checkLimit(FunctionCallsLimit, c.Limits().MaxFunctionCalls, c.IncFunctionCalls())
+	}
+	return nil
+}
+
+func checkLimit(kind LimitKind, max int, value int) error {
+	if max > 0 && value > max {
+		return NewLimitError(kind, max)
+	}
+	return nil
+}
+
+func limitedContextOf(ctx ContextAccessor) LimitedContextAccessor {
+	if c, ok := FindContext(ctx, func(c ContextAccessor) bool {
+		_, ok := c.(LimitedContextAccessor)
+		return ok
+	}).(LimitedContextAccessor); ok {
+		return c
+	}
+	return nil
+}
+
+func (k LimitKind) String() string {
+	switch k {
+	case CollectionSizeLimit:
+		return "collection size"
+	case RepeatDepthLimit:
+		return "repeat depth"
+	case RepeatIterationsLimit:
+		return "repeat iterations"
+	case FunctionCallsLimit:
+		return "function calls"
+	case StringLengthLimit:
+		return "string length"
+	}
+	return fmt.Sprintf("limit %d", int(k))
+}
+
+func (e *LimitError) Kind() LimitKind {
+	return e.kind
+}
+
+func (e *LimitError) Max() int {
+	return e.max
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("evaluation limit exceeded: maximum %s of %d", e.kind, e.max)
+}
+
+func (c *limitedContext) Limits() Limits {
+	return c.limits
+}
+
+func (c *limitedContext) IncFunctionCalls() int {
+	c.functionCalls = c.functionCalls + 1
+	return c.functionCalls
+}
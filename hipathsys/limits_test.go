@@ -0,0 +1,128 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLimitedContextDelegate(t *testing.T) {
+	delegate := NewContextBuilder(newTestModel(t)).
+		Node(NewString("node")).
+		Tracer(&testTracer{}).
+		EnvVar("test", NewString("value")).
+		Build()
+	limits := Limits{MaxCollectionSize: 10}
+	ctx := NewLimitedContext(delegate, limits)
+
+	assert.Same(t, delegate, ctx.Delegate())
+	assert.Equal(t, limits, ctx.Limits())
+	assert.Same(t, delegate.ModelAdapter(), ctx.ModelAdapter())
+	assert.Same(t, delegate.Tracer(), ctx.Tracer())
+	assert.Same(t, delegate.ContextNode(), ctx.ContextNode())
+	v, found := ctx.EnvVar("test")
+	assert.True(t, found)
+	assert.Equal(t, NewString("value"), v)
+	assert.Equal(t, 0, ctx.NewCol().Count())
+	assert.Equal(t, 1, ctx.NewColWithItem(NewString("test")).Count())
+}
+
+func TestLimitsNotLimited(t *testing.T) {
+	ctx := newTestContext(t)
+	assert.NoError(t, CheckCollectionSize(ctx, 1000))
+	assert.NoError(t, CheckRepeatDepth(ctx, 1000))
+	assert.NoError(t, CheckRepeatIterations(ctx, 1000))
+	assert.NoError(t, CheckStringLength(ctx, 1000))
+	assert.NoError(t, CountFunctionCall(ctx))
+}
+
+func TestLimitsUnlimited(t *testing.T) {
+	ctx := NewLimitedContext(newTestContext(t), Limits{})
+	assert.NoError(t, CheckCollectionSize(ctx, 1000))
+	assert.NoError(t, CheckRepeatDepth(ctx, 1000))
+	assert.NoError(t, CheckRepeatIterations(ctx, 1000))
+	assert.NoError(t, CheckStringLength(ctx, 1000))
+	assert.NoError(t, CountFunctionCall(ctx))
+}
+
+func TestCheckCollectionSize(t *testing.T) {
+	ctx := NewLimitedContext(newTestContext(t), Limits{MaxCollectionSize: 5})
+	assert.NoError(t, CheckCollectionSize(ctx, 5))
+
+	err := CheckCollectionSize(ctx, 6)
+	var limitErr *LimitError
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.Equal(t, CollectionSizeLimit, limitErr.Kind())
+		assert.Equal(t, 5, limitErr.Max())
+	}
+	assert.EqualError(t, err, "evaluation limit exceeded: maximum collection size of 5")
+}
+
+func TestCheckRepeatDepth(t *testing.T) {
+	ctx := NewLimitedContext(newTestContext(t), Limits{MaxRepeatDepth: 2})
+	assert.NoError(t, CheckRepeatDepth(ctx, 2))
+	assert.EqualError(t, CheckRepeatDepth(ctx, 3),
+		"evaluation limit exceeded: maximum repeat depth of 2")
+}
+
+func TestCheckRepeatIterations(t *testing.T) {
+	ctx := NewLimitedContext(newTestContext(t), Limits{MaxRepeatIterations: 2})
+	assert.NoError(t, CheckRepeatIterations(ctx, 2))
+	assert.EqualError(t, CheckRepeatIterations(ctx, 3),
+		"evaluation limit exceeded: maximum repeat iterations of 2")
+}
+
+func TestCheckStringLength(t *testing.T) {
+	ctx := NewLimitedContext(newTestContext(t), Limits{MaxStringLength: 2})
+	assert.NoError(t, CheckStringLength(ctx, 2))
+	assert.EqualError(t, CheckStringLength(ctx, 3),
+		"evaluation limit exceeded: maximum string length of 2")
+}
+
+func TestCountFunctionCall(t *testing.T) {
+	ctx := NewLimitedContext(newTestContext(t), Limits{MaxFunctionCalls: 2})
+	assert.NoError(t, CountFunctionCall(ctx))
+	assert.NoError(t, CountFunctionCall(ctx))
+	assert.EqualError(t, CountFunctionCall(ctx),
+		"evaluation limit exceeded: maximum function calls of 2")
+}
+
+func TestCheckLimitsDelegated(t *testing.T) {
+	ctx := NewLimitedContext(newTestContext(t), Limits{MaxCollectionSize: 1})
+	delegating := NewCancelableContext(ctx, context.Background())
+	assert.Error(t, CheckCollectionSize(delegating, 2))
+}
+
+func TestLimitKindString(t *testing.T) {
+	assert.Equal(t, "collection size", CollectionSizeLimit.String())
+	assert.Equal(t, "limit 99", LimitKind(99).String())
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"fmt"
+	"github.com/shopspring/decimal"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+var LongTypeSpec = newAnyTypeSpec("Long")
+
+var longTypeInfo = NewSimpleTypeInfo(namespaceNameString, NewString("Long"), anyTypeNameString)
+
+type longType struct {
+	baseAnyType
+	value        int64
+	decimalValue DecimalAccessor
+}
+
+type LongAccessor interface {
+	NumberAccessor
+	Primitive() int64
+}
+
+func NewLong(value int64) LongAccessor {
+	return NewLongWithSource(value, nil)
+}
+
+func NewLongWithSource(value int64, source interface{}) LongAccessor {
+	return newLong(value, source)
+}
+
+func ParseLong(value string) (LongAccessor, error) {
+	return ParseLongWithSource(value, nil)
+}
+
+func ParseLongWithSource(value string, source interface{}) (LongAccessor, error) {
+	if i, err := strconv.ParseInt(value, 10, 64); err != nil {
+		return nil, fmt.Errorf("not a long: %s", value)
+	} else {
+		return newLong(i, source), nil
+	}
+}
+
+func newLong(value int64, source interface{}) LongAccessor {
+	return &longType{
+		baseAnyType: baseAnyType{
+			source: source,
+		},
+		value: value,
+	}
+}
+
+func (t *longType) DataType() DataTypes {
+	return LongDataType
+}
+
+func (t *longType) Int() int32 {
+	return int32(t.value)
+}
+
+func (t *longType) Int64() int64 {
+	return t.value
+}
+
+func (t *longType) Float32() float32 {
+	return float32(t.value)
+}
+
+func (t *longType) Float64() float64 {
+	return float64(t.value)
+}
+
+func (t *longType) BigFloat() *big.Float {
+	return t.Decimal().BigFloat()
+}
+
+func (t *longType) Decimal() decimal.Decimal {
+	return t.Value().Decimal()
+}
+
+func (t *longType) Primitive() int64 {
+	return t.value
+}
+
+func (t *longType) One() bool {
+	return t.value == 1
+}
+
+func (t *longType) Positive() bool {
+	return t.value > 0
+}
+
+func (t *longType) HasFraction() bool {
+	return false
+}
+
+func (t *longType) TypeSpec() TypeSpecAccessor {
+	return LongTypeSpec
+}
+
+func (t *longType) TypeInfo() TypeInfoAccessor {
+	return longTypeInfo
+}
+
+func (t *longType) Value() DecimalAccessor {
+	if t.decimalValue == nil {
+		t.decimalValue = NewDecimalInt64(t.value)
+	}
+	return t.decimalValue
+}
+
+func (t *longType) WithValue(node NumberAccessor) DecimalValueAccessor {
+	if node == nil || node.DataType() == LongDataType {
+		return node
+	}
+
+	return NewLong(node.Int64())
+}
+
+func (t *longType) ArithmeticOpSupported(ArithmeticOps) bool {
+	return true
+}
+
+func (t *longType) Negate() AnyAccessor {
+	return longResult(-t.value, t.value != math.MinInt64)
+}
+
+func (t *longType) Equal(node interface{}) bool {
+	if o, ok := integralNumber(node); ok {
+		return t.value == o.Int64()
+	}
+
+	return decimalValueEqual(t, node)
+}
+
+func (t *longType) Equivalent(node interface{}) bool {
+	if o, ok := integralNumber(node); ok {
+		return t.value == o.Int64()
+	}
+
+	return decimalValueEquivalent(t, node)
+}
+
+func (t *longType) Compare(comparator Comparator) (int, OperatorStatus) {
+	if o, ok := integralNumber(comparator); ok {
+		l, r := t.value, o.Int64()
+		if l == r {
+			return 0, Evaluated
+		}
+		if l < r {
+			return -1, Evaluated
+		}
+		return 1, Evaluated
+	}
+
+	return decimalValueCompare(t, comparator)
+}
+
+func (t *longType) String() string {
+	return strconv.FormatInt(t.value, 10)
+}
+
+func (t *longType) Ceiling() NumberAccessor {
+	return t
+}
+
+func (t *longType) Exp() NumberAccessor {
+	return NewDecimalFloat64(math.Exp(t.Float64()))
+}
+
+func (t *longType) Floor() NumberAccessor {
+	return t
+}
+
+func (t *longType) Ln() (NumberAccessor, error) {
+	if t.value <= 0 {
+		return nil, fmt.Errorf("logarithmus cannot be applied to non-positive values %d", t.value)
+	}
+	return NewDecimalFloat64(math.Log(t.Float64())), nil
+}
+
+func (t *longType) Log(base NumberAccessor) (NumberAccessor, error) {
+	if t.value <= 0 {
+		return nil, fmt.Errorf("logarithmus cannot be applied to non-positive values %d", t.value)
+	}
+	if !base.Positive() {
+		return nil, fmt.Errorf("logarithmus cannot be applied to non-positive base %f", base.Float64())
+	}
+	return NewDecimalFloat64(math.Log(t.Float64()) / math.Log(base.Float64())), nil
+}
+
+func (t *longType) Power(exponent NumberAccessor) (NumberAccessor, bool) {
+	if exponent.One() {
+		return t, true
+	}
+	if _, ok := integralNumber(exponent); ok {
+		return NewLong(int64(math.Pow(t.Float64(), exponent.Float64()))), true
+	}
+	return NewDecimalInt64(t.value).Power(exponent)
+}
+
+func (t *longType) Round(precision int32) (NumberAccessor, error) {
+	if precision < 0 {
+		return nil, fmt.Errorf("precision must not be negative %d", precision)
+	}
+	return t, nil
+}
+
+func (t *longType) Sqrt() (NumberAccessor, bool) {
+	r := math.Sqrt(t.Float64())
+	if math.IsNaN(r) {
+		return nil, false
+	}
+	return NewDecimalFloat64(r), true
+}
+
+func (t *longType) Truncate(int32) NumberAccessor {
+	return t
+}
+
+func (t *longType) Calc(operand DecimalValueAccessor, op ArithmeticOps) (DecimalValueAccessor, error) {
+	if operand == nil {
+		return nil, nil
+	}
+
+	if !t.ArithmeticOpSupported(op) || !operand.ArithmeticOpSupported(op) {
+		return nil, fmt.Errorf("arithmetic operator not supported: %c", op)
+	}
+
+	if ov, ok := integralNumber(operand); ok {
+		pov := ov.Int64()
+		switch op {
+		case AdditionOp:
+			return longResult(addLong(t.value, pov)), nil
+		case SubtractionOp:
+			return longResult(subtractLong(t.value, pov)), nil
+		case MultiplicationOp:
+			return longResult(multiplyLong(t.value, pov)), nil
+		case DivisionOp:
+			return decimalCalc(t, ov.Value(), op), nil
+		case DivOp:
+			if pov == 0 || (t.value == math.MinInt64 && pov == -1) {
+				return nil, nil
+			}
+			return NewLong(t.value / pov), nil
+		case ModOp:
+			if pov == 0 {
+				return nil, nil
+			}
+			return NewLong(t.value % pov), nil
+		default:
+			panic(fmt.Sprintf("Unhandled operator: %d", op))
+		}
+	}
+
+	if q, ok := operand.(QuantityAccessor); ok {
+		return calcNumberQuantity(t, q, op)
+	}
+	return operand.WithValue(decimalCalc(t, operand.Value(), op)), nil
+}
+
+func addLong(v1 int64, v2 int64) (int64, bool) {
+	r := v1 + v2
+	return r, (r > v1) == (v2 > 0)
+}
+
+func subtractLong(v1 int64, v2 int64) (int64, bool) {
+	r := v1 - v2
+	return r, (r < v1) == (v2 > 0)
+}
+
+func multiplyLong(v1 int64, v2 int64) (int64, bool) {
+	if v1 == 0 || v2 == 0 {
+		return 0, true
+	}
+	r := v1 * v2
+	return r, r/v2 == v1 && !(v1 == math.MinInt64 && v2 == -1)
+}
+
+func longResult(value int64, valid bool) DecimalValueAccessor {
+	if !valid {
+		return nil
+	}
+	return NewLong(value)
+}
+
+func (t *longType) Abs() DecimalValueAccessor {
+	if t.value < 0 {
+		return longResult(-t.value, t.value != math.MinInt64)
+	}
+	return t
+}
+
+func integralNumber(node interface{}) (NumberAccessor, bool) {
+	switch n := node.(type) {
+	case IntegerAccessor:
+		return n, true
+	case LongAccessor:
+		return n, true
+	}
+	return nil, false
+}
+
+func LongValue(node interface{}) interface{} {
+	if v, ok := node.(LongAccessor); !ok {
+		return nil
+	} else {
+		return v.Int64()
+	}
+}
@@ -0,0 +1,344 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestLongSource(t *testing.T) {
+	o := NewLongWithSource(10, "abc")
+	assert.Equal(t, "abc", o.Source())
+}
+
+func TestLongDataType(t *testing.T) {
+	o := NewLong(4711)
+	assert.Equal(t, LongDataType, o.DataType())
+}
+
+func TestLongTypeSpec(t *testing.T) {
+	o := NewLong(0)
+	i := o.TypeSpec()
+	if assert.NotNil(t, i, "type info expected") {
+		assert.Equal(t, "System.Long", i.String())
+		if assert.NotNil(t, i.FQBaseName(), "base name expected") {
+			assert.Equal(t, "System.Any", i.FQBaseName().String())
+		}
+	}
+}
+
+func TestLongTypeInfo(t *testing.T) {
+	o := NewLong(0)
+	i := o.TypeInfo()
+	if assert.Implements(t, (*SimpleTypeInfoAccessor)(nil), i) {
+		a := i.(SimpleTypeInfoAccessor)
+		assert.Equal(t, NewString("System"), a.Namespace())
+		assert.Equal(t, NewString("Long"), a.Name())
+		assert.Equal(t, NewString("System.Any"), a.BaseType())
+	}
+}
+
+func TestLongNegate(t *testing.T) {
+	o := NewLong(8_000_000_000)
+	n := o.Negate()
+	assert.NotSame(t, o, n)
+	assert.Equal(t, int64(8_000_000_000), o.Int64())
+	if assert.Implements(t, (*LongAccessor)(nil), n) {
+		assert.Equal(t, int64(-8_000_000_000), n.(LongAccessor).Int64())
+	}
+}
+
+func TestLongNegateOverflow(t *testing.T) {
+	assert.Nil(t, NewLong(math.MinInt64).Negate())
+	assert.Equal(t, NewLong(math.MinInt64+1), NewLong(math.MaxInt64).Negate())
+}
+
+func TestLongValues(t *testing.T) {
+	o := NewLong(8_000_000_000)
+	assert.Equal(t, int64(8_000_000_000), o.Primitive())
+	assert.Equal(t, int32(-589_934_592), o.Int())
+	assert.Equal(t, float32(8_000_000_000), o.Float32())
+	assert.Equal(t, float64(8_000_000_000), o.Float64())
+	assert.Equal(t, "8000000000", o.Decimal().String())
+	f, _ := o.BigFloat().Float64()
+	assert.Equal(t, 8_000_000_000.0, f)
+	assert.Equal(t, "8000000000", o.String())
+	assert.Same(t, o.Value(), o.Value())
+	assert.Equal(t, int64(8_000_000_000), LongValue(o))
+	assert.Nil(t, LongValue(NewInteger(1)))
+}
+
+func TestParseLong(t *testing.T) {
+	o, err := ParseLong("-8000000000")
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, o, "value expected") {
+		assert.Equal(t, int64(-8_000_000_000), o.Int64())
+	}
+}
+
+func TestParseLongInvalid(t *testing.T) {
+	o, err := ParseLong("8273.3")
+	assert.Nil(t, o, "value unexpected")
+	assert.EqualError(t, err, "not a long: 8273.3")
+}
+
+func TestParseIntegerOverflow(t *testing.T) {
+	o, err := ParseInteger("8000000000")
+	assert.Nil(t, o, "value unexpected")
+	assert.EqualError(t, err, "not an integer: 8000000000")
+}
+
+func TestLongWithValue(t *testing.T) {
+	l := NewLong(232)
+	assert.Nil(t, NewLong(1).WithValue(nil))
+	assert.Same(t, l, NewLong(82763).WithValue(l))
+	r := NewLong(123).WithValue(NewDecimalFloat64(232.72))
+	if assert.Implements(t, (*LongAccessor)(nil), r) {
+		assert.Equal(t, int64(232), r.(LongAccessor).Int64())
+	}
+}
+
+func TestLongEqual(t *testing.T) {
+	assert.True(t, NewLong(10).Equal(NewLong(10)))
+	assert.True(t, NewLong(10).Equal(NewInteger(10)))
+	assert.True(t, NewInteger(10).Equal(NewLong(10)))
+	assert.True(t, NewLong(10).Equal(NewDecimalInt(10)))
+	assert.True(t, NewDecimalInt(10).Equal(NewLong(10)))
+	assert.False(t, NewLong(10).Equal(NewLong(11)))
+	assert.False(t, NewLong(10).Equal(NewString("10")))
+	assert.False(t, NewLong(10).Equal(nil))
+}
+
+func TestLongEquivalent(t *testing.T) {
+	assert.True(t, NewLong(10).Equivalent(NewInteger(10)))
+	assert.True(t, NewInteger(10).Equivalent(NewLong(10)))
+	assert.True(t, NewLong(10).Equivalent(NewDecimalFloat64(10.4)))
+	assert.False(t, NewLong(10).Equivalent(NewLong(11)))
+}
+
+func TestLongCompare(t *testing.T) {
+	res, status := NewLong(8_000_000_000).Compare(NewInteger(10))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, 1, res)
+	res, status = NewLong(-8_000_000_000).Compare(NewLong(10))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, -1, res)
+	res, status = NewLong(10).Compare(NewLong(10))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, 0, res)
+	res, status = NewLong(10).Compare(NewDecimalFloat64(10.5))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, -1, res)
+	res, status = NewInteger(10).Compare(NewLong(8_000_000_000))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, -1, res)
+	_, status = NewLong(10).Compare(NewString("10"))
+	assert.Equal(t, Inconvertible, status)
+}
+
+func TestLongCalc(t *testing.T) {
+	r, err := NewLong(8_000_000_000).Calc(NewInteger(2), AdditionOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(8_000_000_002), r)
+	r, err = NewLong(8_000_000_000).Calc(NewLong(2), SubtractionOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(7_999_999_998), r)
+	r, err = NewLong(8_000_000_000).Calc(NewInteger(2), MultiplicationOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(16_000_000_000), r)
+	r, err = NewLong(5).Calc(NewInteger(2), DivisionOp)
+	assert.NoError(t, err)
+	if assert.Implements(t, (*DecimalAccessor)(nil), r) {
+		assert.Equal(t, 2.5, r.(DecimalAccessor).Float64())
+	}
+	r, err = NewLong(5).Calc(NewInteger(2), DivOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(2), r)
+	r, err = NewLong(5).Calc(NewInteger(2), ModOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(1), r)
+}
+
+func TestLongCalcByZero(t *testing.T) {
+	for _, op := range []ArithmeticOps{DivisionOp, DivOp, ModOp} {
+		r, err := NewLong(5).Calc(NewLong(0), op)
+		assert.NoError(t, err)
+		assert.Nil(t, r)
+	}
+}
+
+func TestLongCalcOverflow(t *testing.T) {
+	r, err := NewLong(math.MaxInt64).Calc(NewLong(1), AdditionOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "addition overflow")
+	r, err = NewLong(math.MinInt64).Calc(NewInteger(1), SubtractionOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "subtraction overflow")
+	r, err = NewLong(math.MaxInt64).Calc(NewInteger(2), MultiplicationOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "multiplication overflow")
+	r, err = NewLong(math.MinInt64).Calc(NewLong(-1), MultiplicationOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "multiplication overflow")
+	r, err = NewLong(math.MinInt64).Calc(NewLong(-1), DivOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r, "div overflow")
+}
+
+func TestLongCalcNoOverflow(t *testing.T) {
+	r, err := NewLong(math.MaxInt64).Calc(NewLong(-1), AdditionOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(math.MaxInt64-1), r)
+	r, err = NewLong(math.MinInt64).Calc(NewLong(-1), SubtractionOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(math.MinInt64+1), r)
+	r, err = NewLong(math.MinInt64).Calc(NewLong(1), MultiplicationOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(math.MinInt64), r)
+}
+
+func TestLongCalcNil(t *testing.T) {
+	r, err := NewLong(5).Calc(nil, AdditionOp)
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestLongCalcUnknownOp(t *testing.T) {
+	assert.Panics(t, func() { _, _ = NewLong(5).Calc(NewLong(2), 0) })
+}
+
+func TestLongCalcNotSupportedOp(t *testing.T) {
+	q := NewQuantity(NewDecimalFloat64(47.2), NewString("m"))
+	r, err := NewLong(2).Calc(q, AdditionOp)
+	assert.NoError(t, err)
+	assert.True(t, NewQuantity(NewDecimalFloat64(49.2), NewString("m")).Equal(r))
+}
+
+func TestLongCalcDivisionQuantity(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(1), NewString("mg"))
+	r, err := NewLong(2).Calc(q, DivisionOp)
+	assert.NoError(t, err)
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 2.0, r.Value().Float64())
+		assert.Equal(t, "/mg", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestLongCalcDecimal(t *testing.T) {
+	r, err := NewLong(8_000_000_000).Calc(NewDecimalFloat64(0.5), AdditionOp)
+	assert.NoError(t, err)
+	if assert.Implements(t, (*DecimalAccessor)(nil), r) {
+		assert.Equal(t, "8000000000.5", r.(DecimalAccessor).String())
+	}
+}
+
+func TestIntegerCalcLong(t *testing.T) {
+	r, err := NewInteger(2).Calc(NewLong(8_000_000_000), AdditionOp)
+	assert.NoError(t, err)
+	assert.Equal(t, NewLong(8_000_000_002), r)
+}
+
+func TestDecimalCalcLong(t *testing.T) {
+	r, err := NewDecimalFloat64(0.5).Calc(NewLong(8_000_000_000), AdditionOp)
+	assert.NoError(t, err)
+	if assert.Implements(t, (*DecimalAccessor)(nil), r) {
+		assert.Equal(t, "8000000000.5", r.(DecimalAccessor).String())
+	}
+}
+
+func TestLongAbs(t *testing.T) {
+	assert.Equal(t, NewLong(8_000_000_000), NewLong(-8_000_000_000).Abs())
+	l := NewLong(10)
+	assert.Same(t, l, l.Abs())
+}
+
+func TestLongAbsOverflow(t *testing.T) {
+	assert.Nil(t, NewLong(math.MinInt64).Abs())
+	assert.Equal(t, NewLong(math.MaxInt64), NewLong(math.MinInt64+1).Abs())
+}
+
+func TestLongProperties(t *testing.T) {
+	assert.True(t, NewLong(1).One())
+	assert.False(t, NewLong(2).One())
+	assert.True(t, NewLong(2).Positive())
+	assert.False(t, NewLong(0).Positive())
+	assert.False(t, NewLong(2).HasFraction())
+	assert.True(t, NewLong(2).ArithmeticOpSupported(ModOp))
+}
+
+func TestLongMath(t *testing.T) {
+	l := NewLong(8_000_000_000)
+	assert.Same(t, l, l.Ceiling())
+	assert.Same(t, l, l.Floor())
+	assert.Same(t, l, l.Truncate(2))
+	r, err := l.Round(2)
+	assert.NoError(t, err)
+	assert.Same(t, l, r)
+	_, err = l.Round(-1)
+	assert.Error(t, err)
+	assert.Equal(t, 7.389, NewLong(2).Exp().Value().Decimal().Round(3).InexactFloat64())
+	s, ok := NewLong(16).Sqrt()
+	assert.True(t, ok)
+	assert.Equal(t, NewDecimalFloat64(4), s)
+	_, ok = NewLong(-16).Sqrt()
+	assert.False(t, ok)
+}
+
+func TestLongLn(t *testing.T) {
+	r, err := NewLong(1).Ln()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, r.Float64())
+	_, err = NewLong(0).Ln()
+	assert.Error(t, err)
+}
+
+func TestLongLog(t *testing.T) {
+	r, err := NewLong(100).Log(NewInteger(10))
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, r.Float64())
+	_, err = NewLong(0).Log(NewInteger(10))
+	assert.Error(t, err)
+	_, err = NewLong(100).Log(NewInteger(0))
+	assert.Error(t, err)
+}
+
+func TestLongPower(t *testing.T) {
+	l := NewLong(3)
+	r, ok := l.Power(NewInteger(1))
+	assert.True(t, ok)
+	assert.Same(t, l, r)
+	r, ok = l.Power(NewLong(3))
+	assert.True(t, ok)
+	assert.Equal(t, NewLong(27), r)
+	r, ok = NewLong(4).Power(NewDecimalFloat64(0.5))
+	assert.True(t, ok)
+	assert.Equal(t, 2.0, r.Float64())
+}
@@ -30,6 +30,7 @@ package hipathsys
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -38,7 +39,7 @@ var UCUMSystemURI = NewString("http://unitsofmeasure.org")
 var QuantityTypeSpec = newAnyTypeSpec("Quantity")
 
 var quantityTypeInfo = NewClassInfo(namespaceNameString, NewString("Quantity"), NewString("System.Any"),
-	NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("value"), NewString(DecimalTypeSpec.String()), nil),
 		NewClassInfoElement(NewString("unit"), NewString(StringTypeSpec.String()), nil),
 	}))
@@ -135,6 +136,21 @@ func quantityValueEqual(t QuantityAccessor, node interface{}, equivalent bool) b
 			return Equal(t.Value(), q.Value())
 		}
 
+		uu1, uu2 := ucumQuantityUnits(t, q)
+		if uu1 == nil {
+			uu1, uu2 = durationUCUMUnits(t, q)
+		}
+		if uu1 != nil {
+			v := ConvertUCUMValue(q.Value(), uu2, uu1)
+			if v == nil {
+				return false
+			}
+			if equivalent {
+				return Equivalent(t.Value(), v)
+			}
+			return Equal(t.Value(), v)
+		}
+
 		u1, exp1 := QuantityUnitWithNameString(t.Unit())
 		u2, exp2 := QuantityUnitWithNameString(q.Unit())
 		if exp1 != exp2 {
@@ -158,6 +174,17 @@ func quantityValueEqual(t QuantityAccessor, node interface{}, equivalent bool) b
 func (t *quantityType) Compare(comparator Comparator) (int, OperatorStatus) {
 	if q, ok := comparator.(QuantityAccessor); ok {
 		if !Equal(t.Unit(), q.Unit()) {
+			uu1, uu2 := ucumQuantityUnits(t, q)
+			if uu1 == nil {
+				uu1, uu2 = durationUCUMUnits(t, q)
+			}
+			if uu1 != nil {
+				if v := ConvertUCUMValue(q.Value(), uu2, uu1); v != nil {
+					return decimalValueCompare(t.value, v)
+				}
+				return -1, Empty
+			}
+
 			u1, exp1 := QuantityUnitWithNameString(t.Unit())
 			u2, exp2 := QuantityUnitWithNameString(q.Unit())
 			if exp1 == exp2 {
@@ -180,13 +207,40 @@ func (t *quantityType) ToUnit(unit StringAccessor) QuantityAccessor {
 		return nil
 	}
 
+	if Equal(t.Unit(), unit) {
+		return t
+	}
+
+	if uu1, uu2 := ucumQuantityUnit(t.Unit()), ucumQuantityUnit(unit); uu1 != nil && uu2 != nil {
+		if v := ConvertUCUMValue(t.Value(), uu1, uu2); v != nil {
+			return NewQuantity(normalizedDecimal(v), unit)
+		}
+		return nil
+	}
+
+	if v := t.toQuantityUnitValue(u2, exp2); v != nil {
+		return NewQuantity(normalizedDecimal(v), unit)
+	}
+
+	uu1, uu2 := durationUCUMUnit(t.Unit()), durationUCUMUnit(unit)
+	if uu1 == nil || uu2 == nil {
+		return nil
+	}
+	v := ConvertUCUMValue(t.Value(), uu1, uu2)
+	if v == nil {
+		return nil
+	}
+	return NewQuantity(normalizedDecimal(v), unit)
+}
+
+func (t *quantityType) toQuantityUnitValue(u2 QuantityUnitAccessor, exp2 int) DecimalAccessor {
 	u1, exp1 := QuantityUnitWithNameString(t.Unit())
 	if u1 == nil || exp1 != exp2 {
 		return nil
 	}
 
 	if u1.Equal(u2) {
-		return t
+		return t.Value()
 	}
 
 	u := u1.CommonBase(u2, true)
@@ -197,9 +251,82 @@ func (t *quantityType) ToUnit(unit StringAccessor) QuantityAccessor {
 	f1, f2 := u1.Factor(u, exp1), u2.Factor(u, exp2)
 	v, _ := t.Value().Calc(f1, MultiplicationOp)
 	v, _ = v.Value().Calc(f2, DivisionOp)
+	return v.Value()
+}
 
-	val := v.Value()
-	return NewQuantity(val, u2.NameWithExp(val, exp2))
+func ucumQuantityUnits(l QuantityAccessor, r QuantityAccessor) (UCUMUnitAccessor, UCUMUnitAccessor) {
+	u1 := ucumQuantityUnit(l.Unit())
+	if u1 == nil {
+		return nil, nil
+	}
+	u2 := ucumQuantityUnit(r.Unit())
+	if u2 == nil {
+		return nil, nil
+	}
+	return u1, u2
+}
+
+func durationUCUMUnits(l QuantityAccessor, r QuantityAccessor) (UCUMUnitAccessor, UCUMUnitAccessor) {
+	if (ucumQuantityUnit(l.Unit()) == nil) == (ucumQuantityUnit(r.Unit()) == nil) {
+		return nil, nil
+	}
+	u1 := durationUCUMUnit(l.Unit())
+	if u1 == nil {
+		return nil, nil
+	}
+	u2 := durationUCUMUnit(r.Unit())
+	if u2 == nil {
+		return nil, nil
+	}
+	return u1, u2
+}
+
+func QuantityUnitsComparable(q1 QuantityAccessor, q2 QuantityAccessor) bool {
+	if (ucumQuantityUnit(q1.Unit()) == nil) == (ucumQuantityUnit(q2.Unit()) == nil) {
+		return true
+	}
+	if QuantityUnitByNameString(q1.Unit()) == nil || QuantityUnitByNameString(q2.Unit()) == nil {
+		return true
+	}
+	return durationUCUMUnit(q1.Unit()) != nil && durationUCUMUnit(q2.Unit()) != nil
+}
+
+func ucumQuantityUnit(unit StringAccessor) UCUMUnitAccessor {
+	if unit == nil {
+		return nil
+	}
+
+	name := unit.String()
+	if u := QuantityUnitByName(name); u != nil && (u.UCUM() == nil || u.UCUM().String() != name) {
+		return nil
+	}
+
+	u, err := ParseUCUMUnit(name)
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+func durationUCUMUnit(unit StringAccessor) UCUMUnitAccessor {
+	if u := ucumQuantityUnit(unit); u != nil {
+		return u
+	}
+
+	u, exp := QuantityUnitWithNameString(unit)
+	code, ok := calendarDurationUCUMCodes[u]
+	if !ok {
+		return nil
+	}
+	if exp != 1 {
+		code += strconv.Itoa(exp)
+	}
+
+	uu, err := ParseUCUMUnit(code)
+	if err != nil {
+		return nil
+	}
+	return uu
 }
 
 func (t *quantityType) String() string {
@@ -224,6 +351,22 @@ func (t *quantityType) Calc(operand DecimalValueAccessor, op ArithmeticOps) (Dec
 		return nil, fmt.Errorf("arithmetic operator not supported: %c", op)
 	}
 
+	if q, ok := operand.(QuantityAccessor); ok {
+		if u1, u2 := ucumQuantityUnits(t, q); u1 != nil {
+			if op == MultiplicationOp || op == DivisionOp {
+				value, unit, err := calcUCUMQuantity(t.Value(), u1, q.Value(), u2, op)
+				if value == nil || err != nil {
+					return nil, err
+				}
+				return NewQuantity(value, NewString(unit)), nil
+			}
+			if !u1.Special() && !u2.Special() && u1.Commensurable(u2) {
+				value, _ := t.Value().Calc(ConvertUCUMValue(q.Value(), u2, u1), op)
+				return NewQuantity(value.Value(), t.Unit()), nil
+			}
+		}
+	}
+
 	var valLeft, varRight DecimalAccessor
 	var unit QuantityUnitAccessor
 	var exp int
@@ -242,6 +385,29 @@ func (t *quantityType) Calc(operand DecimalValueAccessor, op ArithmeticOps) (Dec
 	return NewQuantity(value.Value(), unit.NameWithExp(value.Value(), exp)), nil
 }
 
+func calcNumberQuantity(n NumberAccessor, q QuantityAccessor, op ArithmeticOps) (DecimalValueAccessor, error) {
+	var u UCUMUnitAccessor
+	switch op {
+	case MultiplicationOp:
+		u = ucumQuantityUnit(q.Unit())
+	case DivisionOp:
+		if q.Unit() != nil {
+			if u = durationUCUMUnit(q.Unit()); u == nil {
+				return nil, fmt.Errorf("quantity unit cannot be inverted: %s", q.Unit())
+			}
+		}
+	}
+	if u == nil {
+		return q.WithValue(decimalCalc(n, q.Value(), op)), nil
+	}
+
+	value, unit, err := calcUCUMQuantity(NewDecimal(n.Decimal()), ucumDimensionlessUnit, q.Value(), u, op)
+	if value == nil || err != nil {
+		return nil, err
+	}
+	return NewQuantity(value, NewString(unit)), nil
+}
+
 func mergeQuantityUnits(l QuantityAccessor, r QuantityAccessor, op ArithmeticOps) (DecimalAccessor, DecimalAccessor, QuantityUnitAccessor, int, error) {
 	leftVal, rightVal := l.Value(), r.Value()
 	leftUnit, leftExp := QuantityUnitWithNameString(l.Unit())
@@ -258,7 +424,7 @@ func mergeQuantityUnits(l QuantityAccessor, r QuantityAccessor, op ArithmeticOps
 			leftVal, leftUnit, leftExp, rightVal, rightUnit, rightExp, true)
 		if unit == nil {
 			return nil, nil, nil, 1, fmt.Errorf("units are not equal: %s != %s",
-				leftUnit, rightUnit)
+				quantityUnitString(l.Unit()), quantityUnitString(r.Unit()))
 		}
 	}
 
@@ -282,6 +448,13 @@ func mergeQuantityUnits(l QuantityAccessor, r QuantityAccessor, op ArithmeticOps
 	return leftVal, rightVal, unit, exp, nil
 }
 
+func quantityUnitString(unit StringAccessor) string {
+	if unit == nil {
+		return ""
+	}
+	return unit.String()
+}
+
 func (t *quantityType) Abs() DecimalValueAccessor {
 	return NewQuantity(t.Value().Abs().(DecimalAccessor), t.Unit())
 }
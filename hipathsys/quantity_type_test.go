@@ -171,10 +171,66 @@ func TestQuantityEqualUnitFactorNot(t *testing.T) {
 func TestQuantityEqualUnitFactorEquivalent(t *testing.T) {
 	q1 := NewQuantity(NewDecimalFloat64(7), NewString("d"))
 	q2 := NewQuantity(NewDecimalFloat64(7), NewString("days"))
+	assert.Equal(t, true, q1.Equal(q2))
+	assert.Equal(t, true, q1.Equivalent(q2))
+}
+
+func TestQuantityEqualUCUMUnit(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(5), NewString("mg"))
+	q2 := NewQuantity(NewDecimalFloat64(0.005), NewString("g"))
+	assert.Equal(t, true, q1.Equal(q2))
+	assert.Equal(t, true, q1.Equivalent(q2))
+}
+
+func TestQuantityEqualUCUMUnitDiffers(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(5), NewString("mg"))
+	q2 := NewQuantity(NewDecimalFloat64(0.006), NewString("g"))
+	assert.Equal(t, false, q1.Equal(q2))
+	assert.Equal(t, false, q1.Equivalent(q2))
+}
+
+func TestQuantityEqualUCUMUnitIncommensurable(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(5), NewString("mg"))
+	q2 := NewQuantity(NewDecimalInt(5), NewString("mL"))
+	assert.Equal(t, false, q1.Equal(q2))
+	assert.Equal(t, false, q1.Equivalent(q2))
+}
+
+func TestQuantityEquivalentUCUMUnit(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(12), NewString("[in_i]"))
+	q2 := NewQuantity(NewDecimalFloat64(30.5), NewString("cm"))
 	assert.Equal(t, false, q1.Equal(q2))
 	assert.Equal(t, true, q1.Equivalent(q2))
 }
 
+func TestQuantityEqualCalendarUCUMUnit(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(1), NewString("day"))
+	q2 := NewQuantity(NewDecimalInt(24), NewString("h"))
+	assert.Equal(t, true, q1.Equal(q2))
+	assert.Equal(t, true, q2.Equal(q1))
+	assert.Equal(t, true, q1.Equivalent(q2))
+}
+
+func TestQuantityEqualCalendarUCUMUnitSecond(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(1), NewString("second"))
+	q2 := NewQuantity(NewDecimalInt(1), NewString("s"))
+	assert.Equal(t, true, q1.Equal(q2))
+	assert.Equal(t, true, q1.Equivalent(q2))
+}
+
+func TestQuantityUnitsComparable(t *testing.T) {
+	assert.True(t, QuantityUnitsComparable(NewQuantity(NewDecimalInt(1), NewString("day")),
+		NewQuantity(NewDecimalInt(1), NewString("h"))))
+	assert.True(t, QuantityUnitsComparable(NewQuantity(NewDecimalInt(1), NewString("year")),
+		NewQuantity(NewDecimalInt(1), NewString("months"))))
+	assert.True(t, QuantityUnitsComparable(NewQuantity(NewDecimalInt(1), NewString("year")),
+		NewQuantity(NewDecimalInt(1), NewString("mg"))))
+	assert.False(t, QuantityUnitsComparable(NewQuantity(NewDecimalInt(1), NewString("year")),
+		NewQuantity(NewDecimalInt(1), NewString("a"))))
+	assert.False(t, QuantityUnitsComparable(NewQuantity(NewDecimalInt(1), NewString("mo")),
+		NewQuantity(NewDecimalInt(1), NewString("month"))))
+}
+
 func TestQuantityEqualInteger(t *testing.T) {
 	q1 := NewQuantity(NewDecimalFloat64(47), NewString("g"))
 	assert.Equal(t, true, q1.Equal(NewInteger(47)))
@@ -349,6 +405,69 @@ func TestQuantityCalcSubtraction(t *testing.T) {
 	assert.True(t, e.Equal(r))
 }
 
+func TestQuantityCalcAdditionUCUM(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(1), NewString("mg"))
+	q2 := NewQuantity(NewDecimalInt(2), NewString("g"))
+	r, err := q1.Calc(q2, AdditionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 2001.0, r.Value().Float64())
+		assert.Equal(t, "mg", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestQuantityCalcSubtractionUCUM(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(1), NewString("L"))
+	q2 := NewQuantity(NewDecimalInt(250), NewString("mL"))
+	r, err := q1.Calc(q2, SubtractionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 0.75, r.Value().Float64())
+		assert.Equal(t, "L", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestQuantityCalcAdditionUCUMTimeUnits(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(2), NewString("h"))
+	q2 := NewQuantity(NewDecimalInt(30), NewString("min"))
+	r, err := q1.Calc(q2, AdditionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 2.5, r.Value().Float64())
+		assert.Equal(t, "h", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestQuantityCalcAdditionUCUMWeekDay(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(1), NewString("d"))
+	q2 := NewQuantity(NewDecimalInt(1), NewString("wk"))
+	r, err := q1.Calc(q2, AdditionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 8.0, r.Value().Float64())
+		assert.Equal(t, "d", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestQuantityCalcSubtractionUCUMTimeUnits(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(1), NewString("d"))
+	q2 := NewQuantity(NewDecimalInt(6), NewString("h"))
+	r, err := q1.Calc(q2, SubtractionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, 0.75, r.Value().Float64())
+		assert.Equal(t, "d", r.(QuantityAccessor).Unit().String())
+	}
+}
+
+func TestQuantityCalcAdditionUCUMSpecialUnit(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(10), NewString("Cel"))
+	q2 := NewQuantity(NewDecimalInt(2), NewString("K"))
+	r, err := q1.Calc(q2, AdditionOp)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r, "no res expected")
+}
+
 func TestQuantityCalcMultiplication(t *testing.T) {
 	q1 := NewQuantity(NewDecimalFloat64(47.2), NewString("m"))
 	q2 := NewQuantity(NewDecimalFloat64(21.7), NewString("m"))
@@ -367,6 +486,42 @@ func TestQuantityCalcDivision(t *testing.T) {
 	assert.True(t, e.Equal(r))
 }
 
+func TestQuantityCalcDivisionCompoundUnit(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(10), NewString("mg"))
+	q2 := NewQuantity(NewDecimalInt(2), NewString("mL"))
+	r, err := q1.Calc(q2, DivisionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, "5 'mg/mL'", r.(QuantityAccessor).String())
+	}
+}
+
+func TestQuantityCalcDivisionCancelUnit(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(10), NewString("km"))
+	q2 := NewQuantity(NewDecimalInt(2), NewString("m"))
+	r, err := q1.Calc(q2, DivisionOp)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*QuantityAccessor)(nil), r) {
+		assert.Equal(t, "5000 '1'", r.(QuantityAccessor).String())
+	}
+}
+
+func TestQuantityCalcDivisionZero(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(10), NewString("mg"))
+	q2 := NewQuantity(NewDecimalInt(0), NewString("mL"))
+	r, err := q1.Calc(q2, DivisionOp)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, r, "empty res expected")
+}
+
+func TestQuantityCalcMultiplicationSpecialUnit(t *testing.T) {
+	q1 := NewQuantity(NewDecimalInt(10), NewString("Cel"))
+	q2 := NewQuantity(NewDecimalInt(2), NewString("m"))
+	r, err := q1.Calc(q2, MultiplicationOp)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r, "no res expected")
+}
+
 func TestQuantityCalcNotSupportedOp(t *testing.T) {
 	q1 := NewQuantity(NewDecimalFloat64(48.75), NewString("m3"))
 	q2 := NewQuantity(NewDecimalFloat64(2.5), NewString("m"))
@@ -379,7 +534,9 @@ func TestQuantityCalcCodesDiffers(t *testing.T) {
 	q1 := NewQuantity(NewDecimalFloat64(48.75), NewString("m"))
 	q2 := NewQuantity(NewDecimalFloat64(2.5), NewString("g"))
 	r, err := q1.Calc(q2, AdditionOp)
-	assert.Error(t, err, "error expected")
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "units are not equal: m != g", err.Error())
+	}
 	assert.Nil(t, r, "no res expected")
 }
 
@@ -404,6 +561,19 @@ func TestQuantityCompareEqualUnitFactor(t *testing.T) {
 	assert.Equal(t, 0, res)
 }
 
+func TestQuantityCompareCalendarUCUMUnit(t *testing.T) {
+	res, status := NewQuantity(NewDecimalInt(1), NewString("day")).
+		Compare(NewQuantity(NewDecimalInt(23), NewString("h")))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, 1, res)
+}
+
+func TestQuantityCompareCalendarYearUCUMUnit(t *testing.T) {
+	_, status := NewQuantity(NewDecimalInt(1), NewString("year")).
+		Compare(NewQuantity(NewDecimalInt(1), NewString("a")))
+	assert.Equal(t, Empty, status)
+}
+
 func TestQuantityCompareEqualUnitNil(t *testing.T) {
 	res, status := NewQuantity(NewDecimalFloat64(10.21), nil).
 		Compare(NewQuantity(NewDecimalFloat64(10.21), nil))
@@ -420,11 +590,25 @@ func TestQuantityCompareEqualNotOneUnitNil(t *testing.T) {
 
 func TestQuantityCompareEqualNotUnit(t *testing.T) {
 	res, status := NewQuantity(NewDecimalFloat64(10.21), NewString("cm")).
-		Compare(NewQuantity(NewDecimalFloat64(10.21), NewString("m")))
+		Compare(NewQuantity(NewDecimalFloat64(10.21), NewString("g")))
 	assert.Equal(t, Empty, status)
 	assert.Equal(t, -1, res)
 }
 
+func TestQuantityCompareUCUMUnit(t *testing.T) {
+	res, status := NewQuantity(NewDecimalFloat64(10.21), NewString("cm")).
+		Compare(NewQuantity(NewDecimalFloat64(10.21), NewString("m")))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, -1, res)
+}
+
+func TestQuantityCompareUCUMSpecialUnit(t *testing.T) {
+	res, status := NewQuantity(NewDecimalInt(37), NewString("Cel")).
+		Compare(NewQuantity(NewDecimalFloat64(98.6), NewString("[degF]")))
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, 0, res)
+}
+
 func TestQuantityCompareEqualTypeDiffers(t *testing.T) {
 	res, status := NewQuantity(NewDecimalFloat64(10.21), NewString("cm")).
 		Compare(NewString("test1"))
@@ -477,8 +661,8 @@ func TestQuantityCompareGreaterThanUnitFactor(t *testing.T) {
 func TestQuantityCompareGreaterThanUnitFactorEquivalent(t *testing.T) {
 	res, status := NewQuantity(NewDecimalFloat64(2), NewString("d")).
 		Compare(NewQuantity(NewDecimalFloat64(1), NewString("days")))
-	assert.Equal(t, Empty, status)
-	assert.Equal(t, -1, res)
+	assert.Equal(t, Evaluated, status)
+	assert.Equal(t, 1, res)
 }
 
 func TestQuantityCompareInteger(t *testing.T) {
@@ -528,7 +712,7 @@ func TestQuantityToUnit(t *testing.T) {
 			assert.Equal(t, 1.5, q.Value().Float64())
 		}
 		if assert.NotNil(t, q.Unit()) {
-			assert.Equal(t, "weeks", q.Unit().String())
+			assert.Equal(t, "week", q.Unit().String())
 		}
 	}
 }
@@ -546,6 +730,102 @@ func TestQuantityToUnitExp(t *testing.T) {
 	}
 }
 
+func TestQuantityToUnitUCUM(t *testing.T) {
+	q := NewQuantity(NewDecimalFloat64(1.5), NewString("g"))
+	q = q.ToUnit(NewString("mg"))
+	if assert.NotNil(t, q) {
+		if assert.NotNil(t, q.Value()) {
+			assert.Equal(t, 1500.0, q.Value().Float64())
+		}
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "mg", q.Unit().String())
+		}
+	}
+}
+
+func TestQuantityToUnitUCUMSpecial(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(100), NewString("Cel"))
+	q = q.ToUnit(NewString("[degF]"))
+	if assert.NotNil(t, q) {
+		if assert.NotNil(t, q.Value()) {
+			assert.Equal(t, 212.0, q.Value().Float64())
+		}
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "[degF]", q.Unit().String())
+		}
+	}
+}
+
+func TestQuantityToUnitUCUMIncommensurable(t *testing.T) {
+	q := NewQuantity(NewDecimalFloat64(1.5), NewString("g"))
+	assert.Nil(t, q.ToUnit(NewString("mL")))
+}
+
+func TestQuantityToUnitCalendarToUCUM(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(2), NewString("days"))
+	q = q.ToUnit(NewString("h"))
+	if assert.NotNil(t, q) {
+		if assert.NotNil(t, q.Value()) {
+			assert.Equal(t, 48.0, q.Value().Float64())
+		}
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "h", q.Unit().String())
+		}
+	}
+}
+
+func TestQuantityToUnitUCUMToCalendar(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(90), NewString("min"))
+	q = q.ToUnit(NewString("hour"))
+	if assert.NotNil(t, q) {
+		if assert.NotNil(t, q.Value()) {
+			assert.Equal(t, 1.5, q.Value().Float64())
+		}
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "hour", q.Unit().String())
+		}
+	}
+}
+
+func TestQuantityToUnitCalendarToUCUMExp(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(1), NewString("minutes2"))
+	q = q.ToUnit(NewString("s2"))
+	if assert.NotNil(t, q) {
+		if assert.NotNil(t, q.Value()) {
+			assert.Equal(t, 3600.0, q.Value().Float64())
+		}
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "s2", q.Unit().String())
+		}
+	}
+}
+
+func TestQuantityToUnitUCUMWeekToCalendarDay(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(1), NewString("wk"))
+	q = q.ToUnit(NewString("day"))
+	if assert.NotNil(t, q) {
+		assert.Equal(t, "7 'day'", q.String())
+	}
+}
+
+func TestQuantityToUnitNormalizedValue(t *testing.T) {
+	q := NewQuantity(NewDecimalFloat64(10.5), NewString("days"))
+	q = q.ToUnit(NewString("weeks"))
+	if assert.NotNil(t, q) {
+		assert.Equal(t, "1.5 'weeks'", q.String())
+	}
+}
+
+func TestQuantityToUnitCalendarYearToUCUM(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(1), NewString("year"))
+	assert.Nil(t, q.ToUnit(NewString("a")))
+}
+
+func TestQuantityToUnitCalendarToUCUMIncommensurable(t *testing.T) {
+	q := NewQuantity(NewDecimalInt(1), NewString("week"))
+	assert.Nil(t, q.ToUnit(NewString("mg")))
+}
+
 func TestQuantityAbsPos(t *testing.T) {
 	res := NewQuantity(NewDecimalFloat64(2.1), NewString("mg")).Abs()
 	if assert.Implements(t, (*QuantityAccessor)(nil), res) {
@@ -88,6 +88,16 @@ var quantityUnitsByName = toQuantityUnitsByName(
 	UCUMMonthQuantityUnit,
 	UCUMYearQuantityUnit)
 
+var calendarDurationUCUMCodes = map[QuantityUnitAccessor]string{
+	NanosecondQuantityUnit:  "ns",
+	MillisecondQuantityUnit: "ms",
+	SecondQuantityUnit:      "s",
+	MinuteQuantityUnit:      "min",
+	HourQuantityUnit:        "h",
+	DayQuantityUnit:         "d",
+	WeekQuantityUnit:        "wk",
+}
+
 var quantityUnitExpRegexp = regexp.MustCompile("^(.*[^\\d])([1-3])$")
 
 func IsCalendarDurationUnit(unit QuantityUnitAccessor) bool {
@@ -0,0 +1,299 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+const bundleTypeName = "Bundle"
+
+var restfulReferenceURLRegexp = regexp.MustCompile(
+	"^(https?://.+)/[A-Z][A-Za-z]+/[A-Za-z0-9\\-.]{1,64}(?:/_history/[A-Za-z0-9\\-.]{1,64})?$")
+
+type ReferenceResolver interface {
+	Resolve(ctx ContextAccessor, node interface{}, reference string) (interface{}, error)
+}
+
+type ReferenceResolverProvider interface {
+	ReferenceResolver() ReferenceResolver
+}
+
+var DefaultReferenceResolver = NewChainedReferenceResolver(
+	NewContainedReferenceResolver(), NewBundleReferenceResolver(nil))
+
+type chainedReferenceResolver struct {
+	resolvers []ReferenceResolver
+}
+
+type containedReferenceResolver struct {
+}
+
+type bundleReferenceResolver struct {
+	bundle interface{}
+}
+
+type mapReferenceResolver struct {
+	resources map[string]interface{}
+}
+
+type referenceCacheKey struct {
+	nodeType  reflect.Type
+	node      uintptr
+	reference string
+}
+
+type referenceCacheEntry struct {
+	node     interface{}
+	resource interface{}
+}
+
+type referenceCacheContext struct {
+	DelegatingContext
+	resources map[referenceCacheKey]referenceCacheEntry
+}
+
+func NewChainedReferenceResolver(resolvers ...ReferenceResolver) ReferenceResolver {
+	return &chainedReferenceResolver{resolvers}
+}
+
+func NewContainedReferenceResolver() ReferenceResolver {
+	return &containedReferenceResolver{}
+}
+
+func NewBundleReferenceResolver(bundle interface{}) ReferenceResolver {
+	return &bundleReferenceResolver{bundle}
+}
+
+func NewMapReferenceResolver(resources map[string]interface{}) ReferenceResolver {
+	return &mapReferenceResolver{resources}
+}
+
+func NewReferenceCacheContext(delegate ContextAccessor) ContextAccessor {
+	return &referenceCacheContext{NewDelegatingContext(delegate), make(map[referenceCacheKey]referenceCacheEntry)}
+}
+
+func ContextReferenceResolver(ctx ContextAccessor) ReferenceResolver {
+	var resolver ReferenceResolver
+	FindContext(ctx, func(c ContextAccessor) bool {
+		if p, ok := c.(ReferenceResolverProvider); ok {
+			resolver = p.ReferenceResolver()
+		}
+		return resolver != nil
+	})
+	if resolver == nil {
+		return DefaultReferenceResolver
+	}
+	return NewChainedReferenceResolver(resolver, DefaultReferenceResolver)
+}
+
+func ResolveReference(ctx ContextAccessor, node interface{}, reference string) (interface{}, error) {
+	if len(reference) == 0 {
+		return nil, nil
+	}
+
+	cache, _ := FindContext(ctx, func(c ContextAccessor) bool {
+		_, ok := c.(*referenceCacheContext)
+		return ok
+	}).(*referenceCacheContext)
+	var key referenceCacheKey
+	if cache != nil {
+		key.reference = reference
+		if _, sys := node.(AnyAccessor); !sys {
+			// resolution of system values does not depend on their position in the model
+			key.nodeType, key.node = nodeIdentity(node)
+		}
+		if entry, found := cache.resources[key]; found {
+			return entry.resource, nil
+		}
+	}
+
+	resource, err := ContextReferenceResolver(ctx).Resolve(ctx, node, reference)
+	if err != nil {
+		return nil, err
+	}
+	if cache != nil {
+		// the node is kept to prevent that its address is reused by another node
+		cache.resources[key] = referenceCacheEntry{node, resource}
+	}
+	return resource, nil
+}
+
+func (r *chainedReferenceResolver) Resolve(ctx ContextAccessor, node interface{}, reference string) (interface{}, error) {
+	for _, resolver := range r.resolvers {
+		resource, err := resolver.Resolve(ctx, node, reference)
+		if resource != nil || err != nil {
+			return resource, err
+		}
+	}
+	return nil, nil
+}
+
+func (r *containedReferenceResolver) Resolve(ctx ContextAccessor, node interface{}, reference string) (interface{}, error) {
+	if !strings.HasPrefix(reference, "#") {
+		return nil, nil
+	}
+
+	adapter := ctx.ModelAdapter()
+	container, _ := ctx.EnvVar(RootResourceEnvVarName)
+	if container == nil {
+		return nil, nil
+	}
+	if node != nil {
+		if _, rootResource, found := ResolveResources(adapter, container, node); found && rootResource != nil {
+			container = rootResource
+		}
+	}
+
+	id := reference[1:]
+	if len(id) == 0 {
+		return container, nil
+	}
+
+	contained, err := adapter.Navigate(container, "contained")
+	if err != nil {
+		return nil, err
+	}
+	for _, resource := range modelItems(contained) {
+		if resourceID, _ := ModelString(adapter, resource, "id"); resourceID == id {
+			return resource, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *bundleReferenceResolver) Resolve(ctx ContextAccessor, node interface{}, reference string) (interface{}, error) {
+	if strings.HasPrefix(reference, "#") {
+		return nil, nil
+	}
+
+	adapter := ctx.ModelAdapter()
+	bundle := r.bundle
+	if bundle == nil {
+		bundle, _ = ctx.EnvVar(RootResourceEnvVarName)
+	}
+	if bundle == nil || modelTypeName(adapter, bundle) != bundleTypeName {
+		return nil, nil
+	}
+
+	if i := strings.Index(reference, "/_history/"); i >= 0 {
+		reference = reference[:i]
+	}
+	relative := !strings.Contains(reference, ":")
+	if base := referenceBaseURL(adapter, bundle, node); relative && len(base) > 0 {
+		reference = base + "/" + reference
+		relative = false
+	}
+
+	entries, err := adapter.Navigate(bundle, "entry")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range modelItems(entries) {
+		resource, err := adapter.Navigate(entry, "resource")
+		if err != nil {
+			return nil, err
+		}
+		resource = unwrapModelItem(resource)
+		if resource == nil {
+			continue
+		}
+
+		if fullURL, _ := ModelString(adapter, entry, "fullUrl"); fullURL == reference {
+			return resource, nil
+		}
+		if relative {
+			if id, _ := ModelString(adapter, resource, "id"); modelTypeName(adapter, resource)+"/"+id == reference {
+				return resource, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func referenceBaseURL(adapter ModelAdapter, bundle interface{}, node interface{}) string {
+	if bundle == nil || node == nil || modelTypeName(adapter, bundle) != bundleTypeName {
+		return ""
+	}
+
+	entries, err := adapter.Navigate(bundle, "entry")
+	if err != nil {
+		return ""
+	}
+	for _, entry := range modelItems(entries) {
+		if _, _, found := ResolveResources(adapter, entry, node); found {
+			fullURL, _ := ModelString(adapter, entry, "fullUrl")
+			if parts := restfulReferenceURLRegexp.FindStringSubmatch(fullURL); parts != nil {
+				return parts[1]
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+func (r *mapReferenceResolver) Resolve(_ ContextAccessor, _ interface{}, reference string) (interface{}, error) {
+	if resource, found := r.resources[reference]; found {
+		return resource, nil
+	}
+	return nil, nil
+}
+
+func modelItems(node interface{}) []interface{} {
+	if col, ok := node.(ColAccessor); ok {
+		count := col.Count()
+		items := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			if item := col.Get(i); item != nil {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	if node == nil {
+		return nil
+	}
+	return []interface{}{node}
+}
+
+func unwrapModelItem(node interface{}) interface{} {
+	if items := modelItems(node); len(items) == 1 {
+		return items[0]
+	}
+	return nil
+}
+
+func modelTypeName(adapter ModelAdapter, node interface{}) string {
+	if typeSpec := ModelTypeSpec(adapter, node); typeSpec != nil && typeSpec.FQName() != nil {
+		return typeSpec.FQName().Name()
+	}
+	return ""
+}
@@ -0,0 +1,237 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type countingReferenceResolver struct {
+	resources map[string]interface{}
+	count     int
+}
+
+func (r *countingReferenceResolver) Resolve(_ ContextAccessor, _ interface{}, reference string) (interface{}, error) {
+	r.count++
+	return r.resources[reference], nil
+}
+
+type errorReferenceResolver struct {
+}
+
+func (r *errorReferenceResolver) Resolve(ContextAccessor, interface{}, string) (interface{}, error) {
+	return nil, fmt.Errorf("resolution failed")
+}
+
+func TestMapReferenceResolver(t *testing.T) {
+	patient := NewString("patient")
+	r := NewMapReferenceResolver(map[string]interface{}{"Patient/1": patient})
+
+	res, err := r.Resolve(nil, nil, "Patient/1")
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, patient, res)
+
+	res, err = r.Resolve(nil, nil, "Patient/2")
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestChainedReferenceResolver(t *testing.T) {
+	patient := NewString("patient")
+	other := NewString("other")
+	r := NewChainedReferenceResolver(
+		NewMapReferenceResolver(map[string]interface{}{"Patient/1": patient}),
+		NewMapReferenceResolver(map[string]interface{}{"Patient/1": other, "Patient/2": other}))
+
+	res, err := r.Resolve(nil, nil, "Patient/1")
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, patient, res)
+
+	res, err = r.Resolve(nil, nil, "Patient/2")
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, other, res)
+
+	res, err = r.Resolve(nil, nil, "Patient/3")
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestChainedReferenceResolverError(t *testing.T) {
+	r := NewChainedReferenceResolver(&errorReferenceResolver{},
+		NewMapReferenceResolver(map[string]interface{}{"Patient/1": NewString("patient")}))
+
+	res, err := r.Resolve(nil, nil, "Patient/1")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res)
+}
+
+func TestContainedReferenceResolverNotLocal(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Build()
+
+	res, err := NewContainedReferenceResolver().Resolve(ctx, nil, "Patient/1")
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestContainedReferenceResolverNoRootResource(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Build()
+
+	res, err := NewContainedReferenceResolver().Resolve(ctx, nil, "#org1")
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestBundleReferenceResolverLocal(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Build()
+
+	res, err := NewBundleReferenceResolver(nil).Resolve(ctx, nil, "#org1")
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestBundleReferenceResolverNoBundle(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Node(NewString("test")).Build()
+
+	res, err := NewBundleReferenceResolver(nil).Resolve(ctx, nil, "Patient/1")
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestContextReferenceResolverDefault(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Build()
+	assert.Same(t, DefaultReferenceResolver, ContextReferenceResolver(ctx))
+}
+
+func TestContextReferenceResolverBuilder(t *testing.T) {
+	r := NewMapReferenceResolver(nil)
+	ctx := NewContextBuilder(newTestModel(t)).ReferenceResolver(r).Build()
+	ctx = NewVariableContext(NewReferenceCacheContext(ctx), "test", nil)
+	res := ContextReferenceResolver(ctx)
+	if assert.IsType(t, &chainedReferenceResolver{}, res) {
+		assert.Equal(t, []ReferenceResolver{r, DefaultReferenceResolver}, res.(*chainedReferenceResolver).resolvers)
+	}
+}
+
+func TestResolveReferenceCached(t *testing.T) {
+	patient := NewString("patient")
+	r := &countingReferenceResolver{resources: map[string]interface{}{"Patient/1": patient}}
+	ctx := NewReferenceCacheContext(NewContextBuilder(newTestModel(t)).ReferenceResolver(r).Build())
+
+	for i := 0; i < 2; i++ {
+		res, err := ResolveReference(ctx, nil, "Patient/1")
+		assert.NoError(t, err, "no error expected")
+		assert.Same(t, patient, res)
+		res, err = ResolveReference(ctx, nil, "Patient/2")
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, res)
+	}
+	assert.Equal(t, 2, r.count)
+}
+
+func TestResolveReferenceLocalCached(t *testing.T) {
+	org := NewString("org")
+	r := &countingReferenceResolver{resources: map[string]interface{}{"#org1": org}}
+	ctx := NewReferenceCacheContext(NewContextBuilder(newTestModel(t)).ReferenceResolver(r).Build())
+
+	for i := 0; i < 3; i++ {
+		res, err := ResolveReference(ctx, nil, "#org1")
+		assert.NoError(t, err, "no error expected")
+		assert.Same(t, org, res)
+	}
+	assert.Equal(t, 1, r.count)
+}
+
+func TestResolveReferenceCachedByNode(t *testing.T) {
+	patient := NewString("patient")
+	r := &countingReferenceResolver{resources: map[string]interface{}{"Patient/1": patient}}
+	ctx := NewReferenceCacheContext(NewContextBuilder(newTestModel(t)).ReferenceResolver(r).Build())
+	node1 := map[string]interface{}{"reference": "Patient/1"}
+	node2 := map[string]interface{}{"reference": "Patient/1"}
+
+	for i := 0; i < 3; i++ {
+		res, err := ResolveReference(ctx, node1, "Patient/1")
+		assert.NoError(t, err, "no error expected")
+		assert.Same(t, patient, res)
+	}
+	assert.Equal(t, 1, r.count)
+
+	res, err := ResolveReference(ctx, node2, "Patient/1")
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, patient, res)
+	assert.Equal(t, 2, r.count)
+}
+
+func TestResolveReferenceNoCache(t *testing.T) {
+	r := &countingReferenceResolver{resources: map[string]interface{}{}}
+	ctx := NewContextBuilder(newTestModel(t)).ReferenceResolver(r).Build()
+
+	for i := 0; i < 2; i++ {
+		res, err := ResolveReference(ctx, nil, "Patient/1")
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, res)
+	}
+	assert.Equal(t, 2, r.count)
+}
+
+func TestResolveReferenceEmpty(t *testing.T) {
+	r := &countingReferenceResolver{}
+	ctx := NewContextBuilder(newTestModel(t)).ReferenceResolver(r).Build()
+
+	res, err := ResolveReference(ctx, nil, "")
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+	assert.Equal(t, 0, r.count)
+}
+
+func TestResolveReferenceError(t *testing.T) {
+	ctx := NewReferenceCacheContext(NewContextBuilder(newTestModel(t)).
+		ReferenceResolver(&errorReferenceResolver{}).Build())
+
+	res, err := ResolveReference(ctx, nil, "Patient/1")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res)
+}
+
+func TestReferenceCacheContextDelegate(t *testing.T) {
+	tracer := &testTracer{}
+	delegate := NewContextBuilder(newTestModel(t)).Tracer(tracer).Node(NewString("test")).Build()
+	ctx := NewReferenceCacheContext(delegate)
+
+	assert.Same(t, delegate, ctx.(ContextDelegator).Delegate())
+	assert.Same(t, delegate.ModelAdapter(), ctx.ModelAdapter())
+	assert.Same(t, tracer, ctx.Tracer())
+	assert.Equal(t, NewString("test"), ctx.ContextNode())
+	v, found := ctx.EnvVar(ContextEnvVarName)
+	assert.True(t, found)
+	assert.Equal(t, NewString("test"), v)
+	assert.True(t, ctx.NewCol().Empty())
+	assert.Equal(t, 1, ctx.NewColWithItem(NewString("test")).Count())
+}
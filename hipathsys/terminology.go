@@ -0,0 +1,327 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"fmt"
+	"net/url"
+)
+
+const TerminologiesEnvVarName = "terminologies"
+
+type Coding struct {
+	System string
+	Code   string
+}
+
+type TerminologyProvider interface {
+	MemberOf(valueSet string, coding Coding) (bool, error)
+	Subsumes(coding Coding, other Coding) (bool, error)
+}
+
+type TerminologyProviderAccessor interface {
+	TerminologyProvider() TerminologyProvider
+}
+
+type TerminologyService interface {
+	Expand(valueSet string, params url.Values) (interface{}, error)
+	Lookup(coding Coding, params url.Values) (interface{}, error)
+	ValidateVS(valueSet string, codings []Coding, params url.Values) (interface{}, error)
+	ValidateCS(codeSystem string, codings []Coding, params url.Values) (interface{}, error)
+	Subsumption(system string, coding Coding, other Coding, params url.Values) (string, error)
+	Translate(conceptMap string, codings []Coding, params url.Values) (interface{}, error)
+}
+
+type MethodProvider interface {
+	Method(name string) (FunctionExecutor, bool)
+}
+
+type terminologies struct {
+	service TerminologyService
+}
+
+type terminologyMethod struct {
+	BaseFunction
+	execute func(ctx ContextAccessor, args []interface{}) (interface{}, error)
+}
+
+// MethodProviderEnvVarName returns if the environment variable with the
+// specified name provides methods that are resolved at evaluation time.
+func MethodProviderEnvVarName(name string) bool {
+	return name == TerminologiesEnvVarName
+}
+
+func ContextTerminologyProvider(ctx ContextAccessor) TerminologyProvider {
+	var provider TerminologyProvider
+	FindContext(ctx, func(c ContextAccessor) bool {
+		if p, ok := c.(TerminologyProviderAccessor); ok {
+			provider = p.TerminologyProvider()
+		}
+		return provider != nil
+	})
+	return provider
+}
+
+func NewTerminologies(service TerminologyService) MethodProvider {
+	if service == nil {
+		panic("no terminology service has been specified")
+	}
+	return &terminologies{service}
+}
+
+func ModelCodings(adapter ModelAdapter, node interface{}) ([]Coding, error) {
+	var codings []Coding
+	for _, item := range modelItems(node) {
+		if s, ok := item.(StringAccessor); ok {
+			codings = append(codings, Coding{Code: s.String()})
+			continue
+		}
+
+		concept, err := adapter.Navigate(item, "coding")
+		if err != nil {
+			return nil, err
+		}
+		if items := modelItems(concept); len(items) > 0 {
+			c, err := ModelCodings(adapter, concept)
+			if err != nil {
+				return nil, err
+			}
+			codings = append(codings, c...)
+			continue
+		}
+
+		code, err := ModelString(adapter, item, "code")
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 {
+			continue
+		}
+		system, err := ModelString(adapter, item, "system")
+		if err != nil {
+			return nil, err
+		}
+		codings = append(codings, Coding{System: system, Code: code})
+	}
+	return codings, nil
+}
+
+func (t *terminologies) Method(name string) (FunctionExecutor, bool) {
+	switch name {
+	case "expand":
+		return newTerminologyMethod(name, 1, 2, t.expand), true
+	case "lookup":
+		return newTerminologyMethod(name, 1, 2, t.lookup), true
+	case "validateVS":
+		return newTerminologyMethod(name, 2, 3, t.validateVS), true
+	case "validateCS":
+		return newTerminologyMethod(name, 2, 3, t.validateCS), true
+	case "subsumes":
+		return newTerminologyMethod(name, 3, 4, t.subsumes), true
+	case "translate":
+		return newTerminologyMethod(name, 2, 3, t.translate), true
+	}
+	return nil, false
+}
+
+func (t *terminologies) expand(ctx ContextAccessor, args []interface{}) (interface{}, error) {
+	valueSet, err := terminologyURL(ctx, args[0])
+	if len(valueSet) == 0 || err != nil {
+		return nil, err
+	}
+	params, err := terminologyParams(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return terminologyResult(t.service.Expand(valueSet, params))
+}
+
+func (t *terminologies) lookup(ctx ContextAccessor, args []interface{}) (interface{}, error) {
+	coding, found, err := terminologyCoding(ctx, args[0])
+	if !found || err != nil {
+		return nil, err
+	}
+	params, err := terminologyParams(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return terminologyResult(t.service.Lookup(coding, params))
+}
+
+func (t *terminologies) validateVS(ctx ContextAccessor, args []interface{}) (interface{}, error) {
+	valueSet, err := terminologyURL(ctx, args[0])
+	if len(valueSet) == 0 || err != nil {
+		return nil, err
+	}
+	codings, err := terminologyCodings(ctx, args[1])
+	if len(codings) == 0 || err != nil {
+		return nil, err
+	}
+	params, err := terminologyParams(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	return terminologyResult(t.service.ValidateVS(valueSet, codings, params))
+}
+
+func (t *terminologies) validateCS(ctx ContextAccessor, args []interface{}) (interface{}, error) {
+	codeSystem, err := terminologyURL(ctx, args[0])
+	if len(codeSystem) == 0 || err != nil {
+		return nil, err
+	}
+	codings, err := terminologyCodings(ctx, args[1])
+	if len(codings) == 0 || err != nil {
+		return nil, err
+	}
+	params, err := terminologyParams(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	return terminologyResult(t.service.ValidateCS(codeSystem, codings, params))
+}
+
+func (t *terminologies) subsumes(ctx ContextAccessor, args []interface{}) (interface{}, error) {
+	system, err := terminologyURL(ctx, args[0])
+	if len(system) == 0 || err != nil {
+		return nil, err
+	}
+	coding, found, err := terminologyCoding(ctx, args[1])
+	if !found || err != nil {
+		return nil, err
+	}
+	other, found, err := terminologyCoding(ctx, args[2])
+	if !found || err != nil {
+		return nil, err
+	}
+	params, err := terminologyParams(args, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome, err := t.service.Subsumption(system, coding, other, params)
+	if len(outcome) == 0 || err != nil {
+		return nil, NewTerminologyError(err)
+	}
+	return NewString(outcome), nil
+}
+
+func (t *terminologies) translate(ctx ContextAccessor, args []interface{}) (interface{}, error) {
+	conceptMap, err := terminologyURL(ctx, args[0])
+	if len(conceptMap) == 0 || err != nil {
+		return nil, err
+	}
+	codings, err := terminologyCodings(ctx, args[1])
+	if len(codings) == 0 || err != nil {
+		return nil, err
+	}
+	params, err := terminologyParams(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	return terminologyResult(t.service.Translate(conceptMap, codings, params))
+}
+
+func newTerminologyMethod(name string, minParams int, maxParams int, execute func(ctx ContextAccessor, args []interface{}) (interface{}, error)) *terminologyMethod {
+	return &terminologyMethod{
+		BaseFunction: NewBaseFunction(name, -1, minParams, maxParams),
+		execute:      execute,
+	}
+}
+
+func (m *terminologyMethod) Execute(ctx ContextAccessor, _ interface{}, args []interface{}, _ Looper) (interface{}, error) {
+	return m.execute(ctx, args)
+}
+
+func terminologyResult(res interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, NewTerminologyError(err)
+	}
+	return res, nil
+}
+
+func terminologyURL(ctx ContextAccessor, node interface{}) (string, error) {
+	value := unwrapModelItem(node)
+	if value == nil {
+		if len(modelItems(node)) > 1 {
+			return "", NewKindErrorf(SingletonExpectedErrorKind, "not a single URL or resource")
+		}
+		return "", nil
+	}
+	if s, ok := value.(StringAccessor); ok {
+		return s.String(), nil
+	}
+
+	u, err := ModelString(ctx.ModelAdapter(), value, "url")
+	if err != nil {
+		return "", NewAdapterError(err)
+	}
+	if len(u) == 0 {
+		return "", NewKindErrorf(TypeMismatchErrorKind, "not a URL or a resource with URL: %T", value)
+	}
+	return u, nil
+}
+
+func terminologyCodings(ctx ContextAccessor, node interface{}) ([]Coding, error) {
+	codings, err := ModelCodings(ctx.ModelAdapter(), node)
+	if err != nil {
+		return nil, NewAdapterError(err)
+	}
+	return codings, nil
+}
+
+func terminologyCoding(ctx ContextAccessor, node interface{}) (Coding, bool, error) {
+	codings, err := terminologyCodings(ctx, node)
+	if len(codings) == 0 || err != nil {
+		return Coding{}, false, err
+	}
+	if len(codings) > 1 {
+		return Coding{}, false, NewKindErrorf(SingletonExpectedErrorKind, "not a single coding")
+	}
+	return codings[0], true, nil
+}
+
+func terminologyParams(args []interface{}, pos int) (url.Values, error) {
+	if pos >= len(args) {
+		return url.Values{}, nil
+	}
+
+	value := unwrapModelItem(args[pos])
+	if value == nil {
+		return url.Values{}, nil
+	}
+	s, ok := value.(StringAccessor)
+	if !ok {
+		return nil, NewKindErrorf(TypeMismatchErrorKind, "not valid terminology parameters: %T", value)
+	}
+	params, err := url.ParseQuery(s.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid terminology parameters: %w", err)
+	}
+	return params, nil
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"testing"
+)
+
+type testTerminologyProvider struct {
+}
+
+func (p *testTerminologyProvider) MemberOf(string, Coding) (bool, error) {
+	return true, nil
+}
+
+func (p *testTerminologyProvider) Subsumes(Coding, Coding) (bool, error) {
+	return true, nil
+}
+
+type testTerminologyService struct {
+	url     string
+	codings []Coding
+	params  url.Values
+	err     error
+}
+
+func (s *testTerminologyService) Expand(valueSet string, params url.Values) (interface{}, error) {
+	s.url, s.params = valueSet, params
+	return NewString("expand"), s.err
+}
+
+func (s *testTerminologyService) Lookup(coding Coding, params url.Values) (interface{}, error) {
+	s.codings, s.params = []Coding{coding}, params
+	return NewString("lookup"), s.err
+}
+
+func (s *testTerminologyService) ValidateVS(valueSet string, codings []Coding, params url.Values) (interface{}, error) {
+	s.url, s.codings, s.params = valueSet, codings, params
+	return NewString("validateVS"), s.err
+}
+
+func (s *testTerminologyService) ValidateCS(codeSystem string, codings []Coding, params url.Values) (interface{}, error) {
+	s.url, s.codings, s.params = codeSystem, codings, params
+	return NewString("validateCS"), s.err
+}
+
+func (s *testTerminologyService) Subsumption(system string, coding Coding, other Coding, params url.Values) (string, error) {
+	s.url, s.codings, s.params = system, []Coding{coding, other}, params
+	if s.err != nil {
+		return "", s.err
+	}
+	return "subsumes", nil
+}
+
+func (s *testTerminologyService) Translate(conceptMap string, codings []Coding, params url.Values) (interface{}, error) {
+	s.url, s.codings, s.params = conceptMap, codings, params
+	return NewString("translate"), s.err
+}
+
+type testMapModel struct {
+	testModel
+}
+
+func (a *testMapModel) Navigate(node interface{}, name string) (interface{}, error) {
+	if m, ok := node.(map[string]interface{}); ok {
+		return m[name], nil
+	}
+	return nil, fmt.Errorf("not a map: %T", node)
+}
+
+func newTestMapContext(t *testing.T, service TerminologyService) ContextAccessor {
+	return NewContextBuilder(&testMapModel{testModel{t}}).TerminologyService(service).Build()
+}
+
+func executeTerminologyMethod(ctx ContextAccessor, name string, args ...interface{}) (interface{}, error) {
+	v, _ := ctx.EnvVar(TerminologiesEnvVarName)
+	method, found := v.(MethodProvider).Method(name)
+	if !found {
+		return nil, fmt.Errorf("method not found: %s", name)
+	}
+	return method.Execute(ctx, v, args, nil)
+}
+
+func newTestNodeCol(ctx ContextAccessor, nodes ...interface{}) ColAccessor {
+	col := NewCol(ctx.ModelAdapter())
+	for _, n := range nodes {
+		col.Add(n)
+	}
+	return col
+}
+
+func testCodingNode(system string, code string) map[string]interface{} {
+	return map[string]interface{}{"system": NewString(system), "code": NewString(code)}
+}
+
+func TestContextTerminologyProviderNone(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Build()
+	assert.Nil(t, ContextTerminologyProvider(ctx))
+}
+
+func TestContextTerminologyProviderBuilder(t *testing.T) {
+	p := &testTerminologyProvider{}
+	ctx := NewContextBuilder(newTestModel(t)).TerminologyProvider(p).Build()
+	ctx = NewVariableContext(NewReferenceCacheContext(ctx), "test", nil)
+	assert.Same(t, p, ContextTerminologyProvider(ctx))
+}
+
+func TestNewTerminologiesNil(t *testing.T) {
+	assert.Panics(t, func() { NewTerminologies(nil) })
+}
+
+func TestTerminologiesEnvVar(t *testing.T) {
+	ctx := NewContextBuilder(newTestModel(t)).Build()
+	v, found := ctx.EnvVar(TerminologiesEnvVarName)
+	assert.False(t, found)
+	assert.Nil(t, v)
+
+	ctx = newTestMapContext(t, &testTerminologyService{})
+	v, found = ctx.EnvVar(TerminologiesEnvVarName)
+	assert.True(t, found)
+	assert.Implements(t, (*MethodProvider)(nil), v)
+}
+
+func TestTerminologiesMethod(t *testing.T) {
+	p := NewTerminologies(&testTerminologyService{})
+	tests := []struct {
+		name      string
+		minParams int
+		maxParams int
+	}{
+		{"expand", 1, 2},
+		{"lookup", 1, 2},
+		{"validateVS", 2, 3},
+		{"validateCS", 2, 3},
+		{"subsumes", 3, 4},
+		{"translate", 2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, found := p.Method(tt.name)
+			if assert.True(t, found) {
+				assert.Equal(t, tt.name, m.Name())
+				assert.Equal(t, tt.minParams, m.MinParams())
+				assert.Equal(t, tt.maxParams, m.MaxParams())
+			}
+		})
+	}
+}
+
+func TestTerminologiesMethodUnknown(t *testing.T) {
+	m, found := NewTerminologies(&testTerminologyService{}).Method("memberOf")
+	assert.False(t, found)
+	assert.Nil(t, m)
+}
+
+func TestTerminologiesExpand(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+
+	res, err := executeTerminologyMethod(ctx, "expand", NewString("http://example.org/vs"), NewString("count=10&filter=a"))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("expand"), res)
+	assert.Equal(t, "http://example.org/vs", s.url)
+	assert.Equal(t, url.Values{"count": {"10"}, "filter": {"a"}}, s.params)
+}
+
+func TestTerminologiesExpandResource(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+	resource := map[string]interface{}{"url": NewString("http://example.org/vs")}
+
+	res, err := executeTerminologyMethod(ctx, "expand", newTestNodeCol(ctx, resource))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("expand"), res)
+	assert.Equal(t, "http://example.org/vs", s.url)
+	assert.Equal(t, url.Values{}, s.params)
+}
+
+func TestTerminologiesExpandEmpty(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+
+	res, err := executeTerminologyMethod(ctx, "expand", nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+	assert.Empty(t, s.url)
+}
+
+func TestTerminologiesExpandInvalidURL(t *testing.T) {
+	ctx := newTestMapContext(t, &testTerminologyService{})
+
+	res, err := executeTerminologyMethod(ctx, "expand", map[string]interface{}{})
+	if assert.Error(t, err, "error expected") {
+		assert.True(t, errors.Is(err, ErrTypeMismatch))
+	}
+	assert.Nil(t, res)
+
+	res, err = executeTerminologyMethod(ctx, "expand", newTestNodeCol(ctx, NewString("a"), NewString("b")))
+	if assert.Error(t, err, "error expected") {
+		assert.True(t, errors.Is(err, ErrSingletonExpected))
+	}
+	assert.Nil(t, res)
+}
+
+func TestTerminologiesExpandInvalidParams(t *testing.T) {
+	ctx := newTestMapContext(t, &testTerminologyService{})
+
+	res, err := executeTerminologyMethod(ctx, "expand", NewString("http://example.org/vs"), NewInteger(10))
+	if assert.Error(t, err, "error expected") {
+		assert.True(t, errors.Is(err, ErrTypeMismatch))
+	}
+	assert.Nil(t, res)
+
+	res, err = executeTerminologyMethod(ctx, "expand", NewString("http://example.org/vs"), NewString("count=%zz"))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res)
+}
+
+func TestTerminologiesExpandServiceError(t *testing.T) {
+	ctx := newTestMapContext(t, &testTerminologyService{err: fmt.Errorf("test")})
+
+	res, err := executeTerminologyMethod(ctx, "expand", NewString("http://example.org/vs"))
+	if assert.Error(t, err, "error expected") {
+		assert.True(t, errors.Is(err, ErrTerminology))
+		assert.Equal(t, "test", err.Error())
+	}
+	assert.Nil(t, res)
+}
+
+func TestTerminologiesLookup(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+	concept := map[string]interface{}{"coding": newTestNodeCol(ctx, testCodingNode("http://example.org/cs", "a"))}
+
+	res, err := executeTerminologyMethod(ctx, "lookup", concept)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("lookup"), res)
+	assert.Equal(t, []Coding{{System: "http://example.org/cs", Code: "a"}}, s.codings)
+}
+
+func TestTerminologiesLookupNotSingle(t *testing.T) {
+	ctx := newTestMapContext(t, &testTerminologyService{})
+	concept := map[string]interface{}{"coding": newTestNodeCol(ctx, testCodingNode("http://example.org/cs", "a"),
+		testCodingNode("http://example.org/cs", "b"))}
+
+	res, err := executeTerminologyMethod(ctx, "lookup", concept)
+	if assert.Error(t, err, "error expected") {
+		assert.True(t, errors.Is(err, ErrSingletonExpected))
+	}
+	assert.Nil(t, res)
+}
+
+func TestTerminologiesValidateVS(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+
+	res, err := executeTerminologyMethod(ctx, "validateVS", NewString("http://example.org/vs"), NewString("a"), NewString("displayLanguage=de"))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("validateVS"), res)
+	assert.Equal(t, "http://example.org/vs", s.url)
+	assert.Equal(t, []Coding{{Code: "a"}}, s.codings)
+	assert.Equal(t, url.Values{"displayLanguage": {"de"}}, s.params)
+
+	res, err = executeTerminologyMethod(ctx, "validateVS", NewString("http://example.org/vs"), nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestTerminologiesValidateCS(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+
+	res, err := executeTerminologyMethod(ctx, "validateCS", NewString("http://example.org/cs"),
+		testCodingNode("http://example.org/cs", "a"))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("validateCS"), res)
+	assert.Equal(t, "http://example.org/cs", s.url)
+	assert.Equal(t, []Coding{{System: "http://example.org/cs", Code: "a"}}, s.codings)
+}
+
+func TestTerminologiesSubsumes(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+
+	res, err := executeTerminologyMethod(ctx, "subsumes", NewString("http://example.org/cs"), NewString("a"),
+		testCodingNode("http://example.org/cs", "b"))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("subsumes"), res)
+	assert.Equal(t, "http://example.org/cs", s.url)
+	assert.Equal(t, []Coding{{Code: "a"}, {System: "http://example.org/cs", Code: "b"}}, s.codings)
+
+	res, err = executeTerminologyMethod(ctx, "subsumes", NewString("http://example.org/cs"), NewString("a"), nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res)
+}
+
+func TestTerminologiesSubsumesServiceError(t *testing.T) {
+	ctx := newTestMapContext(t, &testTerminologyService{err: fmt.Errorf("test")})
+
+	res, err := executeTerminologyMethod(ctx, "subsumes", NewString("http://example.org/cs"), NewString("a"), NewString("b"))
+	if assert.Error(t, err, "error expected") {
+		assert.True(t, errors.Is(err, ErrTerminology))
+	}
+	assert.Nil(t, res)
+}
+
+func TestTerminologiesTranslate(t *testing.T) {
+	s := &testTerminologyService{}
+	ctx := newTestMapContext(t, s)
+	concept := map[string]interface{}{"coding": newTestNodeCol(ctx, testCodingNode("http://example.org/cs", "a"),
+		testCodingNode("http://example.org/cs", "b"))}
+
+	res, err := executeTerminologyMethod(ctx, "translate", NewString("http://example.org/cm"), concept)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, NewString("translate"), res)
+	assert.Equal(t, "http://example.org/cm", s.url)
+	assert.Equal(t, []Coding{{System: "http://example.org/cs", Code: "a"}, {System: "http://example.org/cs", Code: "b"}}, s.codings)
+}
+
+func TestModelCodingsNoCode(t *testing.T) {
+	adapter := &testMapModel{testModel{t}}
+
+	codings, err := ModelCodings(adapter, map[string]interface{}{"system": NewString("http://example.org/cs")})
+	assert.NoError(t, err, "no error expected")
+	assert.Empty(t, codings)
+}
+
+func TestModelCodingsError(t *testing.T) {
+	adapter := &testMapModel{testModel{t}}
+
+	codings, err := ModelCodings(adapter, NewInteger(10))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, codings)
+}
+
+func TestMethodProviderEnvVarName(t *testing.T) {
+	assert.True(t, MethodProviderEnvVarName(TerminologiesEnvVarName))
+	assert.False(t, MethodProviderEnvVarName(UCUMEnvVarName))
+	assert.False(t, MethodProviderEnvVarName("test"))
+}
@@ -32,40 +32,40 @@ var namespaceNameString = NewString(NamespaceName)
 
 var simpleTypeInfoTypeSpec = NewTypeSpec(NewFQTypeName("SimpleTypeInfo", NamespaceName))
 var classInfoTypeSpec = NewTypeSpec(NewFQTypeName("ClassInfo", NamespaceName))
-var classInfoElementTypeSpec = NewTypeSpec(NewFQTypeName("ClassInfoElement", NamespaceName))
+var ClassInfoElementTypeSpec = NewTypeSpec(NewFQTypeName("ClassInfoElement", NamespaceName))
 var listTypeInfoTypeSpec = NewTypeSpec(NewFQTypeName("ListTypeInfo", NamespaceName))
 var tupleTypeInfoTypeSpec = NewTypeSpec(NewFQTypeName("TupleTypeInfo", NamespaceName))
 var tupleTypeInfoElementTypeSpec = NewTypeSpec(NewFQTypeName("TupleTypeInfoElement", NamespaceName))
 
 var simpleTypeInfoTypeInfo = NewClassInfo(namespaceNameString, NewString("SimpleTypeInfo"), nil,
-	NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("namespace"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("name"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("baseType"), NewString("System.String"), nil),
 	}))
 var classInfoTypeInfo = NewClassInfo(namespaceNameString, NewString("ClassInfo"), nil,
-	NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("namespace"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("name"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("baseType"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("element"), NewString("List<System.ClassInfoElement>"), False),
 	}))
 var classInfoElementTypeInfo = NewClassInfo(namespaceNameString, NewString("ClassInfoElement"), nil,
-	NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("name"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("type"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("isOneBased"), NewString("System.Boolean"), nil),
 	}))
 var listTypeInfoTypeInfo = NewClassInfo(namespaceNameString, NewString("ListTypeInfo"), nil,
-	NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("elementType"), NewString("System.String"), nil),
 	}))
 var tupleTypeInfoTypeInfo = NewClassInfo(namespaceNameString, NewString("TupleTypeInfo"), nil,
-	NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("element"), NewString("List<System.TupleTypeInfoElement>"), False),
 	}))
 var tupleTypeInfoElementTypeInfo = NewClassInfo(namespaceNameString, NewString("TupleTypeInfoElement"), nil,
-	NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("name"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("type"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("isOneBased"), NewString("System.Boolean"), nil),
@@ -322,7 +322,7 @@ func (t *typeInfoElement) OneBased() BooleanAccessor {
 }
 
 func (t *classInfoElement) TypeSpec() TypeSpecAccessor {
-	return classInfoElementTypeSpec
+	return ClassInfoElementTypeSpec
 }
 
 func (t *classInfoElement) TypeInfo() TypeInfoAccessor {
@@ -380,3 +380,91 @@ func (t *tupleTypeInfoElement) TypeSpec() TypeSpecAccessor {
 func (t *tupleTypeInfoElement) TypeInfo() TypeInfoAccessor {
 	return tupleTypeInfoElementTypeInfo
 }
+
+type typeInfoNavigator interface {
+	navigate(name string) interface{}
+}
+
+func SimpleTypeInfoOf(typeSpec TypeSpecAccessor) SimpleTypeInfoAccessor {
+	if typeSpec == nil || typeSpec.Anonymous() {
+		return NewSimpleTypeInfo(nil, nil, nil)
+	}
+
+	var namespace StringAccessor
+	fqName := typeSpec.FQName()
+	if fqName.HasNamespace() {
+		namespace = NewString(fqName.Namespace())
+	}
+
+	var baseType StringAccessor
+	if fqBaseName := typeSpec.FQBaseName(); fqBaseName != nil {
+		baseType = NewString(fqBaseName.String())
+	}
+
+	return NewSimpleTypeInfo(namespace, NewString(fqName.Name()), baseType)
+}
+
+func NavigateTypeInfo(node interface{}, name string) (interface{}, bool) {
+	if n, ok := node.(typeInfoNavigator); ok {
+		return n.navigate(name), true
+	}
+	return nil, false
+}
+
+func (t *simpleTypeInfo) navigate(name string) interface{} {
+	switch name {
+	case "namespace":
+		return t.namespace
+	case "name":
+		return t.name
+	case "baseType":
+		return t.baseType
+	}
+	return nil
+}
+
+func (t *classInfo) navigate(name string) interface{} {
+	switch name {
+	case "namespace":
+		return t.namespace
+	case "name":
+		return t.name
+	case "baseType":
+		return t.baseType
+	case "element":
+		return t.element
+	}
+	return nil
+}
+
+func (t *listTypeInfo) navigate(name string) interface{} {
+	switch name {
+	case "namespace":
+		return t.namespace
+	case "elementType":
+		return t.elementType
+	}
+	return nil
+}
+
+func (t *tupleTypeInfo) navigate(name string) interface{} {
+	switch name {
+	case "namespace":
+		return t.namespace
+	case "element":
+		return t.element
+	}
+	return nil
+}
+
+func (t *typeInfoElement) navigate(name string) interface{} {
+	switch name {
+	case "name":
+		return t.name
+	case "type":
+		return t.typeName
+	case "isOneBased":
+		return t.oneBased
+	}
+	return nil
+}
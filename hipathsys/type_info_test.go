@@ -128,7 +128,7 @@ func TestSimpleTypeInfoEquivalentNot(t *testing.T) {
 }
 
 func TestNewClassInfo(t *testing.T) {
-	element := NewSysArrayCol(classInfoElementTypeSpec, []interface{}{
+	element := NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
 		NewClassInfoElement(NewString("val1"), NewString("System.String"), nil),
 		NewClassInfoElement(NewString("val2"), NewString("System.String"), nil),
 	})
@@ -551,3 +551,95 @@ func TestTupleTypeInfoElementEquivalentNot(t *testing.T) {
 	t2 := NewTupleTypeInfoElement(NewString("valY"), NewString("test.Decimal"), True)
 	assert.False(t, t1.Equivalent(t2))
 }
+
+func TestSimpleTypeInfoOf(t *testing.T) {
+	ti := SimpleTypeInfoOf(testTypeSpec)
+	assert.Equal(t, NewString("TEST"), ti.Namespace())
+	assert.Equal(t, NewString("type1"), ti.Name())
+	assert.Equal(t, NewString("TEST.base"), ti.BaseType())
+}
+
+func TestSimpleTypeInfoOfNoBase(t *testing.T) {
+	ti := SimpleTypeInfoOf(testBaseTypeSpec)
+	assert.Equal(t, NewString("TEST"), ti.Namespace())
+	assert.Equal(t, NewString("base"), ti.Name())
+	assert.Nil(t, ti.BaseType())
+}
+
+func TestSimpleTypeInfoOfUndefined(t *testing.T) {
+	ti := SimpleTypeInfoOf(UndefinedTypeSpec)
+	assert.Nil(t, ti.Namespace())
+	assert.Nil(t, ti.Name())
+	assert.Nil(t, ti.BaseType())
+}
+
+func TestNavigateTypeInfoSimple(t *testing.T) {
+	ti := NewSimpleTypeInfo(NewString("System"), NewString("Integer"), NewString("System.Any"))
+	res, ok := NavigateTypeInfo(ti, "namespace")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("System"), res)
+	res, ok = NavigateTypeInfo(ti, "name")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("Integer"), res)
+	res, ok = NavigateTypeInfo(ti, "baseType")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("System.Any"), res)
+	res, ok = NavigateTypeInfo(ti, "element")
+	assert.True(t, ok)
+	assert.Nil(t, res)
+}
+
+func TestNavigateTypeInfoClass(t *testing.T) {
+	e := NewSysArrayCol(ClassInfoElementTypeSpec, []interface{}{
+		NewClassInfoElement(NewString("value"), NewString("System.String"), nil),
+	})
+	ti := NewClassInfo(NewString("TEST"), NewString("type1"), nil, e)
+	res, ok := NavigateTypeInfo(ti, "name")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("type1"), res)
+	res, ok = NavigateTypeInfo(ti, "baseType")
+	assert.True(t, ok)
+	assert.Nil(t, res)
+	res, ok = NavigateTypeInfo(ti, "element")
+	assert.True(t, ok)
+	assert.Same(t, e, res)
+}
+
+func TestNavigateTypeInfoElement(t *testing.T) {
+	ti := NewClassInfoElement(NewString("value"), NewString("System.String"), False)
+	res, ok := NavigateTypeInfo(ti, "name")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("value"), res)
+	res, ok = NavigateTypeInfo(ti, "type")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("System.String"), res)
+	res, ok = NavigateTypeInfo(ti, "isOneBased")
+	assert.True(t, ok)
+	assert.Equal(t, False, res)
+}
+
+func TestNavigateTypeInfoList(t *testing.T) {
+	ti := NewListTypeInfo(NewString("System.String"))
+	res, ok := NavigateTypeInfo(ti, "namespace")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("System"), res)
+	res, ok = NavigateTypeInfo(ti, "elementType")
+	assert.True(t, ok)
+	assert.Equal(t, NewString("System.String"), res)
+}
+
+func TestNavigateTypeInfoTuple(t *testing.T) {
+	e := NewSysArrayCol(tupleTypeInfoElementTypeSpec, []interface{}{
+		NewTupleTypeInfoElement(NewString("value"), NewString("System.String"), nil),
+	})
+	ti := NewTupleTypeInfo(NewString("System"), e)
+	res, ok := NavigateTypeInfo(ti, "element")
+	assert.True(t, ok)
+	assert.Same(t, e, res)
+}
+
+func TestNavigateTypeInfoOther(t *testing.T) {
+	res, ok := NavigateTypeInfo(NewString("test"), "name")
+	assert.False(t, ok)
+	assert.Nil(t, res)
+}
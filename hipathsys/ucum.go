@@ -0,0 +1,686 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	_ "embed"
+	"encoding/xml"
+	"fmt"
+	"github.com/shopspring/decimal"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed ucum-essence.xml
+var ucumEssenceXML []byte
+
+const ucumDecimalPrecision = 28
+
+var ucumExponentRegexp = regexp.MustCompile("^(.*[^\\d+-])([+-]?\\d+)$")
+
+var ucumSpecialOffsets = map[string]string{
+	"Cel":   "273.15",
+	"degF":  "459.67",
+	"degRe": "218.52",
+}
+
+type ucumEssence struct {
+	Prefixes  []ucumEssencePrefix   `xml:"prefix"`
+	BaseUnits []ucumEssenceBaseUnit `xml:"base-unit"`
+	Units     []ucumEssenceUnit     `xml:"unit"`
+}
+
+type ucumEssencePrefix struct {
+	Code  string           `xml:"Code,attr"`
+	Value ucumEssenceValue `xml:"value"`
+}
+
+type ucumEssenceBaseUnit struct {
+	Code string `xml:"Code,attr"`
+}
+
+type ucumEssenceUnit struct {
+	Code      string           `xml:"Code,attr"`
+	Metric    string           `xml:"isMetric,attr"`
+	Special   string           `xml:"isSpecial,attr"`
+	Arbitrary string           `xml:"isArbitrary,attr"`
+	Value     ucumEssenceValue `xml:"value"`
+}
+
+type ucumEssenceValue struct {
+	Unit     string               `xml:"Unit,attr"`
+	Value    string               `xml:"value,attr"`
+	Function *ucumEssenceFunction `xml:"function"`
+}
+
+type ucumEssenceFunction struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Unit  string `xml:"Unit,attr"`
+}
+
+type ucumAtom struct {
+	definition ucumEssenceUnit
+	metric     bool
+	unit       *ucumUnit
+	resolving  bool
+}
+
+type ucumRegistry struct {
+	prefixes    map[string]*big.Rat
+	prefixCodes []string
+	atoms       map[string]*ucumAtom
+	parsedUnits sync.Map
+}
+
+type ucumUnit struct {
+	code       string
+	factor     *big.Rat
+	offset     *big.Rat
+	special    bool
+	dimensions map[string]int
+	symbols    []ucumSymbol
+}
+
+type ucumSymbol struct {
+	code string
+	exp  int
+	unit *ucumUnit
+}
+
+type UCUMUnitAccessor interface {
+	Code() string
+	Special() bool
+	Commensurable(other UCUMUnitAccessor) bool
+}
+
+var ucumDimensionlessUnit = newUCUMUnit(big.NewRat(1, 1), map[string]int{})
+
+var ucumRegistryOnce sync.Once
+var ucumDefaultRegistry *ucumRegistry
+
+func ParseUCUMUnit(code string) (UCUMUnitAccessor, error) {
+	r := ucumRegistryInstance()
+	if u, ok := r.parsedUnits.Load(code); ok {
+		return u.(*ucumUnit), nil
+	}
+
+	u, err := r.parse(code)
+	if err != nil {
+		return nil, err
+	}
+	u.code = code
+	r.parsedUnits.Store(code, u)
+	return u, nil
+}
+
+func ConvertUCUMValue(value DecimalAccessor, from UCUMUnitAccessor, to UCUMUnitAccessor) DecimalAccessor {
+	f, ok1 := from.(*ucumUnit)
+	t, ok2 := to.(*ucumUnit)
+	if value == nil || !ok1 || !ok2 || !f.Commensurable(t) {
+		return nil
+	}
+	if f == t {
+		return value
+	}
+
+	v := new(big.Rat).Add(decimalRat(value.Decimal()), f.offset)
+	v.Mul(v, f.factor)
+	v.Quo(v, t.factor)
+	v.Sub(v, t.offset)
+	return NewDecimal(ratDecimal(v))
+}
+
+func (u *ucumUnit) Code() string {
+	return u.code
+}
+
+func (u *ucumUnit) Special() bool {
+	return u.special
+}
+
+func (u *ucumUnit) Commensurable(other UCUMUnitAccessor) bool {
+	o, ok := other.(*ucumUnit)
+	if !ok || len(u.dimensions) != len(o.dimensions) {
+		return false
+	}
+	for d, exp := range u.dimensions {
+		if o.dimensions[d] != exp {
+			return false
+		}
+	}
+	return true
+}
+
+func ucumRegistryInstance() *ucumRegistry {
+	ucumRegistryOnce.Do(func() {
+		r, err := newUCUMRegistry(ucumEssenceXML)
+		if err != nil {
+			panic(err)
+		}
+		ucumDefaultRegistry = r
+	})
+	return ucumDefaultRegistry
+}
+
+func newUCUMRegistry(data []byte) (*ucumRegistry, error) {
+	var essence ucumEssence
+	if err := xml.Unmarshal(data, &essence); err != nil {
+		return nil, fmt.Errorf("invalid UCUM essence: %v", err)
+	}
+
+	r := &ucumRegistry{
+		prefixes: make(map[string]*big.Rat),
+		atoms:    make(map[string]*ucumAtom),
+	}
+	for _, p := range essence.Prefixes {
+		f, ok := new(big.Rat).SetString(p.Value.Value)
+		if !ok {
+			return nil, fmt.Errorf("invalid UCUM prefix value: %s", p.Code)
+		}
+		r.prefixes[p.Code] = f
+		r.prefixCodes = append(r.prefixCodes, p.Code)
+	}
+	sort.SliceStable(r.prefixCodes, func(i, j int) bool {
+		return len(r.prefixCodes[i]) > len(r.prefixCodes[j])
+	})
+
+	for _, b := range essence.BaseUnits {
+		r.atoms[b.Code] = &ucumAtom{
+			metric: true,
+			unit:   newUCUMUnit(big.NewRat(1, 1), map[string]int{b.Code: 1}),
+		}
+	}
+	for _, u := range essence.Units {
+		r.atoms[u.Code] = &ucumAtom{
+			definition: u,
+			metric:     u.Metric == "yes",
+		}
+	}
+
+	for code := range r.atoms {
+		if _, err := r.resolve(code); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func newUCUMUnit(factor *big.Rat, dimensions map[string]int) *ucumUnit {
+	return &ucumUnit{
+		factor:     factor,
+		offset:     new(big.Rat),
+		dimensions: dimensions,
+	}
+}
+
+func (r *ucumRegistry) resolve(code string) (*ucumUnit, error) {
+	a := r.atoms[code]
+	if a.unit != nil {
+		return a.unit, nil
+	}
+	if a.resolving {
+		return nil, fmt.Errorf("cyclic UCUM unit definition: %s", code)
+	}
+
+	a.resolving = true
+	defer func() { a.resolving = false }()
+
+	d := a.definition
+	if d.Arbitrary == "yes" && d.Value.Unit == "1" {
+		a.unit = newUCUMUnit(big.NewRat(1, 1), map[string]int{code: 1})
+		return a.unit, nil
+	}
+
+	if d.Special == "yes" {
+		f := d.Value.Function
+		if f == nil {
+			return nil, fmt.Errorf("special UCUM unit has no function: %s", code)
+		}
+		offset, ok := ucumSpecialOffsets[f.Name]
+		if !ok {
+			a.unit = newUCUMUnit(big.NewRat(1, 1), map[string]int{code: 1})
+			a.unit.special = true
+			return a.unit, nil
+		}
+		u, err := r.definedUnit(code, f.Unit, f.Value)
+		if err != nil {
+			return nil, err
+		}
+		u.offset, _ = new(big.Rat).SetString(offset)
+		u.special = true
+		a.unit = u
+		return u, nil
+	}
+
+	u, err := r.definedUnit(code, d.Value.Unit, d.Value.Value)
+	if err != nil {
+		return nil, err
+	}
+	a.unit = u
+	return u, nil
+}
+
+func (r *ucumRegistry) definedUnit(code string, unit string, value string) (*ucumUnit, error) {
+	f, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("invalid UCUM unit value: %s", code)
+	}
+
+	u, err := r.parse(unit)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UCUM unit definition %s: %v", code, err)
+	}
+	return newUCUMUnit(f.Mul(f, u.factor), u.dimensions), nil
+}
+
+func (r *ucumRegistry) parse(code string) (*ucumUnit, error) {
+	if len(code) == 0 {
+		return nil, fmt.Errorf("UCUM unit must not be empty")
+	}
+
+	p := &ucumParser{registry: r, code: code}
+	u, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(code) {
+		return nil, fmt.Errorf("invalid UCUM unit %s: unexpected character at %d", code, p.pos)
+	}
+	return u, nil
+}
+
+func (r *ucumRegistry) atom(symbol string) (*ucumUnit, error) {
+	if _, ok := r.atoms[symbol]; ok {
+		return r.resolve(symbol)
+	}
+
+	for _, p := range r.prefixCodes {
+		if !strings.HasPrefix(symbol, p) {
+			continue
+		}
+		if a, ok := r.atoms[symbol[len(p):]]; ok && a.metric {
+			u, err := r.resolve(symbol[len(p):])
+			if err != nil {
+				return nil, err
+			}
+			return &ucumUnit{
+				factor:     new(big.Rat).Mul(r.prefixes[p], u.factor),
+				offset:     u.offset,
+				special:    u.special,
+				dimensions: u.dimensions,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown UCUM unit: %s", symbol)
+}
+
+type ucumParser struct {
+	registry *ucumRegistry
+	code     string
+	pos      int
+}
+
+func (p *ucumParser) term() (*ucumUnit, error) {
+	res := newUCUMUnit(big.NewRat(1, 1), map[string]int{})
+	divide := false
+	if p.peek() == '/' {
+		divide = true
+		p.pos++
+	}
+
+	for {
+		u, err := p.component()
+		if err != nil {
+			return nil, err
+		}
+		if res, err = combineUCUMUnits(res, u, divide); err != nil {
+			return nil, err
+		}
+
+		switch p.peek() {
+		case '.':
+			divide = false
+		case '/':
+			divide = true
+		default:
+			return res, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *ucumParser) component() (*ucumUnit, error) {
+	var u *ucumUnit
+	switch p.peek() {
+	case '(':
+		p.pos++
+		var err error
+		if u, err = p.term(); err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("invalid UCUM unit %s: missing closing parenthesis", p.code)
+		}
+		p.pos++
+	case '{':
+		u = newUCUMUnit(big.NewRat(1, 1), map[string]int{})
+	default:
+		var err error
+		if u, err = p.simpleUnit(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek() == '{' {
+		end := strings.IndexByte(p.code[p.pos:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid UCUM unit %s: missing closing brace", p.code)
+		}
+		p.pos += end + 1
+	}
+	return u, nil
+}
+
+func (p *ucumParser) simpleUnit() (*ucumUnit, error) {
+	start := p.pos
+	for p.pos < len(p.code) {
+		c := p.code[p.pos]
+		if c == '.' || c == '/' || c == '(' || c == ')' || c == '{' {
+			break
+		}
+		if c == '[' {
+			end := strings.IndexByte(p.code[p.pos:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid UCUM unit %s: missing closing bracket", p.code)
+			}
+			p.pos += end
+		}
+		p.pos++
+	}
+
+	symbol := p.code[start:p.pos]
+	if len(symbol) == 0 {
+		return nil, fmt.Errorf("invalid UCUM unit %s: missing unit at %d", p.code, start)
+	}
+	if f, ok := new(big.Rat).SetString(symbol); ok && strings.Trim(symbol, "0123456789") == "" {
+		u := newUCUMUnit(f, map[string]int{})
+		if f.Cmp(big.NewRat(1, 1)) != 0 {
+			u.symbols = []ucumSymbol{{code: symbol, exp: 1, unit: u}}
+		}
+		return u, nil
+	}
+
+	exp := 1
+	if parts := ucumExponentRegexp.FindStringSubmatch(symbol); parts != nil {
+		symbol = parts[1]
+		if _, err := fmt.Sscan(parts[2], &exp); err != nil {
+			return nil, fmt.Errorf("invalid UCUM unit %s: invalid exponent", p.code)
+		}
+	}
+
+	u, err := p.registry.atom(symbol)
+	if err != nil {
+		return nil, err
+	}
+	if u.Special() && exp != 1 {
+		return nil, fmt.Errorf("invalid UCUM unit %s: special unit must not have an exponent", p.code)
+	}
+
+	res := *powUCUMUnit(u, exp)
+	res.symbols = []ucumSymbol{{code: symbol, exp: exp, unit: u}}
+	return &res, nil
+}
+
+func (p *ucumParser) peek() byte {
+	if p.pos < len(p.code) {
+		return p.code[p.pos]
+	}
+	return 0
+}
+
+func powUCUMUnit(u *ucumUnit, exp int) *ucumUnit {
+	if exp == 1 {
+		return u
+	}
+
+	f := big.NewRat(1, 1)
+	b := u.factor
+	if exp < 0 {
+		b = new(big.Rat).Inv(b)
+	}
+	for i := 0; i < exp || i < -exp; i++ {
+		f.Mul(f, b)
+	}
+
+	dimensions := make(map[string]int)
+	for d, e := range u.dimensions {
+		dimensions[d] = e * exp
+	}
+	res := newUCUMUnit(f, dimensions)
+	for _, s := range u.symbols {
+		res.symbols = append(res.symbols, ucumSymbol{code: s.code, exp: s.exp * exp, unit: s.unit})
+	}
+	return res
+}
+
+func combineUCUMUnits(u1 *ucumUnit, u2 *ucumUnit, divide bool) (*ucumUnit, error) {
+	if u1.Special() || u2.Special() {
+		if len(u1.dimensions) > 0 || u1.factor.Cmp(big.NewRat(1, 1)) != 0 || divide {
+			return nil, fmt.Errorf("special UCUM unit cannot be combined with other units")
+		}
+		return u2, nil
+	}
+
+	if divide {
+		u2 = powUCUMUnit(u2, -1)
+	}
+
+	dimensions := make(map[string]int)
+	for d, e := range u1.dimensions {
+		dimensions[d] = e
+	}
+	for d, e := range u2.dimensions {
+		if exp := dimensions[d] + e; exp == 0 {
+			delete(dimensions, d)
+		} else {
+			dimensions[d] = exp
+		}
+	}
+	res := newUCUMUnit(new(big.Rat).Mul(u1.factor, u2.factor), dimensions)
+	res.symbols = mergeUCUMSymbols(u1.symbols, u2.symbols)
+	return res, nil
+}
+
+func mergeUCUMSymbols(s1 []ucumSymbol, s2 []ucumSymbol) []ucumSymbol {
+	res := append(make([]ucumSymbol, 0, len(s1)+len(s2)), s1...)
+	for _, s := range s2 {
+		if i := ucumSymbolIndex(res, s.code); i >= 0 {
+			res[i].exp += s.exp
+		} else {
+			res = append(res, s)
+		}
+	}
+	return removeUCUMSymbols(res)
+}
+
+func ucumSymbolIndex(symbols []ucumSymbol, code string) int {
+	for i, s := range symbols {
+		if s.code == code {
+			return i
+		}
+	}
+	return -1
+}
+
+func removeUCUMSymbols(symbols []ucumSymbol) []ucumSymbol {
+	res := symbols[:0]
+	for _, s := range symbols {
+		if s.exp != 0 {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+func calcUCUMQuantity(v1 DecimalAccessor, u1 UCUMUnitAccessor, v2 DecimalAccessor, u2 UCUMUnitAccessor, op ArithmeticOps) (DecimalAccessor, string, error) {
+	l, ok1 := u1.(*ucumUnit)
+	r, ok2 := u2.(*ucumUnit)
+	if !ok1 || !ok2 || (op != MultiplicationOp && op != DivisionOp) {
+		return nil, "", fmt.Errorf("arithmetic operator not supported for UCUM units: %c", op)
+	}
+	if l.Special() || r.Special() {
+		return nil, "", fmt.Errorf("special UCUM units cannot be multiplied or divided: %s, %s", l.Code(), r.Code())
+	}
+
+	value, operand := decimalRat(v1.Decimal()), decimalRat(v2.Decimal())
+	sign := 1
+	if op == DivisionOp {
+		if operand.Sign() == 0 {
+			return nil, "", nil
+		}
+		value.Quo(value, operand)
+		sign = -1
+	} else {
+		value.Mul(value, operand)
+	}
+
+	symbols := append(make([]ucumSymbol, 0, len(l.symbols)+len(r.symbols)), l.symbols...)
+	for _, s := range r.symbols {
+		exp := s.exp * sign
+		i := ucumSymbolIndex(symbols, s.code)
+		if i < 0 {
+			i = commensurableUCUMSymbolIndex(symbols, s)
+		}
+
+		if i < 0 {
+			symbols = append(symbols, ucumSymbol{code: s.code, exp: exp, unit: s.unit})
+		} else {
+			if symbols[i].code != s.code {
+				f := powUCUMUnit(newUCUMUnit(new(big.Rat).Quo(s.unit.factor, symbols[i].unit.factor), nil), exp)
+				value.Mul(value, f.factor)
+			}
+			symbols[i].exp += exp
+		}
+	}
+
+	symbols = removeUCUMSymbols(symbols)
+	if f := dimensionlessUCUMFactor(symbols); f != nil {
+		value.Mul(value, f)
+		symbols = nil
+	}
+	return NewDecimal(ratDecimal(value)), formatUCUMSymbols(symbols), nil
+}
+
+func dimensionlessUCUMFactor(symbols []ucumSymbol) *big.Rat {
+	u := newUCUMUnit(big.NewRat(1, 1), map[string]int{})
+	for _, s := range symbols {
+		u, _ = combineUCUMUnits(u, powUCUMUnit(s.unit, s.exp), false)
+	}
+	if len(u.dimensions) > 0 {
+		return nil
+	}
+	return u.factor
+}
+
+func commensurableUCUMSymbolIndex(symbols []ucumSymbol, symbol ucumSymbol) int {
+	if len(symbol.unit.dimensions) == 0 || symbol.unit.Special() {
+		return -1
+	}
+	for i, s := range symbols {
+		if !s.unit.Special() && s.unit.Commensurable(symbol.unit) {
+			return i
+		}
+	}
+	return -1
+}
+
+func formatUCUMSymbols(symbols []ucumSymbol) string {
+	var b strings.Builder
+	for _, s := range symbols {
+		if s.exp > 0 {
+			if b.Len() > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(s.code)
+			if s.exp != 1 {
+				b.WriteString(strconv.Itoa(s.exp))
+			}
+		}
+	}
+	if len(symbols) == 0 {
+		b.WriteByte('1')
+	}
+
+	for _, s := range symbols {
+		if s.exp < 0 {
+			b.WriteByte('/')
+			b.WriteString(s.code)
+			if s.exp != -1 {
+				b.WriteString(strconv.Itoa(-s.exp))
+			}
+		}
+	}
+	return b.String()
+}
+
+func decimalRat(value decimal.Decimal) *big.Rat {
+	r := new(big.Rat).SetInt(value.Coefficient())
+	exp := value.Exponent()
+	if exp == 0 {
+		return r
+	}
+
+	if exp > 0 {
+		p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil)
+		return r.Mul(r, new(big.Rat).SetInt(p))
+	}
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exp)), nil)
+	return r.Quo(r, new(big.Rat).SetInt(p))
+}
+
+func ratDecimal(value *big.Rat) decimal.Decimal {
+	if value.IsInt() {
+		return decimal.NewFromBigInt(value.Num(), 0)
+	}
+
+	num := new(big.Int).Abs(value.Num())
+	scale := ucumDecimalPrecision + len(value.Denom().String()) - len(num.String())
+	if scale < 1 {
+		scale = 1
+	}
+
+	s := strings.TrimRight(value.FloatString(scale), "0")
+	d, _ := decimal.NewFromString(strings.TrimSuffix(s, "."))
+	return d
+}
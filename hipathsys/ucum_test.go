@@ -0,0 +1,282 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseUCUMUnitEmpty(t *testing.T) {
+	u, err := ParseUCUMUnit("")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitUnknown(t *testing.T) {
+	u, err := ParseUCUMUnit("xyz")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitNonMetricPrefix(t *testing.T) {
+	u, err := ParseUCUMUnit("k[in_i]")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitMissingParenthesis(t *testing.T) {
+	u, err := ParseUCUMUnit("(kg/m2")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitMissingBrace(t *testing.T) {
+	u, err := ParseUCUMUnit("mg{total")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitMissingBracket(t *testing.T) {
+	u, err := ParseUCUMUnit("[in_i")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitMissingUnit(t *testing.T) {
+	u, err := ParseUCUMUnit("kg/")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitUnexpectedCharacter(t *testing.T) {
+	u, err := ParseUCUMUnit("kg)")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitSpecialExponent(t *testing.T) {
+	u, err := ParseUCUMUnit("Cel2")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnitSpecialCombined(t *testing.T) {
+	u, err := ParseUCUMUnit("Cel/s")
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, u, "no unit expected")
+}
+
+func TestParseUCUMUnit(t *testing.T) {
+	u, err := ParseUCUMUnit("mg/dL")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, u, "unit expected") {
+		assert.Equal(t, "mg/dL", u.Code())
+		assert.False(t, u.Special())
+	}
+}
+
+func TestParseUCUMUnitCached(t *testing.T) {
+	u1, _ := ParseUCUMUnit("kg/m2")
+	u2, _ := ParseUCUMUnit("kg/m2")
+	assert.Same(t, u1, u2)
+}
+
+func TestParseUCUMUnitSpecial(t *testing.T) {
+	u, err := ParseUCUMUnit("[degF]")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, u, "unit expected") {
+		assert.True(t, u.Special())
+	}
+}
+
+func TestParseUCUMUnitNonLinearSpecial(t *testing.T) {
+	u, err := ParseUCUMUnit("[pH]")
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, u, "unit expected") {
+		assert.True(t, u.Special())
+		assert.True(t, u.Commensurable(testUCUMUnit(t, "[pH]")))
+		assert.False(t, u.Commensurable(testUCUMUnit(t, "mol/l")))
+	}
+}
+
+func TestParseUCUMUnitEssence(t *testing.T) {
+	for _, code := range []string{"[pH]", "[CFU]", "Ci", "mCi", "[in_i'H2O]", "B[10.nV]", "[m/s2/Hz^(1/2)]", "cal_[15]", "[car_Au]", "[cml_i]"} {
+		_, err := ParseUCUMUnit(code)
+		assert.NoError(t, err, "no error expected for %s", code)
+	}
+}
+
+func TestUCUMUnitCommensurable(t *testing.T) {
+	assert.True(t, testUCUMUnit(t, "kg/m2").Commensurable(testUCUMUnit(t, "g.cm-2")))
+	assert.True(t, testUCUMUnit(t, "N").Commensurable(testUCUMUnit(t, "kg.m/s2")))
+	assert.True(t, testUCUMUnit(t, "%").Commensurable(testUCUMUnit(t, "1")))
+	assert.False(t, testUCUMUnit(t, "mg").Commensurable(testUCUMUnit(t, "mL")))
+	assert.False(t, testUCUMUnit(t, "[IU]").Commensurable(testUCUMUnit(t, "[arb'U]")))
+	assert.False(t, testUCUMUnit(t, "mg").Commensurable(nil))
+}
+
+func TestConvertUCUMValue(t *testing.T) {
+	assertUCUMConversion(t, "0.001", "1", "mg", "g")
+	assertUCUMConversion(t, "1500", "1.5", "g", "mg")
+	assertUCUMConversion(t, "2.54", "1", "[in_i]", "cm")
+	assertUCUMConversion(t, "1000", "1", "L", "cm3")
+	assertUCUMConversion(t, "1", "1", "mmol/L", "mol/m3")
+	assertUCUMConversion(t, "1000000000", "1", "10*3/uL", "/L")
+	assertUCUMConversion(t, "0.1", "1", "kg/m2", "g/cm2")
+	assertUCUMConversion(t, "0.133322", "1", "mm[Hg]", "kPa")
+	assertUCUMConversion(t, "0.45359237", "1", "[lb_av]", "kg")
+	assertUCUMConversion(t, "0.01", "1", "%", "1")
+	assertUCUMConversion(t, "0.001", "1", "mg{total}", "g")
+	assertUCUMConversion(t, "12", "1", "a", "mo")
+	assertUCUMConversion(t, "60", "1", "min", "s")
+	assertUCUMConversion(t, "1", "1", "[IU]", "[iU]")
+	assertUCUMConversion(t, "1", "1", "N", "kg.m/s2")
+	assertUCUMConversion(t, "1", "1", "{score}", "1")
+	assertUCUMConversion(t, "1", "37", "GBq", "Ci")
+	assertUCUMConversion(t, "10", "1", "B", "dB")
+	assertUCUMConversion(t, "0.3048", "1", "[ft_i]", "m")
+	assertUCUMConversion(t, "4.184", "1", "kcal", "kJ")
+}
+
+func TestConvertUCUMValueSpecial(t *testing.T) {
+	assertUCUMConversion(t, "310.15", "37", "Cel", "K")
+	assertUCUMConversion(t, "37", "98.6", "[degF]", "Cel")
+	assertUCUMConversion(t, "-40", "-40", "Cel", "[degF]")
+	assertUCUMConversion(t, "212", "100", "Cel", "[degF]")
+}
+
+func TestConvertUCUMValueRepeating(t *testing.T) {
+	assertUCUMConversion(t, "0.3333333333333333333333333333", "1", "[ft_i]", "[yd_i]")
+}
+
+func TestConvertUCUMValueIncommensurable(t *testing.T) {
+	assert.Nil(t, ConvertUCUMValue(NewDecimalInt(1), testUCUMUnit(t, "mg"), testUCUMUnit(t, "mL")))
+}
+
+func TestConvertUCUMValueNil(t *testing.T) {
+	assert.Nil(t, ConvertUCUMValue(nil, testUCUMUnit(t, "mg"), testUCUMUnit(t, "g")))
+}
+
+func TestCalcUCUMQuantity(t *testing.T) {
+	assertUCUMCalc(t, "5", "mg/mL", "10", "mg", "2", "mL", DivisionOp)
+	assertUCUMCalc(t, "3.0625", "m2", "1.75", "m", "1.75", "m", MultiplicationOp)
+	assertUCUMCalc(t, "22.85714285714285714285714286", "kg/m2", "70", "kg", "3.0625", "m2", DivisionOp)
+	assertUCUMCalc(t, "2", "1", "10", "mg", "5", "mg", DivisionOp)
+	assertUCUMCalc(t, "0.002", "1", "10", "mg", "5", "g", DivisionOp)
+	assertUCUMCalc(t, "20", "g", "10", "g/mL", "2", "mL", MultiplicationOp)
+	assertUCUMCalc(t, "0.02", "m3", "2", "m2", "1", "cm", MultiplicationOp)
+	assertUCUMCalc(t, "6", "mg/kg/d", "12", "mg/kg", "2", "d", DivisionOp)
+	assertUCUMCalc(t, "0.5", "/s", "1", "1", "2", "s", DivisionOp)
+	assertUCUMCalc(t, "1", "1", "1", "N", "1", "kg.m/s2", DivisionOp)
+	assertUCUMCalc(t, "1000", "1", "1", "10*3/uL", "1", "uL", MultiplicationOp)
+}
+
+func TestCalcUCUMQuantityUnsupportedOp(t *testing.T) {
+	v, unit, err := calcUCUMQuantity(NewDecimalInt(1), testUCUMUnit(t, "g"),
+		NewDecimalInt(1), testUCUMUnit(t, "g"), AdditionOp)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, v, "no value expected")
+	assert.Empty(t, unit)
+}
+
+func TestCalcUCUMQuantitySpecial(t *testing.T) {
+	v, unit, err := calcUCUMQuantity(NewDecimalInt(1), testUCUMUnit(t, "m"),
+		NewDecimalInt(1), testUCUMUnit(t, "Cel"), DivisionOp)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, v, "no value expected")
+	assert.Empty(t, unit)
+}
+
+func TestNewUCUMRegistryInvalid(t *testing.T) {
+	r, err := newUCUMRegistry([]byte("<root"))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r, "no registry expected")
+}
+
+func TestNewUCUMRegistryCyclic(t *testing.T) {
+	r, err := newUCUMRegistry([]byte(`<root>
+  <base-unit Code="m"/>
+  <unit Code="x"><value Unit="y" value="1"/></unit>
+  <unit Code="y"><value Unit="x" value="1"/></unit>
+</root>`))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, r, "no registry expected")
+}
+
+func TestNewUCUMRegistryNonLinearFunction(t *testing.T) {
+	r, err := newUCUMRegistry([]byte(`<root>
+  <base-unit Code="K"/>
+  <unit Code="x" isSpecial="yes"><value Unit="x(1 K)"><function name="x" value="1" Unit="K"/></value></unit>
+</root>`))
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, r, "registry expected") {
+		x, _ := r.parse("x")
+		k, _ := r.parse("K")
+		assert.True(t, x.Special())
+		assert.False(t, x.Commensurable(k))
+	}
+}
+
+func testUCUMUnit(t *testing.T, code string) UCUMUnitAccessor {
+	u, err := ParseUCUMUnit(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
+
+func assertUCUMConversion(t *testing.T, expected string, value string, from string, to string) {
+	v, err := ParseDecimal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := ConvertUCUMValue(v, testUCUMUnit(t, from), testUCUMUnit(t, to))
+	if assert.NotNil(t, res, "result expected: %s -> %s", from, to) {
+		assert.Equal(t, expected, res.String(), "%s -> %s", from, to)
+	}
+}
+
+func assertUCUMCalc(t *testing.T, expectedValue string, expectedUnit string, v1 string, u1 string, v2 string, u2 string, op ArithmeticOps) {
+	d1, err := ParseDecimal(v1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, err := ParseDecimal(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, unit, err := calcUCUMQuantity(d1, testUCUMUnit(t, u1), d2, testUCUMUnit(t, u2), op)
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, v, "value expected: %s %c %s", u1, op, u2) {
+		assert.Equal(t, expectedValue, v.String(), "%s %c %s", u1, op, u2)
+	}
+	assert.Equal(t, expectedUnit, unit, "%s %c %s", u1, op, u2)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+//  1. Redistributions of source code must retain the above copyright notice, this
+//     list of conditions and the following disclaimer.
+//
+//  2. Redistributions in binary form must reproduce the above copyright notice,
+//     this list of conditions and the following disclaimer in the documentation
+//     and/or other materials provided with the distribution.
+//
+//  3. Neither the name of the copyright holder nor the names of its
+//     contributors may be used to endorse or promote products derived from
+//     this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package hipathsys
+
+import "sync"
+
+type variableContext struct {
+	DelegatingContext
+	name  string
+	value interface{}
+}
+
+type VariableContextAccessor interface {
+	ContextAccessor
+	Delegate() ContextAccessor
+	VariableName() string
+	VariableValue() interface{}
+}
+
+type systemEnvVarContext struct {
+	DelegatingContext
+	node         interface{}
+	resourceOnce sync.Once
+	resource     interface{}
+	rootResource interface{}
+}
+
+// NewVariableContext defines a variable that is visible to all evaluations
+// that use the returned context. The variable can be accessed by its name as
+// environment variable and shadows variables of the delegate with the same name.
+func NewVariableContext(delegate ContextAccessor, name string, value interface{}) VariableContextAccessor {
+	return &variableContext{NewDelegatingContext(delegate), name, value}
+}
+
+func LookupVariable(ctx ContextAccessor, name string) (interface{}, bool) {
+	if c, ok := FindContext(ctx, func(c ContextAccessor) bool {
+		v, ok := c.(VariableContextAccessor)
+		return ok && v.VariableName() == name
+	}).(VariableContextAccessor); ok {
+		return c.VariableValue(), true
+	}
+	return nil, false
+}
+
+func (c *variableContext) VariableName() string {
+	return c.name
+}
+
+func (c *variableContext) VariableValue() interface{} {
+	return c.value
+}
+
+func (c *variableContext) EnvVar(name string) (interface{}, bool) {
+	if name == c.name {
+		return c.value, true
+	}
+	return c.delegate.EnvVar(name)
+}
+
+func NewSystemEnvVarContext(delegate ContextAccessor, node interface{}) ContextAccessor {
+	return &systemEnvVarContext{DelegatingContext: NewDelegatingContext(delegate), node: node}
+}
+
+func (c *systemEnvVarContext) EnvVar(name string) (interface{}, bool) {
+	if value, found := c.delegate.EnvVar(name); found {
+		return value, true
+	}
+
+	switch name {
+	case ContextEnvVarName:
+		return c.node, true
+	case ResourceEnvVarName:
+		c.resolveResources()
+		return c.resource, true
+	case RootResourceEnvVarName:
+		c.resolveResources()
+		return c.rootResource, true
+	}
+	return SystemEnvVar(name)
+}
+
+func (c *systemEnvVarContext) resolveResources() {
+	c.resourceOnce.Do(func() {
+		if c.node != nil {
+			c.resource, c.rootResource, _ = ResolveResources(c.ModelAdapter(), c.node, c.node)
+		}
+	})
+}
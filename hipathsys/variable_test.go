@@ -0,0 +1,126 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package hipathsys
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestVariableContextDelegate(t *testing.T) {
+	delegate := NewContextBuilder(newTestModel(t)).
+		Node(NewString("node")).
+		Tracer(&testTracer{}).
+		EnvVar("test", NewString("value")).
+		Build()
+	ctx := NewVariableContext(delegate, "x", NewString("var"))
+
+	assert.Same(t, delegate, ctx.Delegate())
+	assert.Equal(t, "x", ctx.VariableName())
+	assert.Equal(t, NewString("var"), ctx.VariableValue())
+	assert.Same(t, delegate.ModelAdapter(), ctx.ModelAdapter())
+	assert.Same(t, delegate.Tracer(), ctx.Tracer())
+	assert.Same(t, delegate.ContextNode(), ctx.ContextNode())
+	v, found := ctx.EnvVar("test")
+	assert.True(t, found)
+	assert.Equal(t, NewString("value"), v)
+	v, found = ctx.EnvVar("x")
+	assert.True(t, found)
+	assert.Equal(t, NewString("var"), v)
+	assert.Equal(t, 0, ctx.NewCol().Count())
+	assert.Equal(t, 1, ctx.NewColWithItem(NewString("test")).Count())
+}
+
+func TestLookupVariable(t *testing.T) {
+	ctx := NewVariableContext(NewVariableContext(newTestContext(t),
+		"x", NewString("outer")), "y", NewString("inner"))
+
+	v, found := LookupVariable(ctx, "x")
+	assert.True(t, found)
+	assert.Equal(t, NewString("outer"), v)
+
+	v, found = LookupVariable(ctx, "y")
+	assert.True(t, found)
+	assert.Equal(t, NewString("inner"), v)
+
+	v, found = LookupVariable(ctx, "z")
+	assert.False(t, found)
+	assert.Nil(t, v)
+}
+
+func TestLookupVariableShadowed(t *testing.T) {
+	ctx := NewVariableContext(NewVariableContext(newTestContext(t),
+		"x", NewString("outer")), "x", NewString("inner"))
+
+	v, found := LookupVariable(ctx, "x")
+	assert.True(t, found)
+	assert.Equal(t, NewString("inner"), v)
+}
+
+func TestVariableContextEnvVarShadowed(t *testing.T) {
+	ctx := NewVariableContext(NewVariableContext(newTestContext(t),
+		"x", NewString("outer")), "x", NewString("inner"))
+
+	v, found := ctx.EnvVar("x")
+	assert.True(t, found)
+	assert.Equal(t, NewString("inner"), v)
+}
+
+func TestSystemEnvVarContext(t *testing.T) {
+	delegate := NewVariableContext(newTestContext(t), "sct", NewString("other"))
+	node := NewString("node")
+	ctx := NewSystemEnvVarContext(delegate, node)
+
+	if d, ok := ctx.(ContextDelegator); assert.True(t, ok) {
+		assert.Same(t, delegate, d.Delegate())
+	}
+	assert.Same(t, delegate.ModelAdapter(), ctx.ModelAdapter())
+	assert.Equal(t, delegate.Tracer(), ctx.Tracer())
+	assert.Equal(t, delegate.ContextNode(), ctx.ContextNode())
+	assert.Equal(t, 0, ctx.NewCol().Count())
+	assert.Equal(t, 1, ctx.NewColWithItem(NewString("test")).Count())
+
+	v, found := ctx.EnvVar("sct")
+	assert.True(t, found)
+	assert.Equal(t, NewString("other"), v)
+	v, found = ctx.EnvVar("loinc")
+	assert.True(t, found)
+	assert.Equal(t, LOINCSystemURI, v)
+	v, found = ctx.EnvVar("context")
+	assert.True(t, found)
+	assert.Same(t, node, v)
+	v, found = ctx.EnvVar("resource")
+	assert.True(t, found)
+	assert.Nil(t, v, "node is not a resource")
+	v, found = ctx.EnvVar("rootResource")
+	assert.True(t, found)
+	assert.Nil(t, v, "node is not a resource")
+	_, found = ctx.EnvVar("test")
+	assert.False(t, found)
+}
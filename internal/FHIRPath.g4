@@ -32,7 +32,7 @@ expression
         | expression 'and' expression                               #andExpression
         | expression ('or' | 'xor') expression                      #orExpression
         | expression 'implies' expression                           #impliesExpression
-        //| (IDENTIFIER)? '=>' expression                             #lambdaExpression
+        | (IDENTIFIER)? '=>' expression                             #lambdaExpression
         ;
 
 term
@@ -47,6 +47,7 @@ literal
         | ('true' | 'false')                                    #booleanLiteral
         | STRING                                                #stringLiteral
         | NUMBER                                                #numberLiteral
+        | LONGNUMBER                                            #longNumberLiteral
         | DATE                                                  #dateLiteral
         | DATETIME                                              #dateTimeLiteral
         | TIME                                                  #timeLiteral
@@ -162,6 +163,10 @@ NUMBER
         : [0-9]+('.' [0-9]+)?
         ;
 
+LONGNUMBER
+        : [0-9]+ 'L'
+        ;
+
 // Pipe whitespace to the HIDDEN channel to support retrieving source text through the parser.
 WS
         : [ \r\n\t]+ -> channel(HIDDEN)
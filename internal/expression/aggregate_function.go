@@ -30,8 +30,15 @@ package expression
 
 import (
 	"github.com/healthiop/hipath/hipathsys"
+	"sort"
 )
 
+type aggregateValue interface {
+	hipathsys.DecimalValueAccessor
+	hipathsys.ArithmeticApplier
+	hipathsys.Comparator
+}
+
 type aggregateFunction struct {
 	hipathsys.BaseFunction
 }
@@ -56,6 +63,10 @@ func (f *aggregateFunction) Execute(ctx hipathsys.ContextAccessor, node interfac
 		col := wrapCollection(ctx, node)
 		count := col.Count()
 		for i := 0; i < count; i++ {
+			if err := hipathsys.CheckCanceled(ctx); err != nil {
+				return nil, err
+			}
+
 			this := col.Get(i)
 			loop.IncIndex(this)
 
@@ -69,3 +80,216 @@ func (f *aggregateFunction) Execute(ctx hipathsys.ContextAccessor, node interfac
 
 	return loop.Total(), nil
 }
+
+type sumFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newSumFunction() *sumFunction {
+	return &sumFunction{
+		BaseFunction: hipathsys.NewBaseFunction("sum", -1, 0, 0),
+	}
+}
+
+func (f *sumFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	values, err := aggregateValues(ctx, node)
+	if len(values) == 0 || err != nil {
+		return nil, err
+	}
+	return sumValues(values)
+}
+
+type avgFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newAvgFunction() *avgFunction {
+	return &avgFunction{
+		BaseFunction: hipathsys.NewBaseFunction("avg", -1, 0, 0),
+	}
+}
+
+func (f *avgFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	values, err := aggregateValues(ctx, node)
+	if len(values) == 0 || err != nil {
+		return nil, err
+	}
+
+	sum, err := sumValues(values)
+	if sum == nil || err != nil {
+		return nil, err
+	}
+	return sum.(hipathsys.ArithmeticApplier).Calc(
+		hipathsys.NewDecimalInt(int32(len(values))), hipathsys.DivisionOp)
+}
+
+type medianFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newMedianFunction() *medianFunction {
+	return &medianFunction{
+		BaseFunction: hipathsys.NewBaseFunction("median", -1, 0, 0),
+	}
+}
+
+func (f *medianFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	values, err := aggregateValues(ctx, node)
+	if len(values) == 0 || err != nil {
+		return nil, err
+	}
+
+	var cmpErr error
+	sort.SliceStable(values, func(i, j int) bool {
+		res, status := values[i].Compare(values[j])
+		if status != hipathsys.Evaluated {
+			cmpErr = hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind,
+				"values cannot be compared: %s <> %s", values[i], values[j])
+		}
+		return res < 0
+	})
+	if cmpErr != nil {
+		return nil, cmpErr
+	}
+
+	count := len(values)
+	if count%2 == 1 {
+		return values[count/2], nil
+	}
+
+	sum, err := sumValues(values[count/2-1 : count/2+1])
+	if sum == nil || err != nil {
+		return nil, err
+	}
+	return sum.(hipathsys.ArithmeticApplier).Calc(hipathsys.DecimalTwo, hipathsys.DivisionOp)
+}
+
+type minMaxFunction struct {
+	hipathsys.BaseFunction
+	max bool
+}
+
+func newMinFunction() *minMaxFunction {
+	return newMinMaxFunction("min", false)
+}
+
+func newMaxFunction() *minMaxFunction {
+	return newMinMaxFunction("max", true)
+}
+
+func newMinMaxFunction(name string, max bool) *minMaxFunction {
+	return &minMaxFunction{
+		BaseFunction: hipathsys.NewBaseFunction(name, -1, 0, 0),
+		max:          max,
+	}
+}
+
+func (f *minMaxFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	col := wrapCollection(ctx, node)
+	count := col.Count()
+
+	var res hipathsys.Comparator
+	for i := 0; i < count; i++ {
+		item, err := systemNode(ctx, col.Get(i))
+		if err != nil {
+			return nil, err
+		}
+
+		c, ok := item.(hipathsys.Comparator)
+		if !ok {
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind,
+				"%s cannot be applied on type: %T", f.Name(), col.Get(i))
+		}
+		if res == nil {
+			res = c
+			continue
+		}
+
+		cmp, status := c.Compare(res)
+		switch status {
+		case hipathsys.Empty:
+			return nil, nil
+		case hipathsys.Inconvertible:
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind,
+				"values cannot be compared: %T <> %T", c, res)
+		}
+		if (f.max && cmp > 0) || (!f.max && cmp < 0) {
+			res = c
+		}
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+	return res, nil
+}
+
+func aggregateValues(ctx hipathsys.ContextAccessor, node interface{}) ([]aggregateValue, error) {
+	col := wrapCollection(ctx, node)
+	count := col.Count()
+
+	var quantities int
+	values := make([]aggregateValue, count)
+	for i := 0; i < count; i++ {
+		item, err := systemNode(ctx, col.Get(i))
+		if err != nil {
+			return nil, err
+		}
+
+		v, ok := item.(aggregateValue)
+		if !ok {
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind,
+				"aggregate cannot be applied on type: %T", col.Get(i))
+		}
+		if _, ok := v.(hipathsys.QuantityAccessor); ok {
+			quantities++
+		}
+		values[i] = v
+	}
+
+	if quantities > 0 && quantities < count {
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind,
+			"aggregate cannot be applied on a mix of quantities and numbers")
+	}
+	return values, nil
+}
+
+func sumValues(values []aggregateValue) (hipathsys.DecimalValueAccessor, error) {
+	var sum hipathsys.DecimalValueAccessor = values[0]
+	for _, v := range values[1:] {
+		var err error
+		if sum, err = addAggregateValue(sum, v); sum == nil || err != nil {
+			return nil, err
+		}
+	}
+	return sum, nil
+}
+
+func addAggregateValue(sum hipathsys.DecimalValueAccessor, v aggregateValue) (hipathsys.DecimalValueAccessor, error) {
+	res, err := sum.(hipathsys.ArithmeticApplier).Calc(v, hipathsys.AdditionOp)
+	if err == nil {
+		return res, nil
+	}
+
+	q, ok := v.(hipathsys.QuantityAccessor)
+	if !ok {
+		return nil, err
+	}
+	converted := q.ToUnit(sum.(hipathsys.QuantityAccessor).Unit())
+	if converted == nil {
+		return nil, err
+	}
+	return sum.(hipathsys.ArithmeticApplier).Calc(converted, hipathsys.AdditionOp)
+}
+
+func systemNode(ctx hipathsys.ContextAccessor, node interface{}) (interface{}, error) {
+	if _, ok := node.(hipathsys.AnyAccessor); ok {
+		return node, nil
+	}
+
+	n, err := ctx.ModelAdapter().CastToSystem(node)
+	if n == nil || err != nil {
+		return node, err
+	}
+	return n, nil
+}
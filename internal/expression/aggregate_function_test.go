@@ -29,6 +29,7 @@
 package expression
 
 import (
+	"context"
 	"github.com/healthiop/hipath/hipathsys"
 	"github.com/healthiop/hipath/internal/test"
 	"github.com/stretchr/testify/assert"
@@ -143,3 +144,267 @@ func TestAggregatePathFuncColEmptyTotal(t *testing.T) {
 		assert.Equal(t, hipathsys.NewInteger(17), res)
 	}
 }
+
+func TestAggregateFuncCanceled(t *testing.T) {
+	ctx := test.NewCanceledTestContext(t)
+	col := ctx.NewColWithItem(hipathsys.NewInteger(0))
+
+	f := newAggregateFunction()
+	res, err := f.Execute(ctx, col, []interface{}{nil}, hipathsys.NewLoop(NewThisInvocation()))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestSumFuncEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestSumFuncInteger(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(10))
+	col.Add(hipathsys.NewInteger(11))
+	col.Add(hipathsys.NewInteger(14))
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(35), res)
+}
+
+func TestSumFuncDecimal(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(10))
+	col.Add(hipathsys.NewDecimalFloat64(1.5))
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, 11.5, res.(hipathsys.DecimalAccessor).Float64())
+	}
+}
+
+func TestSumFuncQuantity(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(1), hipathsys.NewString("second")))
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(500), hipathsys.NewString("milliseconds")))
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.QuantityAccessor)(nil), res) {
+		q := res.(hipathsys.QuantityAccessor)
+		assert.Equal(t, 1500.0, q.Value().Float64())
+		assert.Equal(t, "milliseconds", q.Unit().String())
+	}
+}
+
+func TestSumFuncUCUMQuantity(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(1), hipathsys.NewString("mg")))
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalFloat64(0.002), hipathsys.NewString("g")))
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.QuantityAccessor)(nil), res) {
+		q := res.(hipathsys.QuantityAccessor)
+		assert.Equal(t, 3.0, q.Value().Float64())
+		assert.Equal(t, "mg", q.Unit().String())
+	}
+}
+
+func TestSumFuncIncompatibleUCUMQuantity(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(1), hipathsys.NewString("mg")))
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(2), hipathsys.NewString("m")))
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestSumFuncMixedQuantity(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(1), hipathsys.NewString("g")))
+	col.Add(hipathsys.NewInteger(2))
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestSumFuncString(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("a"))
+
+	f := newSumFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestAvgFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(10))
+	col.Add(hipathsys.NewInteger(11))
+
+	f := newAvgFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, 10.5, res.(hipathsys.DecimalAccessor).Float64())
+	}
+}
+
+func TestAvgFuncUCUMQuantity(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(1), hipathsys.NewString("mg")))
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalFloat64(0.002), hipathsys.NewString("g")))
+
+	f := newAvgFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.QuantityAccessor)(nil), res) {
+		q := res.(hipathsys.QuantityAccessor)
+		assert.Equal(t, 1.5, q.Value().Float64())
+		assert.Equal(t, "mg", q.Unit().String())
+	}
+}
+
+func TestAvgFuncEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newAvgFunction()
+	res, err := f.Execute(ctx, ctx.NewCol(), []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestMedianFuncOdd(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(14))
+	col.Add(hipathsys.NewInteger(10))
+	col.Add(hipathsys.NewInteger(11))
+
+	f := newMedianFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(11), res)
+}
+
+func TestMedianFuncEven(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(14))
+	col.Add(hipathsys.NewInteger(10))
+	col.Add(hipathsys.NewInteger(20))
+	col.Add(hipathsys.NewInteger(11))
+
+	f := newMedianFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, 12.5, res.(hipathsys.DecimalAccessor).Float64())
+	}
+}
+
+func TestMedianFuncIncompatibleUnits(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(1), hipathsys.NewString("s")))
+	col.Add(hipathsys.NewQuantity(hipathsys.NewDecimalInt(1), hipathsys.NewString("cm")))
+
+	f := newMedianFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestMinFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(14))
+	col.Add(hipathsys.NewInteger(10))
+	col.Add(hipathsys.NewInteger(11))
+
+	f := newMinFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(10), res)
+}
+
+func TestMaxFuncString(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("b"))
+	col.Add(hipathsys.NewString("c"))
+	col.Add(hipathsys.NewString("a"))
+
+	f := newMaxFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("c"), res)
+}
+
+func TestMaxFuncDate(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewDateYMD(2020, 3, 4))
+	col.Add(hipathsys.NewDateYMD(2021, 1, 2))
+
+	f := newMaxFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewDateYMD(2021, 1, 2), res)
+}
+
+func TestMaxFuncPrecisionEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewDateYMDWithPrecision(2020, 1, 1, hipathsys.YearDatePrecision))
+	col.Add(hipathsys.NewDateYMD(2020, 3, 4))
+
+	f := newMaxFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestMinFuncInconvertible(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("b"))
+	col.Add(hipathsys.NewInteger(10))
+
+	f := newMinFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty res expected")
+}
+
+func TestMinFuncEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newMinFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{}, hipathsys.NewLoop(nil))
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty res expected")
+}
@@ -29,8 +29,8 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
+	"unicode/utf8"
 )
 
 type ArithmeticExpression struct {
@@ -53,24 +53,29 @@ func (e *ArithmeticExpression) Evaluate(ctx hipathsys.ContextAccessor, node inte
 		return nil, err
 	}
 
-	left, right = unwrapCollection(left), unwrapCollection(right)
+	if left, err = unwrapSingleton(left); err != nil {
+		return nil, err
+	}
+	if right, err = unwrapSingleton(right); err != nil {
+		return nil, err
+	}
 	if left == nil || right == nil {
 		return nil, nil
 	}
 
 	leftOperand, ok := left.(hipathsys.ArithmeticApplier)
 	if !ok {
-		return applyNonNumberArithmetic(left, e.op, right)
+		return applyNonNumberArithmetic(ctx, left, e.op, right)
 	}
 	rightOperand, ok := right.(hipathsys.DecimalValueAccessor)
 	if !ok {
-		return applyNonNumberArithmetic(left, e.op, right)
+		return applyNonNumberArithmetic(ctx, left, e.op, right)
 	}
 
 	return leftOperand.Calc(rightOperand, e.op)
 }
 
-func applyNonNumberArithmetic(left interface{}, op hipathsys.ArithmeticOps, right interface{}) (hipathsys.AnyAccessor, error) {
+func applyNonNumberArithmetic(ctx hipathsys.ContextAccessor, left interface{}, op hipathsys.ArithmeticOps, right interface{}) (hipathsys.AnyAccessor, error) {
 	if op == hipathsys.AdditionOp || op == hipathsys.SubtractionOp {
 		t, err := applyTemporalArithmetic(left, right,
 			op == hipathsys.SubtractionOp)
@@ -84,11 +89,14 @@ func applyNonNumberArithmetic(left interface{}, op hipathsys.ArithmeticOps, righ
 
 	if op == hipathsys.AdditionOp {
 		if s := applyStringArithmetic(left, right); s != nil {
+			if err := hipathsys.CheckStringLength(ctx, utf8.RuneCountInString(s.String())); err != nil {
+				return nil, err
+			}
 			return s, nil
 		}
 	}
 
-	return nil, fmt.Errorf("operands %T and %T do not support arithmetic operation %c", left, op, right)
+	return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "operands %T and %T do not support arithmetic operation %c", left, op, right)
 }
 
 func applyStringArithmetic(left, right interface{}) hipathsys.StringAccessor {
@@ -112,7 +120,7 @@ func applyTemporalArithmetic(left, right interface{}, negate bool) (hipathsys.Te
 		return nil, nil
 	}
 	if quantity, ok = right.(hipathsys.QuantityAccessor); !ok {
-		return nil, fmt.Errorf("only a quantity may be added to a temporal value: %T", right)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "only a quantity may be added to a temporal value: %T", right)
 	}
 
 	if negate {
@@ -57,12 +57,29 @@ func (e *BooleanExpression) Evaluate(ctx hipathsys.ContextAccessor, node interfa
 	if err != nil {
 		return nil, err
 	}
-	right, err := e.evalRight.Evaluate(ctx, node, loop)
+	leftBool, err := unwrapBooleanCollection(left)
 	if err != nil {
 		return nil, err
 	}
 
-	leftBool, err := unwrapBooleanCollection(left)
+	if leftBool != nil {
+		switch e.op {
+		case AndOp:
+			if !leftBool.Bool() {
+				return hipathsys.False, nil
+			}
+		case OrOp:
+			if leftBool.Bool() {
+				return hipathsys.True, nil
+			}
+		case ImpliesOp:
+			if !leftBool.Bool() {
+				return hipathsys.True, nil
+			}
+		}
+	}
+
+	right, err := e.evalRight.Evaluate(ctx, node, loop)
 	if err != nil {
 		return nil, err
 	}
@@ -75,32 +92,38 @@ func (e *BooleanExpression) Evaluate(ctx hipathsys.ContextAccessor, node interfa
 		return nil, nil
 	}
 
-	if e.op == ImpliesOp {
-		if leftBool == nil {
-			if rightBool.Bool() {
-				return hipathsys.True, nil
-			}
-			return nil, nil
+	switch e.op {
+	case AndOp:
+		if rightBool != nil && !rightBool.Bool() {
+			return hipathsys.False, nil
 		}
-		if leftBool.Bool() {
-			return rightBool, nil
+		if leftBool == nil || rightBool == nil {
+			return nil, nil
 		}
 		return hipathsys.True, nil
-	} else {
+	case OrOp:
+		if rightBool != nil && rightBool.Bool() {
+			return hipathsys.True, nil
+		}
 		if leftBool == nil || rightBool == nil {
 			return nil, nil
 		}
-
-		switch e.op {
-		case AndOp:
-			return hipathsys.BooleanOf(leftBool.Bool() && rightBool.Bool()), nil
-		case OrOp:
-			return hipathsys.BooleanOf(leftBool.Bool() || rightBool.Bool()), nil
-		case XOrOp:
-			return hipathsys.BooleanOf(leftBool.Bool() != rightBool.Bool()), nil
-		default:
-			panic(fmt.Sprintf("unhandled boolean operator: %d", e.op))
+		return hipathsys.False, nil
+	case XOrOp:
+		if leftBool == nil || rightBool == nil {
+			return nil, nil
+		}
+		return hipathsys.BooleanOf(leftBool.Bool() != rightBool.Bool()), nil
+	case ImpliesOp:
+		if leftBool == nil {
+			if rightBool.Bool() {
+				return hipathsys.True, nil
+			}
+			return nil, nil
 		}
+		return rightBool, nil
+	default:
+		panic(fmt.Sprintf("unhandled boolean operator: %d", e.op))
 	}
 }
 
@@ -114,7 +137,7 @@ func unwrapBooleanCollection(node interface{}) (hipathsys.BooleanAccessor, error
 			return nil, nil
 		}
 		if col.Count() > 1 {
-			return nil, fmt.Errorf("multi-valued collection cannot be converted to a boolean")
+			return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind, "multi-valued collection cannot be converted to a boolean")
 		}
 
 		v := col.Get(0)
@@ -131,5 +154,5 @@ func unwrapBooleanCollection(node interface{}) (hipathsys.BooleanAccessor, error
 		return b, nil
 	}
 
-	return nil, fmt.Errorf("value cannot be converted to a boolean: %T", node)
+	return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "value cannot be converted to a boolean: %T", node)
 }
@@ -50,14 +50,18 @@ var booleanTests = []struct {
 	{"andEmptyEmpty", AndOp, NewEmptyLiteral(), NewEmptyLiteral(), nil, false},
 	{"andEmptyTrue", AndOp, NewEmptyLiteral(), NewBooleanLiteral(true), nil, false},
 	{"andTrueEmpty", AndOp, NewBooleanLiteral(true), NewEmptyLiteral(), nil, false},
+	{"andEmptyFalse", AndOp, NewEmptyLiteral(), NewBooleanLiteral(false), hipathsys.False, false},
+	{"andFalseEmpty", AndOp, NewBooleanLiteral(false), NewEmptyLiteral(), hipathsys.False, false},
 
 	{"orFalseFalse", OrOp, NewBooleanLiteral(false), NewBooleanLiteral(false), hipathsys.False, false},
 	{"orFalseTrue", OrOp, NewBooleanLiteral(false), NewBooleanLiteral(true), hipathsys.True, false},
 	{"orTrueFalse", OrOp, NewBooleanLiteral(true), NewBooleanLiteral(false), hipathsys.True, false},
 	{"orTrueTrue", OrOp, NewBooleanLiteral(true), NewBooleanLiteral(true), hipathsys.True, false},
 	{"orEmptyEmpty", OrOp, NewEmptyLiteral(), NewEmptyLiteral(), nil, false},
-	{"orEmptyTrue", OrOp, NewEmptyLiteral(), NewBooleanLiteral(true), nil, false},
-	{"orTrueEmpty", OrOp, NewBooleanLiteral(true), NewEmptyLiteral(), nil, false},
+	{"orEmptyTrue", OrOp, NewEmptyLiteral(), NewBooleanLiteral(true), hipathsys.True, false},
+	{"orTrueEmpty", OrOp, NewBooleanLiteral(true), NewEmptyLiteral(), hipathsys.True, false},
+	{"orEmptyFalse", OrOp, NewEmptyLiteral(), NewBooleanLiteral(false), nil, false},
+	{"orFalseEmpty", OrOp, NewBooleanLiteral(false), NewEmptyLiteral(), nil, false},
 
 	{"xorFalseFalse", XOrOp, NewBooleanLiteral(false), NewBooleanLiteral(false), hipathsys.False, false},
 	{"xorFalseTrue", XOrOp, NewBooleanLiteral(false), NewBooleanLiteral(true), hipathsys.True, false},
@@ -78,7 +82,14 @@ var booleanTests = []struct {
 	{"impliesFalseEmpty", ImpliesOp, NewBooleanLiteral(false), NewEmptyLiteral(), hipathsys.True, false},
 
 	{"leftError", AndOp, newTestErrorExpression(), NewBooleanLiteral(false), nil, true},
-	{"rightError", AndOp, NewBooleanLiteral(false), newTestErrorExpression(), nil, true},
+	{"rightError", AndOp, NewBooleanLiteral(true), newTestErrorExpression(), nil, true},
+	{"xorRightError", XOrOp, NewBooleanLiteral(false), newTestErrorExpression(), nil, true},
+
+	{"andFalseShortCircuit", AndOp, NewBooleanLiteral(false), newTestErrorExpression(), hipathsys.False, false},
+	{"orTrueShortCircuit", OrOp, NewBooleanLiteral(true), newTestErrorExpression(), hipathsys.True, false},
+	{"impliesFalseShortCircuit", ImpliesOp, NewBooleanLiteral(false), newTestErrorExpression(), hipathsys.True, false},
+	{"orFalseRightError", OrOp, NewBooleanLiteral(false), newTestErrorExpression(), nil, true},
+	{"impliesTrueRightError", ImpliesOp, NewBooleanLiteral(true), newTestErrorExpression(), nil, true},
 }
 
 func TestBooleanExpression(t *testing.T) {
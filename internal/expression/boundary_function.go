@@ -0,0 +1,234 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/shopspring/decimal"
+	"time"
+)
+
+const (
+	defaultDecimalBoundaryPrecision = 8
+	maxDecimalBoundaryPrecision     = 28
+)
+
+var dateTimePrecisionDigits = map[hipathsys.DateTimePrecisions]int32{
+	hipathsys.YearDatePrecision:   4,
+	hipathsys.MonthDatePrecision:  6,
+	hipathsys.DayDatePrecision:    8,
+	hipathsys.HourTimePrecision:   10,
+	hipathsys.MinuteTimePrecision: 12,
+	hipathsys.SecondTimePrecision: 14,
+	hipathsys.NanoTimePrecision:   17,
+}
+
+var timePrecisionDigits = map[hipathsys.DateTimePrecisions]int32{
+	hipathsys.HourTimePrecision:   2,
+	hipathsys.MinuteTimePrecision: 4,
+	hipathsys.SecondTimePrecision: 6,
+	hipathsys.NanoTimePrecision:   9,
+}
+
+type precisionFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newPrecisionFunction() *precisionFunction {
+	return &precisionFunction{
+		BaseFunction: hipathsys.NewBaseFunction("precision", -1, 0, 0),
+	}
+}
+
+func (f *precisionFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	value, err := unwrapSingleton(node)
+	if value == nil || err != nil {
+		return nil, err
+	}
+
+	switch v := value.(type) {
+	case hipathsys.NumberAccessor:
+		return hipathsys.NewInteger(decimalScale(v.Decimal())), nil
+	case hipathsys.DateTemporalAccessor:
+		return hipathsys.NewInteger(dateTimePrecisionDigits[v.Precision()]), nil
+	case hipathsys.TimeAccessor:
+		return hipathsys.NewInteger(timePrecisionDigits[v.Precision()]), nil
+	}
+	return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "precision cannot be determined for type: %T", value)
+}
+
+type boundaryFunction struct {
+	hipathsys.BaseFunction
+	high bool
+}
+
+func newLowBoundaryFunction() *boundaryFunction {
+	return newBoundaryFunction("lowBoundary", false)
+}
+
+func newHighBoundaryFunction() *boundaryFunction {
+	return newBoundaryFunction("highBoundary", true)
+}
+
+func newBoundaryFunction(name string, high bool) *boundaryFunction {
+	return &boundaryFunction{
+		BaseFunction: hipathsys.NewBaseFunction(name, -1, 0, 1),
+		high:         high,
+	}
+}
+
+func (f *boundaryFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	value, err := unwrapSingleton(node)
+	if value == nil || err != nil {
+		return nil, err
+	}
+
+	var precision hipathsys.IntegerAccessor
+	if len(args) > 0 {
+		if precision, err = integerNode(args[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	switch v := value.(type) {
+	case hipathsys.NumberAccessor:
+		digits := int32(defaultDecimalBoundaryPrecision)
+		if precision != nil {
+			digits = precision.Int()
+		}
+		return decimalBoundary(v, digits, f.high), nil
+	case hipathsys.DateTimeAccessor:
+		p, ok := boundaryPrecision(dateTimePrecisionDigits, precision, hipathsys.NanoTimePrecision)
+		if !ok {
+			return nil, nil
+		}
+		t := dateTimeBoundary(v.Time(), v.Precision(), p, f.high)
+		return hipathsys.NewDateTimeYMDHMSNWithPrecision(t.Year(), int(t.Month()), t.Day(),
+			t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location(), p), nil
+	case hipathsys.TimeAccessor:
+		p, ok := boundaryPrecision(timePrecisionDigits, precision, hipathsys.NanoTimePrecision)
+		if !ok {
+			return nil, nil
+		}
+		t := dateTimeBoundary(time.Date(0, 1, 1, v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC),
+			v.Precision(), p, f.high)
+		return hipathsys.NewTimeHMSNWithPrecision(t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), p), nil
+	case hipathsys.DateAccessor:
+		p, ok := boundaryPrecision(dateTimePrecisionDigits, precision, hipathsys.DayDatePrecision)
+		if !ok || p > hipathsys.DayDatePrecision {
+			return nil, nil
+		}
+		t := dateTimeBoundary(v.Time(), v.Precision(), p, f.high)
+		return hipathsys.NewDateYMDWithPrecision(t.Year(), int(t.Month()), t.Day(), p), nil
+	}
+	return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "boundary cannot be determined for type: %T", value)
+}
+
+func decimalScale(d decimal.Decimal) int32 {
+	if exp := d.Exponent(); exp < 0 {
+		return -exp
+	}
+	return 0
+}
+
+func decimalBoundary(n hipathsys.NumberAccessor, digits int32, high bool) hipathsys.DecimalAccessor {
+	if digits < 0 || digits > maxDecimalBoundaryPrecision {
+		return nil
+	}
+
+	d := n.Decimal()
+	half := decimal.New(5, -decimalScale(d)-1)
+	if high {
+		d = d.Add(half).RoundCeil(digits)
+	} else {
+		d = d.Sub(half).RoundFloor(digits)
+	}
+	return hipathsys.NewDecimal(decimal.RequireFromString(d.StringFixed(digits)))
+}
+
+func boundaryPrecision(digitsByPrecision map[hipathsys.DateTimePrecisions]int32, digits hipathsys.IntegerAccessor,
+	defaultPrecision hipathsys.DateTimePrecisions) (hipathsys.DateTimePrecisions, bool) {
+	if digits == nil {
+		return defaultPrecision, true
+	}
+	for p, d := range digitsByPrecision {
+		if d == digits.Int() {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+func dateTimeBoundary(t time.Time, precision hipathsys.DateTimePrecisions,
+	boundaryPrecision hipathsys.DateTimePrecisions, high bool) time.Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	nano := t.Nanosecond()
+
+	if high {
+		if precision < hipathsys.MonthDatePrecision {
+			month = time.December
+		}
+		if precision < hipathsys.DayDatePrecision {
+			day = time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		}
+		if precision < hipathsys.HourTimePrecision {
+			hour = 23
+		}
+		if precision < hipathsys.MinuteTimePrecision {
+			minute = 59
+		}
+		if precision < hipathsys.SecondTimePrecision {
+			second = 59
+		}
+		if precision < hipathsys.NanoTimePrecision {
+			nano = 999_000_000
+		}
+	}
+
+	if boundaryPrecision < hipathsys.MonthDatePrecision {
+		month = time.January
+	}
+	if boundaryPrecision < hipathsys.DayDatePrecision {
+		day = 1
+	}
+	if boundaryPrecision < hipathsys.HourTimePrecision {
+		hour = 0
+	}
+	if boundaryPrecision < hipathsys.MinuteTimePrecision {
+		minute = 0
+	}
+	if boundaryPrecision < hipathsys.SecondTimePrecision {
+		second = 0
+	}
+	if boundaryPrecision < hipathsys.NanoTimePrecision {
+		nano = 0
+	}
+	return time.Date(year, month, day, hour, minute, second, nano, t.Location())
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrecisionFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newPrecisionFunction()
+	res, err := f.Execute(ctx, nil, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestPrecisionFuncDecimal(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newPrecisionFunction()
+	d, _ := hipathsys.ParseDecimal("1.58700")
+	res, err := f.Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(5), res)
+}
+
+func TestPrecisionFuncInteger(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newPrecisionFunction()
+	res, err := f.Execute(ctx, hipathsys.NewInteger(10), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(0), res)
+}
+
+func TestPrecisionFuncDate(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newPrecisionFunction()
+	d, _ := hipathsys.ParseDate("2014-03")
+	res, err := f.Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(6), res)
+}
+
+func TestPrecisionFuncDateTime(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newPrecisionFunction()
+	dt, _ := hipathsys.ParseDateTime("2014-01-05T10:30:00.000")
+	res, err := f.Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(17), res)
+}
+
+func TestPrecisionFuncTime(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newPrecisionFunction()
+	tm, _ := hipathsys.ParseTime("10:30")
+	res, err := f.Execute(ctx, tm, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(4), res)
+}
+
+func TestPrecisionFuncInvalidType(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newPrecisionFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"), nil, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestBoundaryFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newLowBoundaryFunction()
+	res, err := f.Execute(ctx, nil, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestBoundaryFuncDecimal(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	d, _ := hipathsys.ParseDecimal("1.587")
+
+	res, err := newLowBoundaryFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, "1.58650000", res.(hipathsys.DecimalAccessor).String())
+	}
+
+	res, err = newHighBoundaryFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, "1.58750000", res.(hipathsys.DecimalAccessor).String())
+	}
+
+	res, err = newLowBoundaryFunction().Execute(ctx, d, []interface{}{hipathsys.NewInteger(2)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, "1.58", res.(hipathsys.DecimalAccessor).String())
+	}
+
+	res, err = newHighBoundaryFunction().Execute(ctx, d, []interface{}{hipathsys.NewInteger(2)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, "1.59", res.(hipathsys.DecimalAccessor).String())
+	}
+}
+
+func TestBoundaryFuncNegativeDecimal(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	d, _ := hipathsys.ParseDecimal("-1.587")
+
+	res, err := newLowBoundaryFunction().Execute(ctx, d, []interface{}{hipathsys.NewInteger(2)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, "-1.59", res.(hipathsys.DecimalAccessor).String())
+	}
+}
+
+func TestBoundaryFuncInteger(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newLowBoundaryFunction().Execute(ctx, hipathsys.NewInteger(1), []interface{}{hipathsys.NewInteger(1)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, "0.5", res.(hipathsys.DecimalAccessor).String())
+	}
+}
+
+func TestBoundaryFuncDecimalInvalidPrecision(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newLowBoundaryFunction().Execute(ctx, hipathsys.NewInteger(1), []interface{}{hipathsys.NewInteger(29)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestBoundaryFuncPrecisionNoInteger(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newLowBoundaryFunction().Execute(ctx, hipathsys.NewInteger(1), []interface{}{hipathsys.NewString("1")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestBoundaryFuncDate(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	d, _ := hipathsys.ParseDate("2016-02")
+
+	res, err := newLowBoundaryFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewDateYMDWithPrecision(2016, 2, 1, hipathsys.DayDatePrecision), res)
+
+	res, err = newHighBoundaryFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewDateYMDWithPrecision(2016, 2, 29, hipathsys.DayDatePrecision), res)
+
+	res, err = newHighBoundaryFunction().Execute(ctx, d, []interface{}{hipathsys.NewInteger(4)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewDateYMDWithPrecision(2016, 1, 1, hipathsys.YearDatePrecision), res)
+
+	res, err = newHighBoundaryFunction().Execute(ctx, d, []interface{}{hipathsys.NewInteger(10)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestBoundaryFuncDateTime(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	dt, _ := hipathsys.ParseDateTime("2016-12-31T10:30+02:00")
+
+	res, err := newLowBoundaryFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DateTimeAccessor)(nil), res) {
+		assert.Equal(t, "2016-12-31T10:30:00.000000000+02:00", res.(hipathsys.DateTimeAccessor).String())
+	}
+
+	res, err = newHighBoundaryFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DateTimeAccessor)(nil), res) {
+		assert.Equal(t, "2016-12-31T10:30:59.999000000+02:00", res.(hipathsys.DateTimeAccessor).String())
+	}
+
+	res, err = newHighBoundaryFunction().Execute(ctx, dt, []interface{}{hipathsys.NewInteger(10)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DateTimeAccessor)(nil), res) {
+		assert.Equal(t, "2016-12-31T10+02:00", res.(hipathsys.DateTimeAccessor).String())
+	}
+
+	res, err = newHighBoundaryFunction().Execute(ctx, dt, []interface{}{hipathsys.NewInteger(7)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestBoundaryFuncTime(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	tm, _ := hipathsys.ParseTime("10:30")
+
+	res, err := newLowBoundaryFunction().Execute(ctx, tm, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewTimeHMSNWithPrecision(10, 30, 0, 0, hipathsys.NanoTimePrecision), res)
+
+	res, err = newHighBoundaryFunction().Execute(ctx, tm, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewTimeHMSNWithPrecision(10, 30, 59, 999_000_000, hipathsys.NanoTimePrecision), res)
+
+	res, err = newHighBoundaryFunction().Execute(ctx, tm, []interface{}{hipathsys.NewInteger(8)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestBoundaryFuncInvalidType(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newLowBoundaryFunction().Execute(ctx, hipathsys.NewString("test"), nil, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
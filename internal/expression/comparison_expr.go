@@ -62,7 +62,12 @@ func (e *ComparisonExpression) Evaluate(ctx hipathsys.ContextAccessor, node inte
 		return nil, err
 	}
 
-	left, right = unwrapCollection(left), unwrapCollection(right)
+	if left, err = unwrapSingleton(left); err != nil {
+		return nil, err
+	}
+	if right, err = unwrapSingleton(right); err != nil {
+		return nil, err
+	}
 	if left == nil || right == nil {
 		return nil, nil
 	}
@@ -70,10 +75,10 @@ func (e *ComparisonExpression) Evaluate(ctx hipathsys.ContextAccessor, node inte
 	var ok bool
 	var leftCmp, rightCmp hipathsys.Comparator
 	if leftCmp, ok = left.(hipathsys.Comparator); !ok {
-		return nil, fmt.Errorf("operand cannot be used for comparison: %T", left)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "operand cannot be used for comparison: %T", left)
 	}
 	if rightCmp, ok = right.(hipathsys.Comparator); !ok {
-		return nil, fmt.Errorf("operand cannot be used for comparison: %T", right)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "operand cannot be used for comparison: %T", right)
 	}
 
 	res, status := leftCmp.Compare(rightCmp)
@@ -81,7 +86,7 @@ func (e *ComparisonExpression) Evaluate(ctx hipathsys.ContextAccessor, node inte
 		return nil, nil
 	}
 	if status != hipathsys.Evaluated {
-		return nil, fmt.Errorf("operands cannot be compared: %T <> %T", leftCmp, rightCmp)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "operands cannot be compared: %T <> %T", leftCmp, rightCmp)
 	}
 
 	var b bool
@@ -29,8 +29,8 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
+	"math"
 	"regexp"
 	"strings"
 )
@@ -41,7 +41,7 @@ type iifFunction struct {
 
 func newIIfFunction() *iifFunction {
 	return &iifFunction{
-		BaseFunction: hipathsys.NewBaseFunction("iif", -1, 2, 3),
+		BaseFunction: hipathsys.NewLazyBaseFunction("iif", -1, 2, 3, 1, 2),
 	}
 }
 
@@ -50,20 +50,18 @@ func (f *iifFunction) Execute(_ hipathsys.ContextAccessor, _ interface{}, args [
 	var criterionValue bool
 	if criterion != nil {
 		if b, ok := criterion.(hipathsys.BooleanAccessor); !ok {
-			return nil, fmt.Errorf("criterion must be a boolean: %T", criterion)
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "criterion must be a boolean: %T", criterion)
 		} else {
 			criterionValue = b.Bool()
 		}
 	}
 
-	var res interface{}
 	if criterionValue {
-		res = args[1]
+		return hipathsys.EvaluateArg(args[1])
 	} else if len(args) > 2 {
-		res = args[2]
+		return hipathsys.EvaluateArg(args[2])
 	}
-
-	return res, nil
+	return nil, nil
 }
 
 type toBooleanFunction struct {
@@ -134,6 +132,11 @@ func (f *toIntegerFunction) Execute(_ hipathsys.ContextAccessor, node interface{
 	switch any.DataType() {
 	case hipathsys.IntegerDataType:
 		return any, nil
+	case hipathsys.LongDataType:
+		l := any.(hipathsys.LongAccessor).Int64()
+		if l >= math.MinInt32 && l <= math.MaxInt32 {
+			return hipathsys.NewInteger(int32(l)), nil
+		}
 	case hipathsys.BooleanDataType:
 		if any.(hipathsys.BooleanAccessor).Bool() {
 			return hipathsys.NewInteger(1), nil
@@ -162,6 +165,53 @@ func newConvertsToIntegerFunction() *convertsToIntegerFunction {
 	}
 }
 
+type toLongFunction struct {
+	hipathsys.BaseFunction
+}
+
+var toLongFunc = &toLongFunction{
+	BaseFunction: hipathsys.NewBaseFunction("toLong", -1, 0, 0),
+}
+
+func (f *toLongFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	any, err := convertibleAny(node)
+	if any == nil || err != nil {
+		return nil, err
+	}
+
+	switch any.DataType() {
+	case hipathsys.LongDataType:
+		return any, nil
+	case hipathsys.IntegerDataType:
+		return hipathsys.NewLong(any.(hipathsys.IntegerAccessor).Int64()), nil
+	case hipathsys.BooleanDataType:
+		if any.(hipathsys.BooleanAccessor).Bool() {
+			return hipathsys.NewLong(1), nil
+		}
+		return hipathsys.NewLong(0), nil
+	case hipathsys.StringDataType:
+		l, err := hipathsys.ParseLong(any.(hipathsys.StringAccessor).String())
+		if err == nil {
+			return l, nil
+		}
+	}
+
+	return nil, nil
+}
+
+type convertsToLongFunction struct {
+	convertsToFunction
+}
+
+func newConvertsToLongFunction() *convertsToLongFunction {
+	return &convertsToLongFunction{
+		convertsToFunction: convertsToFunction{
+			BaseFunction: hipathsys.NewBaseFunction("convertsToLong", -1, 0, 0),
+			converter:    toLongFunc,
+		},
+	}
+}
+
 type toDecimalFunction struct {
 	hipathsys.BaseFunction
 }
@@ -337,7 +387,7 @@ func (f *toQuantityFunction) Execute(_ hipathsys.ContextAccessor, node interface
 
 	if q != nil && len(args) > 0 {
 		if s, ok := args[0].(hipathsys.StringAccessor); !ok {
-			return nil, fmt.Errorf("conversion unit is no string: %T", args[0])
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "conversion unit is no string: %T", args[0])
 		} else {
 			q = q.ToUnit(s)
 		}
@@ -461,7 +511,7 @@ func convertibleAny(node interface{}) (hipathsys.AnyAccessor, error) {
 		return nil, nil
 	} else {
 		if _, ok := any.(hipathsys.ColAccessor); ok {
-			return nil, fmt.Errorf("collection with multiple items cannot be converted")
+			return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind, "collection with multiple items cannot be converted")
 		}
 		return any, nil
 	}
@@ -81,6 +81,38 @@ func TestIIfPathFuncFalseNil(t *testing.T) {
 	assert.Nil(t, res, "empty collection expected")
 }
 
+func TestIIfPathFuncLazyTrue(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newIIfFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.True,
+		hipathsys.NewLazyArg(ParseStringLiteral("match"), ctx, nil, nil),
+		hipathsys.NewLazyArg(newTestErrorExpression(), ctx, nil, nil)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("match"), res)
+}
+
+func TestIIfPathFuncLazyFalse(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newIIfFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.False,
+		hipathsys.NewLazyArg(newTestErrorExpression(), ctx, nil, nil),
+		hipathsys.NewLazyArg(ParseStringLiteral("other"), ctx, nil, nil)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("other"), res)
+}
+
+func TestIIfPathFuncLazyError(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newIIfFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.True,
+		hipathsys.NewLazyArg(newTestErrorExpression(), ctx, nil, nil)}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
 func TestIIfPathFuncInvalidType(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -345,6 +377,33 @@ func TestToIntegerFuncBooleanFalse(t *testing.T) {
 	assert.Equal(t, hipathsys.NewInteger(0), res)
 }
 
+func TestToIntegerFuncLong(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toIntegerFunc
+	res, err := f.Execute(ctx, hipathsys.NewLong(123), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(123), res)
+}
+
+func TestToIntegerFuncLongOverflow(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toIntegerFunc
+	res, err := f.Execute(ctx, hipathsys.NewLong(8_000_000_000), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestToIntegerFuncStringOverflow(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toIntegerFunc
+	res, err := f.Execute(ctx, hipathsys.NewString("8000000000"), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
 func TestConvertsToIntegerFuncNil(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -385,6 +444,85 @@ func TestConvertToIntegerNot(t *testing.T) {
 	assert.Equal(t, hipathsys.False, res)
 }
 
+func TestToLongFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toLongFunc
+	res, err := f.Execute(ctx, nil, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestToLongFuncDecimal(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toLongFunc
+	res, err := f.Execute(ctx, hipathsys.NewDecimalFloat64(12.5), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestToLongFuncLong(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toLongFunc
+	l := hipathsys.NewLong(8_000_000_000)
+	res, err := f.Execute(ctx, l, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, l, res)
+}
+
+func TestToLongFuncInteger(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toLongFunc
+	res, err := f.Execute(ctx, hipathsys.NewInteger(123), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewLong(123), res)
+}
+
+func TestToLongFuncString(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toLongFunc
+	res, err := f.Execute(ctx, hipathsys.NewString("-8000000000"), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewLong(-8_000_000_000), res)
+}
+
+func TestToLongFuncStringInvalid(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toLongFunc
+	res, err := f.Execute(ctx, hipathsys.NewString("12.5"), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestToLongFuncBoolean(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toLongFunc
+	res, err := f.Execute(ctx, hipathsys.True, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewLong(1), res)
+	res, err = f.Execute(ctx, hipathsys.False, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewLong(0), res)
+}
+
+func TestConvertsToLongFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newConvertsToLongFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("8000000000"), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+	res, err = f.Execute(ctx, hipathsys.NewString("abc"), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
 func TestToDecimalFuncNil(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -927,7 +1065,7 @@ func TestToQuantityFuncQuantityConvert(t *testing.T) {
 		q := res.(hipathsys.QuantityAccessor)
 		assert.Equal(t, 14.0, q.Value().Float64())
 		if assert.NotNil(t, q.Unit()) {
-			assert.Equal(t, "days", q.Unit().String())
+			assert.Equal(t, "day", q.Unit().String())
 		}
 	}
 }
@@ -943,6 +1081,67 @@ func TestToQuantityFuncQuantityConvertInvalidUnit(t *testing.T) {
 	assert.Nil(t, res, "no result expected")
 }
 
+func TestToQuantityFuncQuantityConvertUCUM(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toQuantityFunc
+	res, err := f.Execute(ctx, hipathsys.NewQuantity(
+		hipathsys.NewDecimalFloat64(2.5), hipathsys.NewString("g")),
+		[]interface{}{hipathsys.NewString("mg")}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.QuantityAccessor)(nil), res) {
+		q := res.(hipathsys.QuantityAccessor)
+		assert.Equal(t, 2500.0, q.Value().Float64())
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "mg", q.Unit().String())
+		}
+	}
+}
+
+func TestToQuantityFuncQuantityConvertCalendarToUCUM(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toQuantityFunc
+	res, err := f.Execute(ctx, hipathsys.NewQuantity(
+		hipathsys.NewDecimalFloat64(2), hipathsys.WeekQuantityUnit.Plural()),
+		[]interface{}{hipathsys.NewString("d")}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.QuantityAccessor)(nil), res) {
+		q := res.(hipathsys.QuantityAccessor)
+		assert.Equal(t, 14.0, q.Value().Float64())
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "d", q.Unit().String())
+		}
+	}
+}
+
+func TestToQuantityFuncQuantityConvertIncompatible(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toQuantityFunc
+	res, err := f.Execute(ctx, hipathsys.NewQuantity(
+		hipathsys.NewDecimalFloat64(2.5), hipathsys.NewString("g")),
+		[]interface{}{hipathsys.NewString("mL")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestToQuantityFuncStringConvertUCUM(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := toQuantityFunc
+	res, err := f.Execute(ctx, hipathsys.NewString("98.6 '[degF]'"),
+		[]interface{}{hipathsys.NewString("Cel")}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.QuantityAccessor)(nil), res) {
+		q := res.(hipathsys.QuantityAccessor)
+		assert.Equal(t, 37.0, q.Value().Float64())
+		if assert.NotNil(t, q.Unit()) {
+			assert.Equal(t, "Cel", q.Unit().String())
+		}
+	}
+}
+
 func TestToQuantityFuncString(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -1035,6 +1234,15 @@ func TestConvertToQuantityInconvertibleUnit(t *testing.T) {
 	assert.Equal(t, hipathsys.False, res)
 }
 
+func TestConvertToQuantityUCUMUnit(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newConvertsToQuantityFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("5 'mg'"), []interface{}{hipathsys.NewString("kg")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+}
+
 func TestConvertToQuantityNot(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -0,0 +1,302 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+	"math"
+	"time"
+)
+
+type dateTimeComponentFunction struct {
+	hipathsys.BaseFunction
+	precision hipathsys.DateTimePrecisions
+}
+
+func newYearOfFunction() *dateTimeComponentFunction {
+	return newDateTimeComponentFunction("yearOf", hipathsys.YearDatePrecision)
+}
+
+func newMonthOfFunction() *dateTimeComponentFunction {
+	return newDateTimeComponentFunction("monthOf", hipathsys.MonthDatePrecision)
+}
+
+func newDayOfFunction() *dateTimeComponentFunction {
+	return newDateTimeComponentFunction("dayOf", hipathsys.DayDatePrecision)
+}
+
+func newHourOfFunction() *dateTimeComponentFunction {
+	return newDateTimeComponentFunction("hourOf", hipathsys.HourTimePrecision)
+}
+
+func newMinuteOfFunction() *dateTimeComponentFunction {
+	return newDateTimeComponentFunction("minuteOf", hipathsys.MinuteTimePrecision)
+}
+
+func newSecondOfFunction() *dateTimeComponentFunction {
+	return newDateTimeComponentFunction("secondOf", hipathsys.SecondTimePrecision)
+}
+
+func newMillisecondOfFunction() *dateTimeComponentFunction {
+	return newDateTimeComponentFunction("millisecondOf", hipathsys.NanoTimePrecision)
+}
+
+func newDateTimeComponentFunction(name string, precision hipathsys.DateTimePrecisions) *dateTimeComponentFunction {
+	return &dateTimeComponentFunction{
+		BaseFunction: hipathsys.NewBaseFunction(name, -1, 0, 0),
+		precision:    precision,
+	}
+}
+
+func (f *dateTimeComponentFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	value, err := unwrapSingleton(node)
+	if value == nil || err != nil {
+		return nil, err
+	}
+
+	c, ok := f.component(value)
+	if !ok {
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "%s cannot be applied on type: %T", f.Name(), value)
+	}
+	if value.(hipathsys.TemporalAccessor).Precision() < f.precision {
+		return nil, nil
+	}
+	return hipathsys.NewInteger(int32(c)), nil
+}
+
+func (f *dateTimeComponentFunction) component(node interface{}) (int, bool) {
+	if f.precision <= hipathsys.DayDatePrecision {
+		d, ok := node.(hipathsys.DateTemporalAccessor)
+		if !ok {
+			return 0, false
+		}
+		switch f.precision {
+		case hipathsys.YearDatePrecision:
+			return d.Year(), true
+		case hipathsys.MonthDatePrecision:
+			return d.Month(), true
+		}
+		return d.Day(), true
+	}
+
+	t, ok := node.(hipathsys.TimeAccessor)
+	if !ok {
+		return 0, false
+	}
+	switch f.precision {
+	case hipathsys.HourTimePrecision:
+		return t.Hour(), true
+	case hipathsys.MinuteTimePrecision:
+		return t.Minute(), true
+	case hipathsys.SecondTimePrecision:
+		return t.Second(), true
+	}
+	return t.Nanosecond() / int(time.Millisecond), true
+}
+
+type timezoneOffsetOfFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newTimezoneOffsetOfFunction() *timezoneOffsetOfFunction {
+	return &timezoneOffsetOfFunction{
+		BaseFunction: hipathsys.NewBaseFunction("timezoneOffsetOf", -1, 0, 0),
+	}
+}
+
+func (f *timezoneOffsetOfFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	value, err := unwrapSingleton(node)
+	if value == nil || err != nil {
+		return nil, err
+	}
+
+	dt, ok := value.(hipathsys.DateTimeAccessor)
+	if !ok {
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "%s cannot be applied on type: %T", f.Name(), value)
+	}
+	if dt.Precision() < hipathsys.HourTimePrecision || dt.Location() == time.Local {
+		return nil, nil
+	}
+
+	_, offset := dt.Time().Zone()
+	return hipathsys.NewDecimalFloat64(float64(offset) / 3600), nil
+}
+
+type dateTimeDifferenceFunction struct {
+	hipathsys.BaseFunction
+	whole bool
+}
+
+func newDurationFunction() *dateTimeDifferenceFunction {
+	return newDateTimeDifferenceFunction("duration", true)
+}
+
+func newDifferenceFunction() *dateTimeDifferenceFunction {
+	return newDateTimeDifferenceFunction("difference", false)
+}
+
+func newDateTimeDifferenceFunction(name string, whole bool) *dateTimeDifferenceFunction {
+	return &dateTimeDifferenceFunction{
+		BaseFunction: hipathsys.NewBaseFunction(name, -1, 2, 2),
+		whole:        whole,
+	}
+}
+
+func (f *dateTimeDifferenceFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	start, err := dateTemporalNode(node)
+	if start == nil || err != nil {
+		return nil, err
+	}
+
+	end, err := dateTemporalNode(args[0])
+	if end == nil || err != nil {
+		return nil, err
+	}
+
+	unitName, err := stringNode(args[1])
+	if unitName == nil || err != nil {
+		return nil, err
+	}
+
+	unit, precision, err := calendarDurationUnit(unitName.String())
+	if err != nil {
+		return nil, err
+	}
+	if start.Precision() < precision || end.Precision() < precision {
+		return nil, nil
+	}
+
+	t1 := civilTime(start.Time(), start.Time().Location())
+	t2 := civilTime(end.Time(), start.Time().Location())
+
+	var diff int64
+	switch unit {
+	case hipathsys.YearQuantityUnit:
+		if f.whole {
+			diff = monthsBetween(t1, t2, true) / 12
+		} else {
+			diff = int64(t2.Year() - t1.Year())
+		}
+	case hipathsys.MonthQuantityUnit:
+		diff = monthsBetween(t1, t2, f.whole)
+	default:
+		d := calendarUnitDuration(unit)
+		if !f.whole {
+			t1, t2 = t1.Truncate(d), t2.Truncate(d)
+		}
+		diff = int64(t2.Sub(t1) / d)
+	}
+
+	if diff < math.MinInt32 || diff > math.MaxInt32 {
+		return hipathsys.NewLong(diff), nil
+	}
+	return hipathsys.NewInteger(int32(diff)), nil
+}
+
+func dateTemporalNode(node interface{}) (hipathsys.DateTemporalAccessor, error) {
+	value, err := unwrapSingleton(node)
+	if value == nil || err != nil {
+		return nil, err
+	}
+
+	if d, ok := value.(hipathsys.DateTemporalAccessor); !ok {
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "not a date or date/time: %T", value)
+	} else {
+		return d, nil
+	}
+}
+
+func calendarDurationUnit(name string) (hipathsys.QuantityUnitAccessor, hipathsys.DateTimePrecisions, error) {
+	switch hipathsys.QuantityUnitByName(name) {
+	case hipathsys.YearQuantityUnit, hipathsys.UCUMYearQuantityUnit:
+		return hipathsys.YearQuantityUnit, hipathsys.YearDatePrecision, nil
+	case hipathsys.MonthQuantityUnit, hipathsys.UCUMMonthQuantityUnit:
+		return hipathsys.MonthQuantityUnit, hipathsys.MonthDatePrecision, nil
+	case hipathsys.WeekQuantityUnit, hipathsys.UCUMWeekQuantityUnit:
+		return hipathsys.WeekQuantityUnit, hipathsys.DayDatePrecision, nil
+	case hipathsys.DayQuantityUnit, hipathsys.UCUMDayQuantityUnit:
+		return hipathsys.DayQuantityUnit, hipathsys.DayDatePrecision, nil
+	case hipathsys.HourQuantityUnit, hipathsys.UCUMHourQuantityUnit:
+		return hipathsys.HourQuantityUnit, hipathsys.HourTimePrecision, nil
+	case hipathsys.MinuteQuantityUnit, hipathsys.UCUMMinuteQuantityUnit:
+		return hipathsys.MinuteQuantityUnit, hipathsys.MinuteTimePrecision, nil
+	case hipathsys.SecondQuantityUnit:
+		return hipathsys.SecondQuantityUnit, hipathsys.SecondTimePrecision, nil
+	case hipathsys.MillisecondQuantityUnit:
+		return hipathsys.MillisecondQuantityUnit, hipathsys.NanoTimePrecision, nil
+	}
+	return nil, 0, fmt.Errorf("not a calendar duration unit: %s", name)
+}
+
+func calendarUnitDuration(unit hipathsys.QuantityUnitAccessor) time.Duration {
+	switch unit {
+	case hipathsys.WeekQuantityUnit:
+		return 7 * 24 * time.Hour
+	case hipathsys.DayQuantityUnit:
+		return 24 * time.Hour
+	case hipathsys.HourQuantityUnit:
+		return time.Hour
+	case hipathsys.MinuteQuantityUnit:
+		return time.Minute
+	case hipathsys.SecondQuantityUnit:
+		return time.Second
+	}
+	return time.Millisecond
+}
+
+func civilTime(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+func monthsBetween(t1 time.Time, t2 time.Time, whole bool) int64 {
+	months := int64(t2.Year()-t1.Year())*12 + int64(t2.Month()-t1.Month())
+	if whole {
+		if months > 0 && addMonthsClamped(t1, months).After(t2) {
+			months--
+		} else if months < 0 && addMonthsClamped(t1, months).Before(t2) {
+			months++
+		}
+	}
+	return months
+}
+
+// addMonthsClamped adds the specified months to the time. If the day of the month
+// does not exist in the resulting month, the last day of that month is used
+// (e.g. January 31 plus one month is the last day of February).
+func addMonthsClamped(t time.Time, months int64) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location()).
+		AddDate(0, int(months), 0)
+	day := t.Day()
+	if lastDay := first.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
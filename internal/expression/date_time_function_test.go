@@ -0,0 +1,295 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDateTimeComponentFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newYearOfFunction().Execute(ctx, nil, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestDateTimeComponentFuncDate(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	d := hipathsys.NewDateYMDWithPrecision(2014, 3, 12, hipathsys.DayDatePrecision)
+
+	res, err := newYearOfFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(2014), res)
+	res, err = newMonthOfFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(3), res)
+	res, err = newDayOfFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(12), res)
+}
+
+func TestDateTimeComponentFuncDateHour(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	d := hipathsys.NewDateYMDWithPrecision(2014, 3, 12, hipathsys.DayDatePrecision)
+
+	res, err := newHourOfFunction().Execute(ctx, d, nil, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestDateTimeComponentFuncPrecision(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	d := hipathsys.NewDateYMDWithPrecision(2014, 1, 1, hipathsys.YearDatePrecision)
+
+	res, err := newMonthOfFunction().Execute(ctx, d, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestDateTimeComponentFuncDateTime(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	dt := hipathsys.NewDateTimeYMDHMSNWithPrecision(2014, 3, 12, 10, 20, 30, 123456789,
+		time.UTC, hipathsys.NanoTimePrecision)
+
+	res, err := newDayOfFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(12), res)
+	res, err = newHourOfFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(10), res)
+	res, err = newMinuteOfFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(20), res)
+	res, err = newSecondOfFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(30), res)
+	res, err = newMillisecondOfFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(123), res)
+}
+
+func TestDateTimeComponentFuncTime(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	tm := hipathsys.NewTimeHMSNWithPrecision(10, 20, 0, 0, hipathsys.MinuteTimePrecision)
+
+	res, err := newMinuteOfFunction().Execute(ctx, tm, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(20), res)
+	res, err = newSecondOfFunction().Execute(ctx, tm, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+	res, err = newYearOfFunction().Execute(ctx, tm, nil, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestTimezoneOffsetOfFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	dt := hipathsys.NewDateTimeYMDHMSNWithPrecision(2014, 3, 12, 10, 20, 0, 0,
+		time.FixedZone("", -(5*60+30)*60), hipathsys.MinuteTimePrecision)
+
+	res, err := newTimezoneOffsetOfFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, -5.5, res.(hipathsys.DecimalAccessor).Float64())
+	}
+}
+
+func TestTimezoneOffsetOfFuncPrecision(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	dt := hipathsys.NewDateTimeYMDHMSNWithPrecision(2014, 3, 12, 0, 0, 0, 0,
+		time.UTC, hipathsys.DayDatePrecision)
+
+	res, err := newTimezoneOffsetOfFunction().Execute(ctx, dt, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestTimezoneOffsetOfFuncNoOffset(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	dt, err := hipathsys.ParseDateTime("2014-01-05T10:30:00")
+	if assert.NoError(t, err, "no error expected") {
+		res, err := newTimezoneOffsetOfFunction().Execute(ctx, dt, nil, nil)
+		assert.NoError(t, err, "no error expected")
+		assert.Nil(t, res, "empty result expected")
+	}
+}
+
+func TestTimezoneOffsetOfFuncDate(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newTimezoneOffsetOfFunction().Execute(ctx, hipathsys.NewDateYMD(2014, 3, 12), nil, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestDurationFuncYears(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newDurationFunction()
+
+	res, err := f.Execute(ctx, hipathsys.NewDateYMD(1974, 12, 25),
+		[]interface{}{hipathsys.NewDateYMD(2020, 12, 24), hipathsys.NewString("years")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(45), res)
+
+	res, err = f.Execute(ctx, hipathsys.NewDateYMD(1974, 12, 25),
+		[]interface{}{hipathsys.NewDateYMD(2020, 12, 25), hipathsys.NewString("a")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(46), res)
+
+	res, err = f.Execute(ctx, hipathsys.NewDateYMD(2020, 12, 25),
+		[]interface{}{hipathsys.NewDateYMD(1974, 12, 26), hipathsys.NewString("year")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(-45), res)
+}
+
+func TestDifferenceFuncYears(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newDifferenceFunction()
+
+	res, err := f.Execute(ctx, hipathsys.NewDateYMD(2020, 12, 31),
+		[]interface{}{hipathsys.NewDateYMD(2021, 1, 1), hipathsys.NewString("years")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(1), res)
+}
+
+func TestDurationFuncMonths(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newDurationFunction().Execute(ctx, hipathsys.NewDateYMD(2020, 1, 31),
+		[]interface{}{hipathsys.NewDateYMD(2020, 2, 29), hipathsys.NewString("months")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(1), res)
+
+	res, err = newDifferenceFunction().Execute(ctx, hipathsys.NewDateYMD(2020, 1, 31),
+		[]interface{}{hipathsys.NewDateYMD(2020, 2, 29), hipathsys.NewString("months")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(1), res)
+}
+
+func TestDurationFuncMonthsEndOfMonth(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newDurationFunction()
+
+	res, err := f.Execute(ctx, hipathsys.NewDateYMD(2020, 3, 31),
+		[]interface{}{hipathsys.NewDateYMD(2020, 4, 30), hipathsys.NewString("months")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(1), res)
+
+	res, err = f.Execute(ctx, hipathsys.NewDateYMD(2020, 3, 31),
+		[]interface{}{hipathsys.NewDateYMD(2020, 4, 29), hipathsys.NewString("months")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(0), res)
+
+	res, err = f.Execute(ctx, hipathsys.NewDateYMD(2020, 3, 31),
+		[]interface{}{hipathsys.NewDateYMD(2020, 2, 29), hipathsys.NewString("month")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(-1), res)
+
+	res, err = f.Execute(ctx, hipathsys.NewDateYMD(2020, 3, 31),
+		[]interface{}{hipathsys.NewDateYMD(2020, 3, 1), hipathsys.NewString("month")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(0), res)
+
+	res, err = f.Execute(ctx, hipathsys.NewDateYMD(2020, 2, 29),
+		[]interface{}{hipathsys.NewDateYMD(2021, 2, 28), hipathsys.NewString("years")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(1), res)
+}
+
+func TestDurationFuncDays(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	start := hipathsys.NewDateTimeYMDHMSNWithPrecision(2020, 3, 1, 22, 0, 0, 0, time.UTC, hipathsys.MinuteTimePrecision)
+	end := hipathsys.NewDateTimeYMDHMSNWithPrecision(2020, 3, 4, 8, 0, 0, 0, time.UTC, hipathsys.MinuteTimePrecision)
+
+	res, err := newDurationFunction().Execute(ctx, start, []interface{}{end, hipathsys.NewString("days")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(2), res)
+
+	res, err = newDifferenceFunction().Execute(ctx, start, []interface{}{end, hipathsys.NewString("d")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(3), res)
+
+	res, err = newDurationFunction().Execute(ctx, start, []interface{}{end, hipathsys.NewString("hours")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(58), res)
+}
+
+func TestDurationFuncMilliseconds(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	start := hipathsys.NewDateTimeYMDHMSNWithPrecision(2020, 1, 1, 0, 0, 0, 0, time.UTC, hipathsys.NanoTimePrecision)
+	end := hipathsys.NewDateTimeYMDHMSNWithPrecision(2021, 1, 1, 0, 0, 0, 0, time.UTC, hipathsys.NanoTimePrecision)
+
+	res, err := newDurationFunction().Execute(ctx, start, []interface{}{end, hipathsys.NewString("ms")}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.LongAccessor)(nil), res) {
+		assert.Equal(t, int64(366*24*60*60*1000), res.(hipathsys.LongAccessor).Int64())
+	}
+}
+
+func TestDurationFuncPrecision(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newDurationFunction().Execute(ctx, hipathsys.NewDateYMDWithPrecision(2020, 1, 1, hipathsys.YearDatePrecision),
+		[]interface{}{hipathsys.NewDateYMD(2021, 3, 1), hipathsys.NewString("months")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestDurationFuncInvalidUnit(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newDurationFunction().Execute(ctx, hipathsys.NewDateYMD(2020, 1, 1),
+		[]interface{}{hipathsys.NewDateYMD(2021, 3, 1), hipathsys.NewString("kg")}, nil)
+	assert.EqualError(t, err, "not a calendar duration unit: kg")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestDurationFuncEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newDurationFunction().Execute(ctx, hipathsys.NewDateYMD(2020, 1, 1),
+		[]interface{}{nil, hipathsys.NewString("days")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestDurationFuncInvalidType(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	res, err := newDurationFunction().Execute(ctx, hipathsys.NewString("2020"),
+		[]interface{}{hipathsys.NewDateYMD(2021, 3, 1), hipathsys.NewString("days")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
@@ -87,7 +87,7 @@ func (e *EqualityExpression) evaluateInternally(ctx hipathsys.ContextAccessor, n
 		r = hipathsys.ModelEquivalent(ctx.ModelAdapter(), left, right)
 	} else {
 		r = hipathsys.ModelEqual(ctx.ModelAdapter(), left, right)
-		if !r && temporalPrecisionNotEqual(left, right) {
+		if !r && (temporalPrecisionNotEqual(left, right) || quantityUnitsNotComparable(left, right)) {
 			return nil, nil
 		}
 	}
@@ -128,3 +128,15 @@ func temporalPrecisionNotEqual(n1 interface{}, n2 interface{}) bool {
 	}
 	return !hipathsys.TemporalPrecisionEqual(t1, t2)
 }
+
+func quantityUnitsNotComparable(n1 interface{}, n2 interface{}) bool {
+	var ok bool
+	var q1, q2 hipathsys.QuantityAccessor
+	if q1, ok = n1.(hipathsys.QuantityAccessor); !ok {
+		return false
+	}
+	if q2, ok = n2.(hipathsys.QuantityAccessor); !ok {
+		return false
+	}
+	return !hipathsys.QuantityUnitsComparable(q1, q2)
+}
@@ -279,6 +279,22 @@ func TestEqualityExpressionEqualTimePrecisionDiffers(t *testing.T) {
 	assert.Nil(t, res, "empty collection expected")
 }
 
+func TestEqualityExpressionEqualCalendarYearUCUM(t *testing.T) {
+	n1, err := ParseQuantityLiteral("1", "year")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n2, err := ParseQuantityLiteral("1", "'a'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := test.NewTestContext(t)
+	e := NewEqualityExpression(false, false, n1, n2)
+	res, err := e.Evaluate(ctx, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
 func TestEqualityExpressionEqualTimeNanoSecondPrecision(t *testing.T) {
 	n1, err := ParseTimeLiteral("@T12:20:22.0")
 	if err != nil {
@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -94,7 +93,7 @@ func (f *existsFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}
 			}
 			if res != nil {
 				if b, ok := res.(hipathsys.BooleanAccessor); !ok {
-					return nil, fmt.Errorf("filter expression must return boolean, but returned %T", res)
+					return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "filter expression must return boolean, but returned %T", res)
 				} else if b.Bool() {
 					return hipathsys.True, nil
 				}
@@ -128,7 +127,7 @@ func (f *allFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _
 			return nil, err
 		}
 		if b, ok := res.(hipathsys.BooleanAccessor); !ok {
-			return nil, fmt.Errorf("parameter expression must return boolean, but returned %T", res)
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "parameter expression must return boolean, but returned %T", res)
 		} else if !b.Bool() {
 			return hipathsys.False, nil
 		}
@@ -173,7 +172,7 @@ func (f *allAnyTrueFalseFunction) Execute(ctx hipathsys.ContextAccessor, node in
 	for i := 0; i < count; i++ {
 		this := col.Get(i)
 		if b, ok := this.(hipathsys.BooleanAccessor); !ok {
-			return nil, fmt.Errorf("collection must contain only boolean values, but contains %T", this)
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "collection must contain only boolean values, but contains %T", this)
 		} else if f.all && f.t != b.Bool() {
 			return hipathsys.False, nil
 		} else if !f.all && f.t == b.Bool() {
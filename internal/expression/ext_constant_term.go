@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -44,7 +43,7 @@ func ParseExtConstantTerm(value string) *ExtConstantTerm {
 func (e *ExtConstantTerm) Evaluate(ctx hipathsys.ContextAccessor, _ interface{}, _ hipathsys.Looper) (interface{}, error) {
 	res, found := ctx.EnvVar(e.name)
 	if !found {
-		return nil, fmt.Errorf("Environment variable has not been defined: %s", e.name)
+		return nil, hipathsys.NewKindErrorf(hipathsys.UnknownEnvVarErrorKind, "Environment variable has not been defined: %s", e.name)
 	}
 	return res, nil
 }
@@ -47,6 +47,6 @@ func TestExtConstantTermNotDefined(t *testing.T) {
 	ctx := test.NewTestContext(t)
 	evaluator := ParseExtConstantTerm("xxx")
 	res, err := evaluator.Evaluate(ctx, nil, nil)
-	assert.Error(t, err, "error expected")
+	assert.ErrorIs(t, err, hipathsys.ErrUnknownEnvVar)
 	assert.Nil(t, res, "no res expected due to error")
 }
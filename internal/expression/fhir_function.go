@@ -0,0 +1,189 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+)
+
+type resolveFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newResolveFunction() *resolveFunction {
+	return &resolveFunction{
+		BaseFunction: hipathsys.NewBaseFunction("resolve", -1, 0, 0),
+	}
+}
+
+func (f *resolveFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	if emptyCollection(node) {
+		return nil, nil
+	}
+
+	col := wrapCollection(ctx, node)
+	count := col.Count()
+	var res hipathsys.ColModifier
+	for i := 0; i < count; i++ {
+		if err := hipathsys.CheckCanceled(ctx); err != nil {
+			return nil, err
+		}
+
+		item := col.Get(i)
+		reference, err := referenceString(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+
+		resource, err := hipathsys.ResolveReference(ctx, item, reference)
+		if err != nil {
+			return nil, hipathsys.NewAdapterError(err)
+		}
+		if resource != nil {
+			if res == nil {
+				res = ctx.NewCol()
+			}
+			if c, ok := resource.(hipathsys.ColAccessor); ok {
+				res.AddAll(c)
+			} else {
+				res.Add(resource)
+			}
+		}
+	}
+	return res, nil
+}
+
+type extensionFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newExtensionFunction() *extensionFunction {
+	return &extensionFunction{
+		BaseFunction: hipathsys.NewBaseFunction("extension", -1, 1, 1),
+	}
+}
+
+func (f *extensionFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	url, err := stringNode(args[0])
+	if url == nil || err != nil || emptyCollection(node) {
+		return nil, err
+	}
+
+	adapter := ctx.ModelAdapter()
+	col := wrapCollection(ctx, node)
+	count := col.Count()
+	var res hipathsys.ColModifier
+	for i := 0; i < count; i++ {
+		item := col.Get(i)
+		if item == nil {
+			continue
+		}
+
+		extensions, err := hipathsys.ModelExtensions(adapter, item)
+		if err != nil {
+			return nil, hipathsys.NewAdapterError(err)
+		}
+		if extensions == nil {
+			continue
+		}
+
+		extCount := extensions.Count()
+		for j := 0; j < extCount; j++ {
+			extension := extensions.Get(j)
+			extURL, err := hipathsys.ModelString(ctx.ModelAdapter(), extension, "url")
+			if err != nil {
+				return nil, hipathsys.NewAdapterError(err)
+			}
+			if extURL == url.String() {
+				if res == nil {
+					res = ctx.NewCol()
+				}
+				res.Add(extension)
+			}
+		}
+	}
+	return res, nil
+}
+
+type hasValueFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newHasValueFunction() *hasValueFunction {
+	return &hasValueFunction{
+		BaseFunction: hipathsys.NewBaseFunction("hasValue", -1, 0, 0),
+	}
+}
+
+func (f *hasValueFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	_, found := primitiveValue(ctx, node)
+	return hipathsys.BooleanOf(found), nil
+}
+
+type getValueFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newGetValueFunction() *getValueFunction {
+	return &getValueFunction{
+		BaseFunction: hipathsys.NewBaseFunction("getValue", -1, 0, 0),
+	}
+}
+
+func (f *getValueFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	if value, found := primitiveValue(ctx, node); found {
+		return value, nil
+	}
+	return nil, nil
+}
+
+func primitiveValue(ctx hipathsys.ContextAccessor, node interface{}) (hipathsys.AnyAccessor, bool) {
+	node = unwrapCollection(node)
+	if node == nil {
+		return nil, false
+	}
+	if _, ok := node.(hipathsys.ColAccessor); ok {
+		return nil, false
+	}
+	return hipathsys.ModelPrimitiveValue(ctx.ModelAdapter(), node)
+}
+
+func referenceString(ctx hipathsys.ContextAccessor, node interface{}) (string, error) {
+	if node == nil {
+		return "", nil
+	}
+	if s, ok := node.(hipathsys.StringAccessor); ok {
+		return s.String(), nil
+	}
+	reference, err := hipathsys.ModelString(ctx.ModelAdapter(), node, "reference")
+	if err != nil {
+		return "", hipathsys.NewAdapterError(err)
+	}
+	return reference, nil
+}
@@ -0,0 +1,269 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testResolverContext struct {
+	hipathsys.ContextAccessor
+	resolver hipathsys.ReferenceResolver
+}
+
+func newTestResolverContext(t *testing.T, resources map[string]interface{}) hipathsys.ContextAccessor {
+	return hipathsys.NewReferenceCacheContext(&testResolverContext{
+		ContextAccessor: test.NewTestContext(t),
+		resolver:        hipathsys.NewMapReferenceResolver(resources),
+	})
+}
+
+func (c *testResolverContext) Delegate() hipathsys.ContextAccessor {
+	return c.ContextAccessor
+}
+
+func (c *testResolverContext) ReferenceResolver() hipathsys.ReferenceResolver {
+	return c.resolver
+}
+
+func TestResolveFuncEmpty(t *testing.T) {
+	ctx := newTestResolverContext(t, nil)
+
+	f := newResolveFunction()
+	res, err := f.Execute(ctx, hipathsys.EmptyCol, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestResolveFuncString(t *testing.T) {
+	patient := map[string]interface{}{"id": hipathsys.NewString("1")}
+	ctx := newTestResolverContext(t, map[string]interface{}{"Patient/1": patient})
+
+	f := newResolveFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("Patient/1"), []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		col := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 1, col.Count()) {
+			assert.Equal(t, patient, col.Get(0))
+		}
+	}
+}
+
+func TestResolveFuncReference(t *testing.T) {
+	patient := map[string]interface{}{"id": hipathsys.NewString("1")}
+	practitioner := map[string]interface{}{"id": hipathsys.NewString("2")}
+	ctx := newTestResolverContext(t, map[string]interface{}{
+		"Patient/1":      patient,
+		"Practitioner/2": practitioner,
+	})
+
+	col := ctx.NewCol()
+	col.Add(map[string]interface{}{"reference": hipathsys.NewString("Patient/1")})
+	col.Add(map[string]interface{}{"reference": hipathsys.NewString("Patient/9")})
+	col.Add(map[string]interface{}{"reference": hipathsys.EmptyCol})
+	col.Add(map[string]interface{}{"reference": hipathsys.NewString("Practitioner/2")})
+
+	f := newResolveFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		col := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 2, col.Count()) {
+			assert.Equal(t, patient, col.Get(0))
+			assert.Equal(t, practitioner, col.Get(1))
+		}
+	}
+}
+
+func TestResolveFuncNotFound(t *testing.T) {
+	ctx := newTestResolverContext(t, nil)
+
+	f := newResolveFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("Patient/1"), []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestResolveFuncNavigateError(t *testing.T) {
+	ctx := newTestResolverContext(t, nil)
+
+	f := newResolveFunction()
+	res, err := f.Execute(ctx, map[string]interface{}{}, []interface{}{}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestResolveFuncCanceled(t *testing.T) {
+	ctx := test.NewCanceledTestContext(t)
+
+	f := newResolveFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("Patient/1"), []interface{}{}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func testExtension(url string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"url": hipathsys.NewString(url), "value": value}
+}
+
+func TestExtensionFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	ext1 := testExtension("http://example.org/a", hipathsys.NewString("a1"))
+	ext2 := testExtension("http://example.org/b", hipathsys.NewString("b"))
+	ext3 := testExtension("http://example.org/a", hipathsys.NewString("a2"))
+
+	extensions := ctx.NewCol()
+	extensions.Add(ext1)
+	extensions.Add(ext2)
+
+	col := ctx.NewCol()
+	col.Add(map[string]interface{}{"extension": extensions})
+	col.Add(map[string]interface{}{"extension": ext3})
+	col.Add(map[string]interface{}{"extension": nil})
+
+	f := newExtensionFunction()
+	res, err := f.Execute(ctx, col, []interface{}{hipathsys.NewString("http://example.org/a")}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		col := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 2, col.Count()) {
+			assert.Equal(t, ext1, col.Get(0))
+			assert.Equal(t, ext3, col.Get(1))
+		}
+	}
+}
+
+func TestExtensionFuncNotFound(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	node := map[string]interface{}{"extension": testExtension("http://example.org/b", hipathsys.NewString("b"))}
+
+	f := newExtensionFunction()
+	res, err := f.Execute(ctx, node, []interface{}{hipathsys.NewString("http://example.org/a")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestExtensionFuncEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newExtensionFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString("http://example.org/a")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestExtensionFuncEmptyURL(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	node := map[string]interface{}{"extension": testExtension("http://example.org/a", hipathsys.NewString("a"))}
+
+	f := newExtensionFunction()
+	res, err := f.Execute(ctx, node, []interface{}{hipathsys.EmptyCol}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestExtensionFuncInvalidURL(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	node := map[string]interface{}{"extension": testExtension("http://example.org/a", hipathsys.NewString("a"))}
+
+	f := newExtensionFunction()
+	res, err := f.Execute(ctx, node, []interface{}{hipathsys.NewInteger(10)}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestExtensionFuncNavigateError(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newExtensionFunction()
+	res, err := f.Execute(ctx, map[string]interface{}{}, []interface{}{hipathsys.NewString("http://example.org/a")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestHasValueFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newHasValueFunction()
+	res, err := f.Execute(ctx, ctx.NewColWithItem(hipathsys.NewString("test")), []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+}
+
+func TestHasValueFuncNoPrimitive(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newHasValueFunction()
+	res, err := f.Execute(ctx, map[string]interface{}{}, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
+func TestHasValueFuncEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newHasValueFunction()
+	res, err := f.Execute(ctx, hipathsys.EmptyCol, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
+func TestHasValueFuncMultiple(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("test1"))
+	col.Add(hipathsys.NewString("test2"))
+
+	f := newHasValueFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
+func TestGetValueFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newGetValueFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"), []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("test"), res)
+}
+
+func TestGetValueFuncNoPrimitive(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newGetValueFunction()
+	res, err := f.Execute(ctx, map[string]interface{}{}, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
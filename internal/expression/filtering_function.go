@@ -53,6 +53,10 @@ func (f *whereFunction) Execute(ctx hipathsys.ContextAccessor, node interface{},
 	var filtered hipathsys.ColModifier
 	loopEvaluator := loop.Evaluator()
 	for i := 0; i < count; i++ {
+		if err := hipathsys.CheckCanceled(ctx); err != nil {
+			return nil, err
+		}
+
 		this := col.Get(i)
 		loop.IncIndex(this)
 
@@ -62,7 +66,7 @@ func (f *whereFunction) Execute(ctx hipathsys.ContextAccessor, node interface{},
 		}
 		if res != nil {
 			if b, ok := res.(hipathsys.BooleanAccessor); !ok {
-				return nil, fmt.Errorf("filter expression must return boolean, but returned %T", res)
+				return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "filter expression must return boolean, but returned %T", res)
 			} else if b.Bool() {
 				if filtered == nil {
 					filtered = ctx.NewCol()
@@ -95,6 +99,10 @@ func (f *selectFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}
 	var projected hipathsys.ColModifier
 	loopEvaluator := loop.Evaluator()
 	for i := 0; i < count; i++ {
+		if err := hipathsys.CheckCanceled(ctx); err != nil {
+			return nil, err
+		}
+
 		this := col.Get(i)
 		loop.IncIndex(this)
 
@@ -112,6 +120,9 @@ func (f *selectFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}
 			} else {
 				projected.Add(res)
 			}
+			if err := hipathsys.CheckCollectionSize(ctx, projected.Count()); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -128,7 +139,7 @@ var repeatFunc = &repeatFunction{
 
 func (f *repeatFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, loop hipathsys.Looper) (interface{}, error) {
 	projected := ctx.NewCol()
-	err := repeat(ctx, node, loop, projected)
+	err := repeat(ctx, node, loop, projected, &repeatState{})
 
 	if err != nil || projected.Empty() {
 		projected = nil
@@ -137,15 +148,34 @@ func (f *repeatFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}
 	return projected, err
 }
 
-func repeat(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper, projected hipathsys.ColModifier) error {
+type repeatState struct {
+	depth      int
+	iterations int
+}
+
+func repeat(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper, projected hipathsys.ColModifier, state *repeatState) error {
 	col := wrapCollection(ctx, node)
 	count := col.Count()
 	if count == 0 {
 		return nil
 	}
 
+	state.depth++
+	defer func() { state.depth-- }()
+	if err := hipathsys.CheckRepeatDepth(ctx, state.depth); err != nil {
+		return err
+	}
+
 	loopEvaluator := loop.Evaluator()
 	for i := 0; i < count; i++ {
+		if err := hipathsys.CheckCanceled(ctx); err != nil {
+			return err
+		}
+		state.iterations++
+		if err := hipathsys.CheckRepeatIterations(ctx, state.iterations); err != nil {
+			return err
+		}
+
 		this := col.Get(i)
 		loop.IncIndex(this)
 
@@ -154,7 +184,7 @@ func repeat(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Loop
 			return err
 		}
 		if res != nil {
-			err := repeatRecursively(ctx, res, loop, projected)
+			err := repeatRecursively(ctx, res, loop, projected, state)
 			if err != nil {
 				return err
 			}
@@ -164,7 +194,7 @@ func repeat(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Loop
 	return nil
 }
 
-func repeatRecursively(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper, projected hipathsys.ColModifier) error {
+func repeatRecursively(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper, projected hipathsys.ColModifier, state *repeatState) error {
 	if col, ok := node.(hipathsys.ColAccessor); ok {
 		count := col.Count()
 		for i := 0; i < count; i++ {
@@ -172,8 +202,11 @@ func repeatRecursively(ctx hipathsys.ContextAccessor, node interface{}, loop hip
 			if n != nil {
 				added := projected.AddUnique(n)
 				if added {
+					if err := hipathsys.CheckCollectionSize(ctx, projected.Count()); err != nil {
+						return err
+					}
 					err := repeat(ctx, n, hipathsys.NewLoopWithIndex(
-						loop.Evaluator(), i), projected)
+						loop.Evaluator(), i), projected, state)
 					if err != nil {
 						return err
 					}
@@ -181,8 +214,11 @@ func repeatRecursively(ctx hipathsys.ContextAccessor, node interface{}, loop hip
 			}
 		}
 	} else if projected.AddUnique(node) {
+		if err := hipathsys.CheckCollectionSize(ctx, projected.Count()); err != nil {
+			return err
+		}
 		err := repeat(ctx, node, hipathsys.NewLoop(
-			loop.Evaluator()), projected)
+			loop.Evaluator()), projected, state)
 		if err != nil {
 			return err
 		}
@@ -204,7 +240,7 @@ func (f *ofTypeFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}
 	var typeSpec hipathsys.StringAccessor
 	var ok bool
 	if typeSpec, ok = unwrapCollection(args[0]).(hipathsys.StringAccessor); !ok {
-		return nil, fmt.Errorf("not a valid type specifier: %T", args[0])
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "not a valid type specifier: %T", args[0])
 	}
 
 	var typeName hipathsys.FQTypeNameAccessor
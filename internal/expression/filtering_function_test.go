@@ -29,6 +29,7 @@
 package expression
 
 import (
+	"context"
 	"github.com/healthiop/hipath/hipathsys"
 	"github.com/healthiop/hipath/internal/test"
 	"github.com/stretchr/testify/assert"
@@ -385,3 +386,108 @@ func TestOfTypePathFuncBaseType(t *testing.T) {
 		}
 	}
 }
+
+func TestWherePathFuncCanceled(t *testing.T) {
+	ctx := test.NewCanceledTestContext(t)
+	col := ctx.NewColWithItem(hipathsys.NewInteger(0))
+
+	f := newWhereFunction()
+	res, err := f.Execute(ctx, col, nil, hipathsys.NewLoop(NewThisInvocation()))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestSelectPathFuncCanceled(t *testing.T) {
+	ctx := test.NewCanceledTestContext(t)
+	col := ctx.NewColWithItem(hipathsys.NewInteger(0))
+
+	f := newSelectFunction()
+	res, err := f.Execute(ctx, col, nil, hipathsys.NewLoop(NewThisInvocation()))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestRepeatPathFuncCanceled(t *testing.T) {
+	ctx := test.NewCanceledTestContext(t)
+	col := ctx.NewColWithItem(hipathsys.NewInteger(0))
+
+	res, err := repeatFunc.Execute(ctx, col, nil, hipathsys.NewLoop(NewThisInvocation()))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestSelectPathFuncCollectionSizeLimit(t *testing.T) {
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{MaxCollectionSize: 2})
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(1))
+	col.Add(hipathsys.NewInteger(2))
+	col.Add(hipathsys.NewInteger(3))
+
+	f := newSelectFunction()
+	res, err := f.Execute(ctx, col, nil, hipathsys.NewLoop(NewThisInvocation()))
+	assert.EqualError(t, err, "evaluation limit exceeded: maximum collection size of 2")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestRepeatPathFuncDepthLimit(t *testing.T) {
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{MaxRepeatDepth: 2})
+
+	node111 := make(map[string]interface{})
+	node111["id"] = "111"
+	node111["item"] = nil
+
+	node11 := make(map[string]interface{})
+	node11["id"] = "11"
+	node11["item"] = node111
+
+	node1 := make(map[string]interface{})
+	node1["id"] = "1"
+	node1["item"] = node11
+
+	res, err := repeatFunc.Execute(ctx, node1, nil, hipathsys.NewLoop(NewMemberInvocation("item")))
+	assert.EqualError(t, err, "evaluation limit exceeded: maximum repeat depth of 2")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestRepeatPathFuncIterationsLimit(t *testing.T) {
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{MaxRepeatIterations: 2})
+
+	node111 := make(map[string]interface{})
+	node111["id"] = "111"
+	node111["item"] = nil
+
+	node11 := make(map[string]interface{})
+	node11["id"] = "11"
+	node11["item"] = node111
+
+	node1 := make(map[string]interface{})
+	node1["id"] = "1"
+	node1["item"] = node11
+
+	res, err := repeatFunc.Execute(ctx, node1, nil, hipathsys.NewLoop(NewMemberInvocation("item")))
+	assert.EqualError(t, err, "evaluation limit exceeded: maximum repeat iterations of 2")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestRepeatPathFuncWithinLimits(t *testing.T) {
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{
+		MaxRepeatDepth: 3, MaxRepeatIterations: 3, MaxCollectionSize: 2})
+
+	node111 := make(map[string]interface{})
+	node111["id"] = "111"
+	node111["item"] = nil
+
+	node11 := make(map[string]interface{})
+	node11["id"] = "11"
+	node11["item"] = node111
+
+	node1 := make(map[string]interface{})
+	node1["id"] = "1"
+	node1["item"] = node11
+
+	res, err := repeatFunc.Execute(ctx, node1, nil, hipathsys.NewLoop(NewMemberInvocation("item")))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		assert.Equal(t, 2, res.(hipathsys.ColAccessor).Count())
+	}
+}
@@ -31,6 +31,8 @@ package expression
 import (
 	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
+	"sync"
+	"unicode/utf8"
 )
 
 var functions = []hipathsys.FunctionExecutor{
@@ -64,12 +66,16 @@ var functions = []hipathsys.FunctionExecutor{
 	// combining
 	newUnionFunction(),
 	newCombineFunction(),
+	// sorting
+	newSortFunction(),
 	// conversion
 	newIIfFunction(),
 	toBooleanFunc,
 	newConvertsToBooleanFunction(),
 	toIntegerFunc,
 	newConvertsToIntegerFunction(),
+	toLongFunc,
+	newConvertsToLongFunction(),
 	toDateFunc,
 	newConvertsToDateFunction(),
 	toDateTimeFunc,
@@ -84,6 +90,7 @@ var functions = []hipathsys.FunctionExecutor{
 	newConvertsToTimeFunction(),
 	// string manipulation
 	newIndexOfFunction(),
+	newLastIndexOfFunction(),
 	newSubstringFunction(),
 	newStartsWithFunction(),
 	newEndsWithFunction(),
@@ -92,9 +99,17 @@ var functions = []hipathsys.FunctionExecutor{
 	newLowerFunction(),
 	newReplaceFunction(),
 	newMatchesFunction(),
+	newMatchesFullFunction(),
 	newReplaceMatchesFunction(),
 	newLengthFunction(),
 	newToCharsFunction(),
+	newTrimFunction(),
+	newSplitFunction(),
+	newJoinFunction(),
+	newEncodeFunction(),
+	newDecodeFunction(),
+	newEscapeFunction(),
+	newUnescapeFunction(),
 	// math
 	newAbsFunction(),
 	newCeilingFunction(),
@@ -110,26 +125,118 @@ var functions = []hipathsys.FunctionExecutor{
 	childrenFunc,
 	newDescendantsFunction(),
 	// utility
+	newDefineVariableFunction(),
 	newTraceFunction(),
 	newNowFunction(),
 	newTimeOfDayFunction(),
 	newTodayFunction(),
+	newPrecisionFunction(),
+	newLowBoundaryFunction(),
+	newHighBoundaryFunction(),
+	// date/time
+	newYearOfFunction(),
+	newMonthOfFunction(),
+	newDayOfFunction(),
+	newHourOfFunction(),
+	newMinuteOfFunction(),
+	newSecondOfFunction(),
+	newMillisecondOfFunction(),
+	newTimezoneOffsetOfFunction(),
+	newDurationFunction(),
+	newDifferenceFunction(),
 	// type
 	newAsFunction(),
 	newIsFunction(),
+	newTypeFunction(),
 	// aggregate
 	newAggregateFunction(),
+	newSumFunction(),
+	newMinFunction(),
+	newMaxFunction(),
+	newAvgFunction(),
+	newMedianFunction(),
+	// FHIR
+	newResolveFunction(),
+	newExtensionFunction(),
+	newHasValueFunction(),
+	newGetValueFunction(),
+	// terminology
+	newMemberOfFunction(),
+	newSubsumesFunction(),
+	newSubsumedByFunction(),
 }
 
 var functionsByName = createFunctionsByName(functions)
 
+var builtinFunctionRegistry = &FunctionRegistry{functionsByName: functionsByName}
+
+type FunctionRegistry struct {
+	parent          *FunctionRegistry
+	lock            sync.RWMutex
+	functionsByName map[string]hipathsys.FunctionExecutor
+}
+
 type FunctionInvocation struct {
 	executor        hipathsys.FunctionExecutor
 	paramEvaluators []hipathsys.Evaluator
 }
 
+func NewFunctionRegistry() *FunctionRegistry {
+	return &FunctionRegistry{
+		parent:          builtinFunctionRegistry,
+		functionsByName: make(map[string]hipathsys.FunctionExecutor),
+	}
+}
+
+func (r *FunctionRegistry) Register(executor hipathsys.FunctionExecutor) error {
+	if executor == nil {
+		return fmt.Errorf("executor must not be nil")
+	}
+	name := executor.Name()
+	if len(name) == 0 {
+		return fmt.Errorf("executor name must not be empty")
+	}
+	if executor.MinParams() < 0 || executor.MinParams() > executor.MaxParams() {
+		return fmt.Errorf("executor %s has invalid parameter bounds %d..%d",
+			name, executor.MinParams(), executor.MaxParams())
+	}
+	if executor.EvaluatorParam() >= executor.MaxParams() {
+		return fmt.Errorf("executor %s has invalid evaluator parameter %d",
+			name, executor.EvaluatorParam())
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, found := r.lookupLocked(name); found {
+		return fmt.Errorf("executor has already been defined: %s", name)
+	}
+
+	r.functionsByName[name] = executor
+	return nil
+}
+
+func (r *FunctionRegistry) Lookup(name string) (hipathsys.FunctionExecutor, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.lookupLocked(name)
+}
+
+func (r *FunctionRegistry) lookupLocked(name string) (hipathsys.FunctionExecutor, bool) {
+	if executor, found := r.functionsByName[name]; found {
+		return executor, true
+	}
+	if r.parent != nil {
+		return r.parent.Lookup(name)
+	}
+	return nil, false
+}
+
 func LookupFunctionInvocation(name string, paramEvaluators []hipathsys.Evaluator) (*FunctionInvocation, error) {
-	executor, found := functionsByName[name]
+	return builtinFunctionRegistry.LookupFunctionInvocation(name, paramEvaluators)
+}
+
+func (r *FunctionRegistry) LookupFunctionInvocation(name string, paramEvaluators []hipathsys.Evaluator) (*FunctionInvocation, error) {
+	executor, found := r.Lookup(name)
 	if !found {
 		return nil, fmt.Errorf("executor has not been defined: %s", name)
 	}
@@ -140,6 +247,18 @@ func LookupFunctionInvocation(name string, paramEvaluators []hipathsys.Evaluator
 	if len(paramEvaluators) > executor.MaxParams() {
 		return nil, fmt.Errorf("executor %s accepts at most %d parameters", name, executor.MaxParams())
 	}
+	lazyExecutor, _ := executor.(hipathsys.LazyParamsFunctionExecutor)
+	for pos, paramEvaluator := range paramEvaluators {
+		if _, ok := paramEvaluator.(*LambdaExpression); ok && pos != executor.EvaluatorParam() &&
+			(lazyExecutor == nil || !lazyExecutor.LazyParam(pos)) {
+			return nil, fmt.Errorf("executor %s does not accept a lambda expression as parameter %d", name, pos)
+		}
+	}
+	if validator, ok := executor.(paramsValidator); ok {
+		if err := validator.validateParams(paramEvaluators); err != nil {
+			return nil, err
+		}
+	}
 
 	return newFunctionInvocation(executor, paramEvaluators), nil
 }
@@ -148,23 +267,43 @@ func newFunctionInvocation(executor hipathsys.FunctionExecutor, argEvaluators []
 	return &FunctionInvocation{executor, argEvaluators}
 }
 
+type paramsValidator interface {
+	validateParams(paramEvaluators []hipathsys.Evaluator) error
+}
+
+type contextFunctionExecutor interface {
+	ExecuteContext(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error)
+}
+
 func (f *FunctionInvocation) Evaluate(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, error) {
+	res, _, err := f.evaluateContext(ctx, node, loop)
+	return res, err
+}
+
+func (f *FunctionInvocation) evaluateContext(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	if err := hipathsys.CountFunctionCall(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	var args []interface{}
+	var loopEvaluator hipathsys.Evaluator
+	evaluatorParam := f.executor.EvaluatorParam()
 	ac := len(f.paramEvaluators)
 	if ac == 0 {
 		args = nil
 	} else {
-		evaluatorParam := f.executor.EvaluatorParam()
+		lazyExecutor, _ := f.executor.(hipathsys.LazyParamsFunctionExecutor)
 		args = make([]interface{}, len(f.paramEvaluators))
 
-		var loopEvaluator hipathsys.Evaluator
 		for pos, argEvaluator := range f.paramEvaluators {
 			if evaluatorParam == pos {
 				loopEvaluator = argEvaluator
+			} else if lazyExecutor != nil && lazyExecutor.LazyParam(pos) {
+				args[pos] = hipathsys.NewLazyArg(argEvaluator, ctx, node, loop)
 			} else {
 				if argEvaluator != nil {
 					if arg, err := argEvaluator.Evaluate(ctx, node, loop); err != nil {
-						return nil, fmt.Errorf("error in argument %d of executor invocation %s: %v",
+						return nil, nil, fmt.Errorf("error in argument %d of executor invocation %s: %w",
 							pos, f.executor.Name(), err)
 					} else {
 						args[pos] = arg
@@ -172,13 +311,36 @@ func (f *FunctionInvocation) Evaluate(ctx hipathsys.ContextAccessor, node interf
 				}
 			}
 		}
+	}
+	if evaluatorParam >= 0 {
+		loop = hipathsys.NewLoop(loopEvaluator)
+	}
 
-		if evaluatorParam >= 0 {
-			loop = hipathsys.NewLoop(loopEvaluator)
-		}
+	var res interface{}
+	var err error
+	resCtx := ctx
+	if e, ok := f.executor.(contextFunctionExecutor); ok {
+		res, resCtx, err = e.ExecuteContext(ctx, node, args, loop)
+	} else {
+		res, err = f.executor.Execute(ctx, node, args, loop)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkResultLimits(ctx, res); err != nil {
+		return nil, nil, err
 	}
+	return res, resCtx, nil
+}
 
-	return f.executor.Execute(ctx, node, args, loop)
+func checkResultLimits(ctx hipathsys.ContextAccessor, res interface{}) error {
+	switch r := res.(type) {
+	case hipathsys.ColAccessor:
+		return hipathsys.CheckCollectionSize(ctx, r.Count())
+	case hipathsys.StringAccessor:
+		return hipathsys.CheckStringLength(ctx, utf8.RuneCountInString(r.String()))
+	}
+	return nil
 }
 
 func createFunctionsByName(functions []hipathsys.FunctionExecutor) map[string]hipathsys.FunctionExecutor {
@@ -105,6 +105,54 @@ func TestFunctionInvocationLoop(t *testing.T) {
 	assert.NotSame(t, testLoop, loopExpression.loop)
 }
 
+func TestFunctionInvocationLoopNoArgs(t *testing.T) {
+	function := &testInvocationLoopFunction{
+		t:            t,
+		BaseFunction: hipathsys.NewBaseFunction("test", 0, 0, 1),
+	}
+
+	ctx := test.NewTestContext(t)
+	e := newFunctionInvocation(function, []hipathsys.Evaluator{})
+
+	res, err := e.Evaluate(ctx, nil, testLoop)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "no result expected")
+	loop := function.loop
+	if assert.NotNil(t, loop, "loop expected") {
+		assert.NotSame(t, testLoop, loop)
+		assert.Nil(t, loop.Evaluator())
+	}
+}
+
+func TestFunctionInvocationLazyArgs(t *testing.T) {
+	function := &testInvocationLazyFunction{
+		BaseFunction: hipathsys.NewLazyBaseFunction("test", -1, 0, 100, 1, 2),
+	}
+
+	eagerExpression := newTestExpression(hipathsys.NewString("test1"))
+	lazyExpression := newTestExpression(hipathsys.NewString("test2"))
+	unusedExpression := newTestExpression(hipathsys.NewString("test3"))
+	ctx := test.NewTestContext(t)
+	e := newFunctionInvocation(function, []hipathsys.Evaluator{
+		eagerExpression, lazyExpression, unusedExpression})
+
+	tt := newTestingType(t)
+	res, err := e.Evaluate(ctx, tt, testLoop)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		c := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 2, c.Count()) {
+			assert.Equal(t, hipathsys.NewString("test1"), c.Get(0))
+			assert.Equal(t, hipathsys.NewString("test2"), c.Get(1))
+		}
+	}
+	assert.Equal(t, 1, eagerExpression.invocationCount)
+	assert.Equal(t, 1, lazyExpression.invocationCount)
+	assert.Same(t, tt, lazyExpression.node)
+	assert.Same(t, testLoop, lazyExpression.loop)
+	assert.Equal(t, 0, unusedExpression.invocationCount)
+}
+
 func TestFunctionInvocationArgsError(t *testing.T) {
 	function := &testInvocationArgsFunction{
 		t:            t,
@@ -170,11 +218,16 @@ func (f *testInvocationArgsFunction) Execute(ctx hipathsys.ContextAccessor, _ in
 
 type testInvocationLoopFunction struct {
 	hipathsys.BaseFunction
-	t *testing.T
+	t    *testing.T
+	loop hipathsys.Looper
 }
 
 func (f *testInvocationLoopFunction) Execute(_ hipathsys.ContextAccessor, _ interface{}, _ []interface{}, loop hipathsys.Looper) (interface{}, error) {
 	t := f.t
+	f.loop = loop
+	if loop != nil && loop.Evaluator() == nil {
+		return nil, nil
+	}
 	if assert.NotNil(t, loop) && assert.NotNil(t, loop.Evaluator()) {
 		res, err := loop.Evaluator().Evaluate(nil, nil, nil)
 		return res, err
@@ -182,6 +235,21 @@ func (f *testInvocationLoopFunction) Execute(_ hipathsys.ContextAccessor, _ inte
 	return nil, nil
 }
 
+type testInvocationLazyFunction struct {
+	hipathsys.BaseFunction
+}
+
+func (f *testInvocationLazyFunction) Execute(ctx hipathsys.ContextAccessor, _ interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	c := ctx.NewCol()
+	c.Add(args[0])
+	a, err := hipathsys.EvaluateArg(args[1])
+	if err != nil {
+		return nil, err
+	}
+	c.Add(a)
+	return c, nil
+}
+
 type testInvocationErrFunction struct {
 	hipathsys.BaseFunction
 }
@@ -189,3 +257,28 @@ type testInvocationErrFunction struct {
 func (f *testInvocationErrFunction) Execute(hipathsys.ContextAccessor, interface{}, []interface{}, hipathsys.Looper) (interface{}, error) {
 	return nil, fmt.Errorf("an error occurred")
 }
+
+func TestFunctionRegistryLookupFunctionInvocation(t *testing.T) {
+	r := NewFunctionRegistry()
+	err := r.Register(&testInvocationErrFunction{
+		BaseFunction: hipathsys.NewBaseFunction("test", -1, 0, 1),
+	})
+	assert.NoError(t, err, "no error expected")
+
+	fi, err := r.LookupFunctionInvocation("test", make([]hipathsys.Evaluator, 1))
+	assert.NoError(t, err, "no error expected")
+	assert.NotNil(t, fi, "executor invocation expected")
+
+	fi, err = r.LookupFunctionInvocation("union", make([]hipathsys.Evaluator, 1))
+	assert.NoError(t, err, "no error expected")
+	assert.NotNil(t, fi, "executor invocation expected")
+
+	fi, err = LookupFunctionInvocation("test", make([]hipathsys.Evaluator, 1))
+	assert.EqualError(t, err, "executor has not been defined: test", "error expected")
+	assert.Nil(t, fi, "no executor invocation expected")
+}
+
+func TestFunctionRegistryRegisterNil(t *testing.T) {
+	r := NewFunctionRegistry()
+	assert.EqualError(t, r.Register(nil), "executor must not be nil")
+}
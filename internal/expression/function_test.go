@@ -31,6 +31,7 @@ package expression
 import (
 	"github.com/healthiop/hipath/hipathsys"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -67,11 +68,14 @@ var functionTests = []struct {
 	{"exclude", newExcludeFunction(), -1, 1, 1},
 	{"union", newUnionFunction(), -1, 1, 1},
 	{"combine", newCombineFunction(), -1, 1, 1},
+	{"sort", newSortFunction(), -1, 0, math.MaxInt32},
 	{"iif", newIIfFunction(), -1, 2, 3},
 	{"toBoolean", toBooleanFunc, -1, 0, 0},
 	{"convertsToBoolean", newConvertsToBooleanFunction(), -1, 0, 0},
 	{"toInteger", toIntegerFunc, -1, 0, 0},
 	{"convertsToInteger", newConvertsToIntegerFunction(), -1, 0, 0},
+	{"toLong", toLongFunc, -1, 0, 0},
+	{"convertsToLong", newConvertsToLongFunction(), -1, 0, 0},
 	{"toDecimal", toDecimalFunc, -1, 0, 0},
 	{"convertsToDecimal", newConvertsToDecimalFunction(), -1, 0, 0},
 	{"toDate", toDateFunc, -1, 0, 0},
@@ -85,6 +89,7 @@ var functionTests = []struct {
 	{"toTime", toTimeFunc, -1, 0, 0},
 	{"convertsToTime", newConvertsToTimeFunction(), -1, 0, 0},
 	{"indexOf", newIndexOfFunction(), -1, 1, 1},
+	{"lastIndexOf", newLastIndexOfFunction(), -1, 1, 1},
 	{"substring", newSubstringFunction(), -1, 1, 2},
 	{"startsWith", newStartsWithFunction(), -1, 1, 1},
 	{"endsWith", newEndsWithFunction(), -1, 1, 1},
@@ -93,9 +98,17 @@ var functionTests = []struct {
 	{"lower", newLowerFunction(), -1, 0, 0},
 	{"replace", newReplaceFunction(), -1, 2, 2},
 	{"matches", newMatchesFunction(), -1, 1, 1},
+	{"matchesFull", newMatchesFullFunction(), -1, 1, 1},
 	{"replaceMatches", newReplaceMatchesFunction(), -1, 2, 2},
 	{"length", newLengthFunction(), -1, 0, 0},
 	{"toChars", newToCharsFunction(), -1, 0, 0},
+	{"trim", newTrimFunction(), -1, 0, 0},
+	{"split", newSplitFunction(), -1, 1, 1},
+	{"join", newJoinFunction(), -1, 0, 1},
+	{"encode", newEncodeFunction(), -1, 1, 1},
+	{"decode", newDecodeFunction(), -1, 1, 1},
+	{"escape", newEscapeFunction(), -1, 1, 1},
+	{"unescape", newUnescapeFunction(), -1, 1, 1},
 	{"abs", newAbsFunction(), -1, 0, 0},
 	{"ceiling", newCeilingFunction(), -1, 0, 0},
 	{"exp", newExpFunction(), -1, 0, 0},
@@ -106,15 +119,41 @@ var functionTests = []struct {
 	{"round", newRoundFunction(), -1, 0, 1},
 	{"sqrt", newSqrtFunction(), -1, 0, 0},
 	{"truncate", newTruncateFunction(), -1, 0, 0},
+	{"defineVariable", newDefineVariableFunction(), -1, 1, 2},
 	{"trace", newTraceFunction(), 1, 1, 2},
 	{"now", newNowFunction(), -1, 0, 0},
 	{"timeOfDay", newTimeOfDayFunction(), -1, 0, 0},
 	{"today", newTodayFunction(), -1, 0, 0},
+	{"precision", newPrecisionFunction(), -1, 0, 0},
+	{"lowBoundary", newLowBoundaryFunction(), -1, 0, 1},
+	{"highBoundary", newHighBoundaryFunction(), -1, 0, 1},
+	{"yearOf", newYearOfFunction(), -1, 0, 0},
+	{"monthOf", newMonthOfFunction(), -1, 0, 0},
+	{"dayOf", newDayOfFunction(), -1, 0, 0},
+	{"hourOf", newHourOfFunction(), -1, 0, 0},
+	{"minuteOf", newMinuteOfFunction(), -1, 0, 0},
+	{"secondOf", newSecondOfFunction(), -1, 0, 0},
+	{"millisecondOf", newMillisecondOfFunction(), -1, 0, 0},
+	{"timezoneOffsetOf", newTimezoneOffsetOfFunction(), -1, 0, 0},
+	{"duration", newDurationFunction(), -1, 2, 2},
+	{"difference", newDifferenceFunction(), -1, 2, 2},
 	{"children", childrenFunc, -1, 0, 0},
 	{"descendants", newDescendantsFunction(), -1, 0, 0},
 	{"as", newAsFunction(), -1, 1, 1},
 	{"is", newIsFunction(), -1, 1, 1},
+	{"type", newTypeFunction(), -1, 0, 0},
 	{"aggregate", newAggregateFunction(), 0, 1, 2},
+	{"sum", newSumFunction(), -1, 0, 0},
+	{"min", newMinFunction(), -1, 0, 0},
+	{"max", newMaxFunction(), -1, 0, 0},
+	{"avg", newAvgFunction(), -1, 0, 0},
+	{"median", newMedianFunction(), -1, 0, 0},
+	{"extension", newExtensionFunction(), -1, 1, 1},
+	{"hasValue", newHasValueFunction(), -1, 0, 0},
+	{"getValue", newGetValueFunction(), -1, 0, 0},
+	{"memberOf", newMemberOfFunction(), -1, 1, 1},
+	{"subsumes", newSubsumesFunction(), -1, 1, 1},
+	{"subsumedBy", newSubsumedByFunction(), -1, 1, 1},
 }
 
 func TestFunctions(t *testing.T) {
@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -43,22 +42,32 @@ func NewIndexerExpression(exprEvaluator hipathsys.Evaluator, indexEvaluator hipa
 }
 
 func (e *IndexerExpression) Evaluate(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, error) {
-	col, err := e.exprEvaluator.Evaluate(ctx, node, loop)
+	res, _, err := e.evaluateContext(ctx, node, loop)
+	return res, err
+}
+
+func (e *IndexerExpression) evaluateContext(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	col, exprCtx, err := evaluateContext(e.exprEvaluator, ctx, node, loop)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	index, err := e.indexEvaluator.Evaluate(ctx, node, loop)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	res, err := e.index(col, index)
+	return res, exprCtx, err
+}
+
+func (e *IndexerExpression) index(col interface{}, index interface{}) (interface{}, error) {
 	if col == nil || index == nil {
 		return nil, nil
 	}
 
 	var indexValue int
 	if n, ok := index.(hipathsys.NumberAccessor); !ok {
-		return nil, fmt.Errorf("index is not a number: %T", index)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "index is not a number: %T", index)
 	} else {
 		indexValue = int(n.Int())
 	}
@@ -39,11 +39,28 @@ func NewInvocationExpression(exprEvaluator hipathsys.Evaluator, invocationEvalua
 	return &InvocationExpression{exprEvaluator, invocationEvaluator}
 }
 
+type contextEvaluator interface {
+	evaluateContext(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error)
+}
+
+func evaluateContext(evaluator hipathsys.Evaluator, ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	if e, ok := evaluator.(contextEvaluator); ok {
+		return e.evaluateContext(ctx, node, loop)
+	}
+	res, err := evaluator.Evaluate(ctx, node, loop)
+	return res, ctx, err
+}
+
 func (e *InvocationExpression) Evaluate(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, error) {
-	exprNode, err := e.exprEvaluator.Evaluate(ctx, node, loop)
+	res, _, err := e.evaluateContext(ctx, node, loop)
+	return res, err
+}
+
+func (e *InvocationExpression) evaluateContext(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	exprNode, ctx, err := evaluateContext(e.exprEvaluator, ctx, node, loop)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return e.invocationEvaluator.Evaluate(ctx, exprNode, loop)
+	return evaluateContext(e.invocationEvaluator, ctx, exprNode, loop)
 }
@@ -79,3 +79,26 @@ func TestInvocationExpressionEvaluateFuncErr(t *testing.T) {
 	assert.Error(t, err, "error expected")
 	assert.Nil(t, res, "no res expected")
 }
+
+func TestInvocationExpressionEvaluateDefinedVariable(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f, err := LookupFunctionInvocation("defineVariable", []hipathsys.Evaluator{
+		ParseStringLiteral("'v'"), ParseStringLiteral("'test'")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := LookupFunctionInvocation("exists", []hipathsys.Evaluator{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	evaluator := NewInvocationExpression(
+		NewInvocationExpression(NewSourceExpression(NewInvocationTerm(f), 1, 0, "defineVariable('v', 'test')"), e),
+		ParseExtConstantTerm("v"))
+
+	res, err := evaluator.Evaluate(ctx, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("test"), res)
+}
@@ -41,3 +41,7 @@ func NewInvocationTerm(evaluator hipathsys.Evaluator) *InvocationTerm {
 func (t *InvocationTerm) Evaluate(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, error) {
 	return t.evaluator.Evaluate(ctx, node, loop)
 }
+
+func (t *InvocationTerm) evaluateContext(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	return evaluateContext(t.evaluator, ctx, node, loop)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+)
+
+type LambdaExpression struct {
+	name      string
+	evaluator hipathsys.Evaluator
+}
+
+type VariableInvocation struct {
+	name string
+}
+
+func NewLambdaExpression(name string, evaluator hipathsys.Evaluator) *LambdaExpression {
+	return &LambdaExpression{name, evaluator}
+}
+
+func (e *LambdaExpression) Name() string {
+	return e.name
+}
+
+func (e *LambdaExpression) Evaluate(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, error) {
+	if len(e.name) > 0 {
+		ctx = hipathsys.NewVariableContext(ctx, e.name, node)
+	}
+	return e.evaluator.Evaluate(ctx, node, loop)
+}
+
+func NewVariableInvocation(name string) *VariableInvocation {
+	return &VariableInvocation{name}
+}
+
+func (i *VariableInvocation) Evaluate(ctx hipathsys.ContextAccessor, _ interface{}, _ hipathsys.Looper) (interface{}, error) {
+	value, found := hipathsys.LookupVariable(ctx, i.name)
+	if !found {
+		return nil, fmt.Errorf("variable has not been defined: %s", i.name)
+	}
+	return value, nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLambdaExpression(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	e := NewLambdaExpression("x", NewVariableInvocation("x"))
+	assert.Equal(t, "x", e.Name())
+
+	res, err := e.Evaluate(ctx, hipathsys.NewString("test"), nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("test"), res)
+}
+
+func TestLambdaExpressionUnnamed(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	e := NewLambdaExpression("", NewVariableInvocation("x"))
+
+	res, err := e.Evaluate(ctx, hipathsys.NewString("test"), nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestLambdaExpressionSelect(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(1))
+	col.Add(hipathsys.NewInteger(2))
+
+	loopEvaluator := NewLambdaExpression("x", NewArithmeticExpression(
+		NewVariableInvocation("x"), hipathsys.MultiplicationOp, NewNumberLiteralInt(10)))
+	res, err := newSelectFunction().Execute(ctx, col, nil, hipathsys.NewLoop(loopEvaluator))
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		c := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 2, c.Count()) {
+			assert.Equal(t, hipathsys.NewInteger(10), c.Get(0))
+			assert.Equal(t, hipathsys.NewInteger(20), c.Get(1))
+		}
+	}
+}
+
+func TestVariableInvocationNotDefined(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := NewVariableInvocation("x").Evaluate(ctx, nil, nil)
+	assert.EqualError(t, err, "variable has not been defined: x")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestLookupFunctionInvocationLambdaNotLoopParam(t *testing.T) {
+	res, err := LookupFunctionInvocation("iif", []hipathsys.Evaluator{
+		NewLambdaExpression("x", NewBooleanLiteral(true)), NewBooleanLiteral(true)})
+	assert.EqualError(t, err, "executor iif does not accept a lambda expression as parameter 0")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestLookupFunctionInvocationLambdaLazyParam(t *testing.T) {
+	res, err := LookupFunctionInvocation("sort", []hipathsys.Evaluator{
+		NewThisInvocation(), NewLambdaExpression("x", NewThisInvocation())})
+	assert.NoError(t, err, "no error expected")
+	assert.NotNil(t, res, "result expected")
+}
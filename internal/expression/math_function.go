@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -210,7 +209,10 @@ func (f *roundFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, a
 		return nil, err
 	}
 
-	if i, ok := r.(hipathsys.IntegerAccessor); ok {
+	switch i := r.(type) {
+	case hipathsys.IntegerAccessor:
+		return hipathsys.NewDecimal(i.Decimal()), nil
+	case hipathsys.LongAccessor:
 		return hipathsys.NewDecimal(i.Decimal()), nil
 	}
 	return r, nil
@@ -256,7 +258,10 @@ func (f *truncateFunction) Execute(_ hipathsys.ContextAccessor, node interface{}
 	}
 
 	t := n.Truncate(0)
-	if i, ok := t.(hipathsys.IntegerAccessor); ok {
+	switch i := t.(type) {
+	case hipathsys.IntegerAccessor:
+		return i, nil
+	case hipathsys.LongAccessor:
 		return i, nil
 	}
 	return hipathsys.NewInteger(t.Int()), nil
@@ -269,7 +274,7 @@ func arithmeticNode(node interface{}) (hipathsys.ArithmeticApplier, error) {
 	}
 
 	if a, ok := value.(hipathsys.ArithmeticApplier); !ok {
-		return nil, fmt.Errorf("arithmetic cannot be applied: %T", value)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "arithmetic cannot be applied: %T", value)
 	} else {
 		return a, nil
 	}
@@ -282,7 +287,7 @@ func numberNode(node interface{}) (hipathsys.NumberAccessor, error) {
 	}
 
 	if a, ok := value.(hipathsys.NumberAccessor); !ok {
-		return nil, fmt.Errorf("not a number: %T", value)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "not a number: %T", value)
 	} else {
 		return a, nil
 	}
@@ -295,7 +300,7 @@ func integerNode(node interface{}) (hipathsys.IntegerAccessor, error) {
 	}
 
 	if a, ok := value.(hipathsys.IntegerAccessor); !ok {
-		return nil, fmt.Errorf("not an integer: %T", value)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "not an integer: %T", value)
 	} else {
 		return a, nil
 	}
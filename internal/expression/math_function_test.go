@@ -596,6 +596,17 @@ func TestRoundFuncInteger(t *testing.T) {
 	}
 }
 
+func TestRoundFuncLong(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newRoundFunction()
+	res, err := f.Execute(ctx, hipathsys.NewLong(8_000_000_000), []interface{}{hipathsys.NewInteger(3)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res) {
+		assert.Equal(t, 8_000_000_000.0, res.(hipathsys.DecimalAccessor).Float64())
+	}
+}
+
 func TestRoundFuncIntegerCol(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -747,6 +758,16 @@ func TestTruncateFuncInteger(t *testing.T) {
 	}
 }
 
+func TestTruncateFuncLong(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newTruncateFunction()
+	l := hipathsys.NewLong(8_000_000_000)
+	res, err := f.Execute(ctx, l, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, l, res)
+}
+
 func TestTruncateFuncIntegerCol(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -45,6 +45,18 @@ func (i *MemberInvocation) Evaluate(ctx hipathsys.ContextAccessor, node interfac
 	if node == nil {
 		return nil, fmt.Errorf("cannot extract path from empty: %s", i.name)
 	}
+	if err := hipathsys.CheckCanceled(ctx); err != nil {
+		return nil, err
+	}
 
-	return ctx.ModelAdapter().Navigate(node, i.name)
+	res, err := hipathsys.ModelNavigate(ctx.ModelAdapter(), node, i.name)
+	if err != nil {
+		return nil, hipathsys.NewAdapterError(err)
+	}
+	if col, ok := res.(hipathsys.ColAccessor); ok {
+		if err := hipathsys.CheckCollectionSize(ctx, col.Count()); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
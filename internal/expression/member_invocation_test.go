@@ -29,6 +29,8 @@
 package expression
 
 import (
+	"context"
+	"github.com/healthiop/hipath/hipathsys"
 	"github.com/healthiop/hipath/internal/test"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -60,6 +62,17 @@ func TestMemberInvocationNotFound(t *testing.T) {
 	ctx := test.NewTestContext(t)
 	e := NewMemberInvocation("x2")
 	res, err := e.Evaluate(ctx, model, nil)
-	assert.Error(t, err, "error expected")
+	assert.ErrorIs(t, err, hipathsys.ErrAdapter)
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestMemberInvocationCanceled(t *testing.T) {
+	model := make(map[string]interface{})
+	model["x1"] = "test"
+
+	ctx := test.NewCanceledTestContext(t)
+	e := NewMemberInvocation("x1")
+	res, err := e.Evaluate(ctx, model, nil)
+	assert.ErrorIs(t, err, context.Canceled)
 	assert.Nil(t, res, "no result expected")
 }
@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -68,7 +67,7 @@ func (e *ContainsExpression) Evaluate(ctx hipathsys.ContextAccessor, node interf
 		return nil, nil
 	}
 	if hipathsys.IsCol(val) {
-		return nil, fmt.Errorf("collection membership cannot be checked with value: %T", val)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "collection membership cannot be checked with value: %T", val)
 	}
 
 	return hipathsys.BooleanOf(col.Contains(val)), nil
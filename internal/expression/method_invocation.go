@@ -0,0 +1,97 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+)
+
+type MethodInvocation struct {
+	name            string
+	paramEvaluators []hipathsys.Evaluator
+	fallback        *FunctionInvocation
+	fallbackErr     error
+}
+
+func NewMethodInvocation(name string, paramEvaluators []hipathsys.Evaluator, fallback *FunctionInvocation, fallbackErr error) *MethodInvocation {
+	return &MethodInvocation{name, paramEvaluators, fallback, fallbackErr}
+}
+
+func (m *MethodInvocation) Evaluate(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, error) {
+	res, _, err := m.evaluateContext(ctx, node, loop)
+	return res, err
+}
+
+func (m *MethodInvocation) evaluateContext(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	provider, ok := unwrapCollection(node).(hipathsys.MethodProvider)
+	if !ok {
+		if m.fallback == nil {
+			return nil, nil, m.fallbackErr
+		}
+		return m.fallback.evaluateContext(ctx, node, loop)
+	}
+
+	executor, ok := provider.Method(m.name)
+	if !ok {
+		if m.fallback != nil {
+			return m.fallback.evaluateContext(ctx, node, loop)
+		}
+		return nil, nil, fmt.Errorf("method has not been defined: %s", m.name)
+	}
+
+	if len(m.paramEvaluators) < executor.MinParams() {
+		return nil, nil, fmt.Errorf("method %s requires at least %d parameters", m.name, executor.MinParams())
+	}
+	if len(m.paramEvaluators) > executor.MaxParams() {
+		return nil, nil, fmt.Errorf("method %s accepts at most %d parameters", m.name, executor.MaxParams())
+	}
+	if err := hipathsys.CountFunctionCall(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	contextNode, _ := ctx.EnvVar(hipathsys.ContextEnvVarName)
+	args := make([]interface{}, len(m.paramEvaluators))
+	for pos, argEvaluator := range m.paramEvaluators {
+		arg, err := argEvaluator.Evaluate(ctx, contextNode, loop)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error in argument %d of method invocation %s: %w", pos, m.name, err)
+		}
+		args[pos] = arg
+	}
+
+	res, err := executor.Execute(ctx, provider, args, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkResultLimits(ctx, res); err != nil {
+		return nil, nil, err
+	}
+	return res, ctx, nil
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"errors"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testMethodProvider struct {
+	hipathsys.AnyAccessor
+	node interface{}
+	args []interface{}
+}
+
+type testMethod struct {
+	hipathsys.BaseFunction
+	provider *testMethodProvider
+}
+
+func newTestMethodProvider() *testMethodProvider {
+	return &testMethodProvider{AnyAccessor: hipathsys.NewString("provider")}
+}
+
+func (p *testMethodProvider) Method(name string) (hipathsys.FunctionExecutor, bool) {
+	if name != "test" {
+		return nil, false
+	}
+	return &testMethod{hipathsys.NewBaseFunction(name, -1, 1, 2), p}, true
+}
+
+func (m *testMethod) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	m.provider.node = node
+	m.provider.args = args
+	return hipathsys.NewString("result"), nil
+}
+
+func TestMethodInvocation(t *testing.T) {
+	p := newTestMethodProvider()
+	arg := newTestExpression(hipathsys.NewString("arg"))
+	contextNode := hipathsys.NewString("context")
+	ctx := hipathsys.NewVariableContext(test.NewTestContext(t), hipathsys.ContextEnvVarName, contextNode)
+	e := NewMethodInvocation("test", []hipathsys.Evaluator{arg}, nil, nil)
+
+	res, err := e.Evaluate(ctx, p, testLoop)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("result"), res)
+	assert.Same(t, p, p.node)
+	assert.Equal(t, []interface{}{hipathsys.NewString("arg")}, p.args)
+	assert.Same(t, contextNode, arg.node)
+}
+
+func TestMethodInvocationCol(t *testing.T) {
+	p := newTestMethodProvider()
+	ctx := test.NewTestContext(t)
+	e := NewMethodInvocation("test", []hipathsys.Evaluator{ParseStringLiteral("arg")}, nil, nil)
+
+	res, err := e.Evaluate(ctx, ctx.NewColWithItem(p), testLoop)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("result"), res)
+	assert.Same(t, p, p.node)
+}
+
+func TestMethodInvocationUndefined(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	e := NewMethodInvocation("other", []hipathsys.Evaluator{}, nil, nil)
+
+	res, err := e.Evaluate(ctx, newTestMethodProvider(), testLoop)
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "method has not been defined: other", err.Error())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestMethodInvocationNoProvider(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	lookupErr := errors.New("executor has not been defined: test")
+	e := NewMethodInvocation("test", []hipathsys.Evaluator{ParseStringLiteral("arg")}, nil, lookupErr)
+
+	res, err := e.Evaluate(ctx, hipathsys.NewString("test"), testLoop)
+	assert.Same(t, lookupErr, err)
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestMethodInvocationNoProviderFunctionInput(t *testing.T) {
+	ctx := hipathsys.NewVariableContext(test.NewTestContext(t), hipathsys.ContextEnvVarName, hipathsys.NewString("context"))
+	arg := newTestExpression(hipathsys.True)
+	fallback := newFunctionInvocation(newWhereFunction(), []hipathsys.Evaluator{arg})
+	e := NewMethodInvocation("where", []hipathsys.Evaluator{arg}, fallback, nil)
+
+	node := hipathsys.NewString("test")
+	res, err := e.Evaluate(ctx, node, testLoop)
+	assert.NoError(t, err, "no error expected")
+	if col, ok := res.(hipathsys.ColAccessor); assert.True(t, ok, "collection expected") &&
+		assert.Equal(t, 1, col.Count()) {
+		assert.Same(t, node, col.Get(0))
+	}
+	assert.Same(t, node, arg.node)
+}
+
+func TestMethodInvocationFallback(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	fallback := newFunctionInvocation(newExistsFunction(), []hipathsys.Evaluator{})
+	e := NewMethodInvocation("exists", []hipathsys.Evaluator{}, fallback, nil)
+
+	res, err := e.Evaluate(ctx, hipathsys.NewString("test"), testLoop)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+
+	res, err = e.Evaluate(ctx, newTestMethodProvider(), testLoop)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+}
+
+func TestMethodInvocationTooFewParams(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	e := NewMethodInvocation("test", []hipathsys.Evaluator{}, nil, nil)
+
+	res, err := e.Evaluate(ctx, newTestMethodProvider(), testLoop)
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "method test requires at least 1 parameters", err.Error())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestMethodInvocationTooManyParams(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	e := NewMethodInvocation("test", []hipathsys.Evaluator{
+		ParseStringLiteral("a"), ParseStringLiteral("b"), ParseStringLiteral("c")}, nil, nil)
+
+	res, err := e.Evaluate(ctx, newTestMethodProvider(), testLoop)
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "method test accepts at most 2 parameters", err.Error())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestMethodInvocationArgError(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	e := NewMethodInvocation("test", []hipathsys.Evaluator{ParseStringLiteral("a"), newTestErrorExpression()}, nil, nil)
+
+	res, err := e.Evaluate(ctx, newTestMethodProvider(), testLoop)
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, "error in argument 1 of method invocation test: an error occurred", err.Error())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestMethodInvocationFunctionCallsLimit(t *testing.T) {
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{MaxFunctionCalls: 1})
+	e := NewMethodInvocation("test", []hipathsys.Evaluator{ParseStringLiteral("a")}, nil, nil)
+
+	_, err := e.Evaluate(ctx, newTestMethodProvider(), testLoop)
+	assert.NoError(t, err, "no error expected")
+
+	res, err := e.Evaluate(ctx, newTestMethodProvider(), testLoop)
+	if assert.Error(t, err, "error expected") {
+		var limitErr *hipathsys.LimitError
+		if assert.True(t, errors.As(err, &limitErr)) {
+			assert.Equal(t, hipathsys.FunctionCallsLimit, limitErr.Kind())
+		}
+	}
+	assert.Nil(t, res, "no result expected")
+}
@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -53,7 +52,7 @@ func (e *NegatorExpression) Evaluate(ctx hipathsys.ContextAccessor, node interfa
 
 	negator, ok := data.(hipathsys.Negator)
 	if !ok {
-		return nil, fmt.Errorf("cannot negate value of type: %T", data)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "cannot negate value of type: %T", data)
 	}
 	return negator.Negate(), nil
 }
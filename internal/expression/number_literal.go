@@ -33,6 +33,8 @@ import (
 	"strings"
 )
 
+const longSuffix = "L"
+
 type NumberLiteral struct {
 	node hipathsys.NumberAccessor
 }
@@ -51,6 +53,8 @@ func ParseNumberLiteral(value string) (hipathsys.Evaluator, error) {
 
 	if strings.ContainsRune(value, '.') {
 		node, err = hipathsys.ParseDecimal(value)
+	} else if strings.HasSuffix(value, longSuffix) {
+		node, err = hipathsys.ParseLong(strings.TrimSuffix(value, longSuffix))
 	} else {
 		node, err = hipathsys.ParseInteger(value)
 	}
@@ -77,6 +77,33 @@ func TestNumberLiteralInvalidInteger(t *testing.T) {
 	assert.Nil(t, evaluator, "no evaluator expected")
 }
 
+func TestNumberLiteralLong(t *testing.T) {
+	evaluator, err := ParseNumberLiteral("8000000000L")
+
+	assert.NoError(t, err, "no error expected")
+	if assert.NotNil(t, evaluator, "evaluator expected") {
+		res, err := evaluator.Evaluate(nil, nil, nil)
+		assert.NoError(t, err, "no error expected")
+		if assert.Implements(t, (*hipathsys.LongAccessor)(nil), res) {
+			assert.Equal(t, int64(8_000_000_000), res.(hipathsys.LongAccessor).Int64())
+		}
+	}
+}
+
+func TestNumberLiteralInvalidLong(t *testing.T) {
+	evaluator, err := ParseNumberLiteral("80000000000000000000L")
+
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, evaluator, "no evaluator expected")
+}
+
+func TestNumberLiteralIntegerOverflow(t *testing.T) {
+	evaluator, err := ParseNumberLiteral("8000000000")
+
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, evaluator, "no evaluator expected")
+}
+
 func TestNumberLiteralDecimal(t *testing.T) {
 	evaluator, err := ParseNumberLiteral("-72638.1")
 
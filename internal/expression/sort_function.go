@@ -0,0 +1,237 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+	"math"
+	"sort"
+)
+
+const (
+	sortAscending  = "asc"
+	sortDescending = "desc"
+)
+
+// sortFunction sorts the input collection by any number of key expressions.
+// Without a key expression the items themselves are used as key. A key is
+// sorted in descending order when it is negated (-name, x => -x.name) or when
+// it is followed by the string literal 'desc'. The string literal 'asc' marks
+// a key as ascending. A direction that is not preceded by a key applies to the
+// items themselves. Directions must be string literals; identifiers asc and desc
+// would be evaluated as member navigation and are rejected.
+type sortFunction struct {
+	hipathsys.BaseFunction
+}
+
+type sortKey struct {
+	evaluator  hipathsys.Evaluator
+	descending bool
+	flagged    bool
+}
+
+func newSortFunction() *sortFunction {
+	return &sortFunction{
+		BaseFunction: hipathsys.NewBaseFunction("sort", -1, 0, math.MaxInt32),
+	}
+}
+
+func (f *sortFunction) LazyParam(int) bool {
+	return true
+}
+
+func (f *sortFunction) validateParams(paramEvaluators []hipathsys.Evaluator) error {
+	_, err := sortKeys(paramEvaluators)
+	return err
+}
+
+func (f *sortFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	evaluators := make([]hipathsys.Evaluator, len(args))
+	for pos, arg := range args {
+		evaluators[pos] = arg.(hipathsys.LazyArg).Evaluator()
+	}
+	keys, err := sortKeys(evaluators)
+	if err != nil {
+		return nil, err
+	}
+
+	col := wrapCollection(ctx, node)
+	count := col.Count()
+	if count == 0 {
+		return nil, nil
+	}
+
+	values := make([][]interface{}, count)
+	for i := range values {
+		values[i] = make([]interface{}, len(keys))
+	}
+	for k, key := range keys {
+		loop := hipathsys.NewLoop(key.evaluator)
+		for i := 0; i < count; i++ {
+			if err := hipathsys.CheckCanceled(ctx); err != nil {
+				return nil, err
+			}
+
+			this := col.Get(i)
+			loop.IncIndex(this)
+
+			res, err := key.evaluator.Evaluate(ctx, this, loop)
+			if err != nil {
+				return nil, err
+			}
+			if values[i][k], err = unwrapSingleton(res); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	adapter := ctx.ModelAdapter()
+	order := make([]int, count)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		v1, v2 := values[order[i]], values[order[j]]
+		for k, key := range keys {
+			res := compareSortValues(adapter, v1[k], v2[k])
+			if key.descending {
+				res = -res
+			}
+			if res != 0 {
+				return res < 0
+			}
+		}
+		return false
+	})
+
+	sorted := ctx.NewCol()
+	for _, i := range order {
+		sorted.Add(col.Get(i))
+	}
+	return sorted, nil
+}
+
+func sortKeys(evaluators []hipathsys.Evaluator) ([]sortKey, error) {
+	keys := make([]sortKey, 0, len(evaluators))
+	for pos, evaluator := range evaluators {
+		if name, ok := sortDirectionIdentifier(evaluator); ok {
+			return nil, fmt.Errorf("sort direction must be specified as string literal '%s' in parameter %d", name, pos)
+		}
+		if direction, ok := sortDirection(evaluator); ok {
+			if len(keys) == 0 {
+				keys = append(keys, sortKey{evaluator: NewThisInvocation()})
+			}
+			key := &keys[len(keys)-1]
+			if key.flagged {
+				return nil, fmt.Errorf("sort direction has already been specified for key before parameter %d", pos)
+			}
+			key.descending = direction == sortDescending
+			key.flagged = true
+			continue
+		}
+
+		key := sortKey{evaluator: evaluator}
+		switch e := unwrapSourceExpression(evaluator).(type) {
+		case *NegatorExpression:
+			key.evaluator = e.evaluator
+			key.descending = true
+		case *LambdaExpression:
+			if n, ok := unwrapSourceExpression(e.evaluator).(*NegatorExpression); ok {
+				key.evaluator = NewLambdaExpression(e.name, n.evaluator)
+				key.descending = true
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		keys = append(keys, sortKey{evaluator: NewThisInvocation()})
+	}
+	return keys, nil
+}
+
+func sortDirection(evaluator hipathsys.Evaluator) (string, bool) {
+	if l, ok := unwrapSourceExpression(evaluator).(*StringLiteral); ok {
+		switch l.node.String() {
+		case sortAscending, sortDescending:
+			return l.node.String(), true
+		}
+	}
+	return "", false
+}
+
+func sortDirectionIdentifier(evaluator hipathsys.Evaluator) (string, bool) {
+	e := unwrapSourceExpression(evaluator)
+	if t, ok := e.(*InvocationTerm); ok {
+		e = unwrapSourceExpression(t.evaluator)
+	}
+	if m, ok := e.(*MemberInvocation); ok && (m.name == sortAscending || m.name == sortDescending) {
+		return m.name, true
+	}
+	return "", false
+}
+
+func compareSortValues(adapter hipathsys.ModelAdapter, v1 interface{}, v2 interface{}) int {
+	if v1 == nil || v2 == nil {
+		switch {
+		case v1 != nil:
+			return 1
+		case v2 != nil:
+			return -1
+		}
+		return 0
+	}
+
+	c1, ok1 := v1.(hipathsys.Comparator)
+	c2, ok2 := v2.(hipathsys.Comparator)
+	if ok1 && ok2 {
+		res, status := c1.Compare(c2)
+		switch status {
+		case hipathsys.Evaluated:
+			return res
+		case hipathsys.Empty:
+			return 0
+		}
+	}
+
+	if hipathsys.ModelEqual(adapter, v1, v2) {
+		return 0
+	}
+
+	n1 := hipathsys.ModelTypeSpec(adapter, v1).String()
+	n2 := hipathsys.ModelTypeSpec(adapter, v2).String()
+	switch {
+	case n1 < n2:
+		return -1
+	case n1 > n2:
+		return 1
+	}
+	return 0
+}
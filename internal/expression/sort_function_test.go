@@ -0,0 +1,178 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSortFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(3))
+	col.Add(hipathsys.NewInteger(1))
+	col.Add(hipathsys.NewInteger(2))
+
+	f := newSortFunction()
+	res, err := f.Execute(ctx, col, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		c := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 3, c.Count()) {
+			assert.Equal(t, hipathsys.NewInteger(1), c.Get(0))
+			assert.Equal(t, hipathsys.NewInteger(2), c.Get(1))
+			assert.Equal(t, hipathsys.NewInteger(3), c.Get(2))
+		}
+	}
+}
+
+func TestSortFuncEmpty(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newSortFunction()
+	res, err := f.Execute(ctx, nil, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestSortFuncNegatedKey(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("b"))
+	col.Add(hipathsys.NewString("c"))
+	col.Add(hipathsys.NewString("a"))
+
+	f := newSortFunction()
+	res, err := f.Execute(ctx, col, []interface{}{
+		hipathsys.NewLazyArg(NewNegatorExpression(NewThisInvocation()), ctx, col, nil)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		c := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 3, c.Count()) {
+			assert.Equal(t, hipathsys.NewString("c"), c.Get(0))
+			assert.Equal(t, hipathsys.NewString("b"), c.Get(1))
+			assert.Equal(t, hipathsys.NewString("a"), c.Get(2))
+		}
+	}
+}
+
+func TestSortFuncNegatedSourceKey(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("b"))
+	col.Add(hipathsys.NewString("c"))
+	col.Add(hipathsys.NewString("a"))
+
+	f := newSortFunction()
+	key := NewLambdaExpression("x", NewSourceExpression(
+		NewNegatorExpression(NewVariableInvocation("x")), 1, 5, "-x"))
+	res, err := f.Execute(ctx, col, []interface{}{
+		hipathsys.NewLazyArg(NewSourceExpression(key, 1, 0, "x => -x"), ctx, col, nil)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		c := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 3, c.Count()) {
+			assert.Equal(t, hipathsys.NewString("c"), c.Get(0))
+			assert.Equal(t, hipathsys.NewString("b"), c.Get(1))
+			assert.Equal(t, hipathsys.NewString("a"), c.Get(2))
+		}
+	}
+}
+
+func TestSortFuncDescFlag(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewInteger(1))
+	col.Add(hipathsys.NewInteger(3))
+	col.Add(hipathsys.NewInteger(2))
+
+	f := newSortFunction()
+	res, err := f.Execute(ctx, col, []interface{}{
+		hipathsys.NewLazyArg(ParseStringLiteral("'desc'"), ctx, col, nil)}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		c := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 3, c.Count()) {
+			assert.Equal(t, hipathsys.NewInteger(3), c.Get(0))
+			assert.Equal(t, hipathsys.NewInteger(2), c.Get(1))
+			assert.Equal(t, hipathsys.NewInteger(1), c.Get(2))
+		}
+	}
+}
+
+func TestSortFuncDuplicateFlag(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newSortFunction()
+	res, err := f.Execute(ctx, hipathsys.NewInteger(1), []interface{}{
+		hipathsys.NewLazyArg(ParseStringLiteral("'desc'"), ctx, nil, nil),
+		hipathsys.NewLazyArg(ParseStringLiteral("'asc'"), ctx, nil, nil)}, nil)
+	assert.EqualError(t, err, "sort direction has already been specified for key before parameter 1")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestSortFuncDirectionIdentifier(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newSortFunction()
+	res, err := f.Execute(ctx, hipathsys.NewInteger(1), []interface{}{
+		hipathsys.NewLazyArg(NewMemberInvocation("desc"), ctx, nil, nil)}, nil)
+	assert.EqualError(t, err, "sort direction must be specified as string literal 'desc' in parameter 0")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestSortFuncValidateParams(t *testing.T) {
+	f := newSortFunction()
+	assert.NoError(t, f.validateParams([]hipathsys.Evaluator{
+		NewMemberInvocation("name"), ParseStringLiteral("'desc'")}))
+	assert.EqualError(t, f.validateParams([]hipathsys.Evaluator{
+		NewMemberInvocation("name"), NewMemberInvocation("asc")}),
+		"sort direction must be specified as string literal 'asc' in parameter 1")
+}
+
+func TestSortFuncKeyError(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newSortFunction()
+	res, err := f.Execute(ctx, hipathsys.NewInteger(1), []interface{}{
+		hipathsys.NewLazyArg(newTestErrorExpression(), ctx, nil, nil)}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestCompareSortValues(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	adapter := ctx.ModelAdapter()
+	assert.Equal(t, 0, compareSortValues(adapter, nil, nil))
+	assert.Equal(t, -1, compareSortValues(adapter, nil, hipathsys.NewInteger(1)))
+	assert.Equal(t, 1, compareSortValues(adapter, hipathsys.NewInteger(1), nil))
+	assert.Equal(t, -1, compareSortValues(adapter, hipathsys.NewInteger(1), hipathsys.NewDecimalFloat64(1.5)))
+	assert.Equal(t, 0, compareSortValues(adapter, hipathsys.True, hipathsys.True))
+	assert.Equal(t, -1, compareSortValues(adapter, hipathsys.True, hipathsys.NewString("a")))
+	assert.Equal(t, 1, compareSortValues(adapter, hipathsys.NewString("a"), hipathsys.NewInteger(1)))
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"errors"
+	"github.com/healthiop/hipath/hipathsys"
+)
+
+type SourceExpression struct {
+	evaluator hipathsys.Evaluator
+	line      int
+	column    int
+	source    string
+}
+
+func NewSourceExpression(evaluator hipathsys.Evaluator, line int, column int, source string) *SourceExpression {
+	return &SourceExpression{evaluator, line, column, source}
+}
+
+func (e *SourceExpression) Evaluator() hipathsys.Evaluator {
+	return e.evaluator
+}
+
+func (e *SourceExpression) Evaluate(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, error) {
+	res, _, err := e.evaluateContext(ctx, node, loop)
+	return res, err
+}
+
+func (e *SourceExpression) evaluateContext(ctx hipathsys.ContextAccessor, node interface{}, loop hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	res, ctx, err := evaluateContext(e.evaluator, ctx, node, loop)
+	if err != nil {
+		var sourceErr *hipathsys.SourceError
+		if errors.As(err, &sourceErr) {
+			return nil, nil, err
+		}
+		return nil, nil, hipathsys.NewSourceError(e.line, e.column, e.source, err)
+	}
+	return res, ctx, nil
+}
+
+func unwrapSourceExpression(evaluator hipathsys.Evaluator) hipathsys.Evaluator {
+	for {
+		e, ok := evaluator.(*SourceExpression)
+		if !ok {
+			return evaluator
+		}
+		evaluator = e.evaluator
+	}
+}
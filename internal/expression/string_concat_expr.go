@@ -29,8 +29,8 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
+	"unicode/utf8"
 )
 
 type StringConcatExpression struct {
@@ -52,7 +52,12 @@ func (e *StringConcatExpression) Evaluate(ctx hipathsys.ContextAccessor, node in
 		return nil, err
 	}
 
-	left, right = unwrapCollection(left), unwrapCollection(right)
+	if left, err = unwrapSingleton(left); err != nil {
+		return nil, err
+	}
+	if right, err = unwrapSingleton(right); err != nil {
+		return nil, err
+	}
 	if left == nil && right == nil {
 		return hipathsys.EmptyString, nil
 	}
@@ -61,12 +66,12 @@ func (e *StringConcatExpression) Evaluate(ctx hipathsys.ContextAccessor, node in
 	var leftString, rightString hipathsys.Stringifier
 	if left != nil {
 		if leftString, ok = left.(hipathsys.Stringifier); !ok {
-			return nil, fmt.Errorf("left operand is not string: %T", left)
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "left operand is not string: %T", left)
 		}
 	}
 	if right != nil {
 		if rightString, ok = right.(hipathsys.Stringifier); !ok {
-			return nil, fmt.Errorf("right operand is not string: %T", right)
+			return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "right operand is not string: %T", right)
 		}
 	}
 
@@ -76,5 +81,9 @@ func (e *StringConcatExpression) Evaluate(ctx hipathsys.ContextAccessor, node in
 	if rightString == nil {
 		return leftString, nil
 	}
-	return hipathsys.NewString(leftString.String() + rightString.String()), nil
+	res := leftString.String() + rightString.String()
+	if err := hipathsys.CheckStringLength(ctx, utf8.RuneCountInString(res)); err != nil {
+		return nil, err
+	}
+	return hipathsys.NewString(res), nil
 }
@@ -47,6 +47,16 @@ func TestStringConcat(t *testing.T) {
 	}
 }
 
+func TestStringConcatStringLengthLimit(t *testing.T) {
+	evaluator := NewStringConcatExpression(
+		NewRawStringLiteral("Test"), NewRawStringLiteral(" ABC"))
+
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{MaxStringLength: 7})
+	res, err := evaluator.Evaluate(ctx, nil, nil)
+	assert.EqualError(t, err, "evaluation limit exceeded: maximum string length of 7")
+	assert.Nil(t, res, "no result expected")
+}
+
 func TestStringConcatBoolean(t *testing.T) {
 	numberLiteral, err := ParseNumberLiteral("20")
 	if err != nil {
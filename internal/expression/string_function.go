@@ -29,13 +29,29 @@
 package expression
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
+	"html"
 	"regexp"
 	"strings"
 	"unicode/utf8"
 )
 
+const (
+	hexEncoding       = "hex"
+	base64Encoding    = "base64"
+	urlBase64Encoding = "urlbase64"
+)
+
+const (
+	htmlEscaping = "html"
+	jsonEscaping = "json"
+)
+
 type indexOfFunction struct {
 	hipathsys.BaseFunction
 }
@@ -238,7 +254,7 @@ func newReplaceFunction() *replaceFunction {
 	}
 }
 
-func (f *replaceFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+func (f *replaceFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
 	s, err := stringNode(node)
 	if s == nil || err != nil {
 		return nil, err
@@ -254,7 +270,20 @@ func (f *replaceFunction) Execute(_ hipathsys.ContextAccessor, node interface{},
 		return nil, err
 	}
 
-	res := strings.ReplaceAll(s.String(), pattern.String(), substitution.String())
+	str, p, sub := s.String(), pattern.String(), substitution.String()
+	var count int
+	if len(p) == 0 {
+		count = utf8.RuneCountInString(str) + 1
+	} else {
+		count = strings.Count(str, p)
+	}
+	length := utf8.RuneCountInString(str) +
+		count*(utf8.RuneCountInString(sub)-utf8.RuneCountInString(p))
+	if err := hipathsys.CheckStringLength(ctx, length); err != nil {
+		return nil, err
+	}
+
+	res := strings.ReplaceAll(str, p, sub)
 	return hipathsys.StringOf(res), nil
 }
 
@@ -368,6 +397,297 @@ func (f *toCharsFunction) Execute(ctx hipathsys.ContextAccessor, node interface{
 	return col, nil
 }
 
+type lastIndexOfFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newLastIndexOfFunction() *lastIndexOfFunction {
+	return &lastIndexOfFunction{
+		BaseFunction: hipathsys.NewBaseFunction("lastIndexOf", -1, 1, 1),
+	}
+}
+
+func (f *lastIndexOfFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	ss, err := stringNode(args[0])
+	if ss == nil || err != nil {
+		return nil, err
+	}
+
+	str := s.String()
+	i := strings.LastIndex(str, ss.String())
+	if i > 0 {
+		i = utf8.RuneCountInString(str[:i])
+	}
+	return hipathsys.NewInteger(int32(i)), nil
+}
+
+type matchesFullFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newMatchesFullFunction() *matchesFullFunction {
+	return &matchesFullFunction{
+		BaseFunction: hipathsys.NewBaseFunction("matchesFull", -1, 1, 1),
+	}
+}
+
+func (f *matchesFullFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	re, err := stringNode(args[0])
+	if re == nil || err != nil {
+		return nil, err
+	}
+
+	b, err := regexp.MatchString("^(?:"+re.String()+")$", s.String())
+	if err != nil {
+		return nil, err
+	}
+	return hipathsys.BooleanOf(b), nil
+}
+
+type trimFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newTrimFunction() *trimFunction {
+	return &trimFunction{
+		BaseFunction: hipathsys.NewBaseFunction("trim", -1, 0, 0),
+	}
+}
+
+func (f *trimFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	return hipathsys.StringOf(strings.TrimSpace(s.String())), nil
+}
+
+type splitFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newSplitFunction() *splitFunction {
+	return &splitFunction{
+		BaseFunction: hipathsys.NewBaseFunction("split", -1, 1, 1),
+	}
+}
+
+func (f *splitFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	separator, err := stringNode(args[0])
+	if separator == nil || err != nil {
+		return nil, err
+	}
+
+	col := ctx.NewCol()
+	for _, part := range strings.Split(s.String(), separator.String()) {
+		col.Add(hipathsys.StringOf(part))
+	}
+	return col, nil
+}
+
+type joinFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newJoinFunction() *joinFunction {
+	return &joinFunction{
+		BaseFunction: hipathsys.NewBaseFunction("join", -1, 0, 1),
+	}
+}
+
+func (f *joinFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	col := wrapCollection(ctx, node)
+	count := col.Count()
+	if count == 0 {
+		return nil, nil
+	}
+
+	var separator string
+	if len(args) > 0 {
+		s, err := stringNode(args[0])
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			separator = s.String()
+		}
+	}
+
+	parts := make([]string, count)
+	for i := 0; i < count; i++ {
+		s, err := stringNode(col.Get(i))
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			parts[i] = s.String()
+		}
+	}
+
+	return hipathsys.StringOf(strings.Join(parts, separator)), nil
+}
+
+type encodeFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newEncodeFunction() *encodeFunction {
+	return &encodeFunction{
+		BaseFunction: hipathsys.NewBaseFunction("encode", -1, 1, 1),
+	}
+}
+
+func (f *encodeFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	format, err := stringNode(args[0])
+	if format == nil || err != nil {
+		return nil, err
+	}
+
+	value := []byte(s.String())
+	switch format.String() {
+	case hexEncoding:
+		return hipathsys.StringOf(hex.EncodeToString(value)), nil
+	case base64Encoding:
+		return hipathsys.StringOf(base64.StdEncoding.EncodeToString(value)), nil
+	case urlBase64Encoding:
+		return hipathsys.StringOf(base64.URLEncoding.EncodeToString(value)), nil
+	}
+	return nil, fmt.Errorf("unsupported encoding: %s", format.String())
+}
+
+type decodeFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newDecodeFunction() *decodeFunction {
+	return &decodeFunction{
+		BaseFunction: hipathsys.NewBaseFunction("decode", -1, 1, 1),
+	}
+}
+
+func (f *decodeFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	format, err := stringNode(args[0])
+	if format == nil || err != nil {
+		return nil, err
+	}
+
+	var value []byte
+	switch format.String() {
+	case hexEncoding:
+		value, err = hex.DecodeString(s.String())
+	case base64Encoding:
+		value, err = base64.StdEncoding.DecodeString(s.String())
+	case urlBase64Encoding:
+		value, err = base64.URLEncoding.DecodeString(s.String())
+	default:
+		return nil, fmt.Errorf("unsupported encoding: %s", format.String())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s encoded string: %w", format.String(), err)
+	}
+	if !utf8.Valid(value) {
+		return nil, fmt.Errorf("decoded value is not a valid UTF-8 string")
+	}
+	return hipathsys.StringOf(string(value)), nil
+}
+
+type escapeFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newEscapeFunction() *escapeFunction {
+	return &escapeFunction{
+		BaseFunction: hipathsys.NewBaseFunction("escape", -1, 1, 1),
+	}
+}
+
+func (f *escapeFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	target, err := stringNode(args[0])
+	if target == nil || err != nil {
+		return nil, err
+	}
+
+	switch target.String() {
+	case htmlEscaping:
+		return hipathsys.StringOf(html.EscapeString(s.String())), nil
+	case jsonEscaping:
+		var b bytes.Buffer
+		e := json.NewEncoder(&b)
+		e.SetEscapeHTML(false)
+		if err := e.Encode(s.String()); err != nil {
+			return nil, err
+		}
+		value := strings.TrimSuffix(b.String(), "\n")
+		return hipathsys.StringOf(value[1 : len(value)-1]), nil
+	}
+	return nil, fmt.Errorf("unsupported escaping: %s", target.String())
+}
+
+type unescapeFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newUnescapeFunction() *unescapeFunction {
+	return &unescapeFunction{
+		BaseFunction: hipathsys.NewBaseFunction("unescape", -1, 1, 1),
+	}
+}
+
+func (f *unescapeFunction) Execute(_ hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	s, err := stringNode(node)
+	if s == nil || err != nil {
+		return nil, err
+	}
+
+	target, err := stringNode(args[0])
+	if target == nil || err != nil {
+		return nil, err
+	}
+
+	switch target.String() {
+	case htmlEscaping:
+		return hipathsys.StringOf(html.UnescapeString(s.String())), nil
+	case jsonEscaping:
+		var value string
+		if err := json.Unmarshal([]byte("\""+s.String()+"\""), &value); err != nil {
+			return nil, fmt.Errorf("invalid json escaped string: %w", err)
+		}
+		return hipathsys.StringOf(value), nil
+	}
+	return nil, fmt.Errorf("unsupported escaping: %s", target.String())
+}
+
 func stringNode(node interface{}) (hipathsys.StringAccessor, error) {
 	value := unwrapCollection(node)
 	if value == nil {
@@ -375,7 +695,7 @@ func stringNode(node interface{}) (hipathsys.StringAccessor, error) {
 	}
 
 	if s, ok := value.(hipathsys.StringAccessor); !ok {
-		return nil, fmt.Errorf("not a string: %T", value)
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "not a string: %T", value)
 	} else {
 		return s, nil
 	}
@@ -699,6 +699,21 @@ func TestReplaceFuncAll(t *testing.T) {
 	assert.Equal(t, hipathsys.NewString("abxyfgxyf"), res)
 }
 
+func TestReplaceFuncStringLengthLimit(t *testing.T) {
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{MaxStringLength: 10})
+
+	f := newReplaceFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("abcabc"),
+		[]interface{}{hipathsys.NewString("b"), hipathsys.NewString("xyz")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("axyzcaxyzc"), res)
+
+	res, err = f.Execute(ctx, hipathsys.NewString("abcabc"),
+		[]interface{}{hipathsys.NewString(""), hipathsys.NewString("x")}, nil)
+	assert.EqualError(t, err, "evaluation limit exceeded: maximum string length of 10")
+	assert.Nil(t, res, "no result expected")
+}
+
 func TestReplaceFuncRemove(t *testing.T) {
 	ctx := test.NewTestContext(t)
 
@@ -1113,3 +1128,359 @@ func TestToCharsFuncEmptyString(t *testing.T) {
 		assert.Equal(t, 0, col.Count())
 	}
 }
+
+func TestLastIndexOfFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newLastIndexOfFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString("a")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestLastIndexOfFuncSubstringNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newLastIndexOfFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("abc"), []interface{}{nil}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestLastIndexOfFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newLastIndexOfFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("ÁbcÁbc"), []interface{}{hipathsys.NewString("bc")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(4), res)
+}
+
+func TestLastIndexOfFuncNotFound(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newLastIndexOfFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("abc"), []interface{}{hipathsys.NewString("x")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewInteger(-1), res)
+}
+
+func TestLastIndexOfFuncInputInvalid(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newLastIndexOfFunction()
+	res, err := f.Execute(ctx, hipathsys.True, []interface{}{hipathsys.NewString("x")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestMatchesFullFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newMatchesFullFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString("test")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestMatchesFullFuncRegexNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newMatchesFullFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"), []interface{}{nil}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestMatchesFullFuncTrue(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newMatchesFullFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test123"),
+		[]interface{}{hipathsys.NewString("[a-z]+|\\d+|[a-z]+\\d+")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+}
+
+func TestMatchesFullFuncFalse(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newMatchesFullFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("Atest123abcZ"),
+		[]interface{}{hipathsys.NewString("[a-z]+\\d+[a-z]+")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
+func TestMatchesFullFuncRegexInvalid(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newMatchesFullFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"),
+		[]interface{}{hipathsys.NewString("[a-z")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestTrimFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newTrimFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestTrimFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newTrimFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString(" \t test 1\n "), []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("test 1"), res)
+}
+
+func TestTrimFuncInputInvalid(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newTrimFunction()
+	res, err := f.Execute(ctx, hipathsys.True, []interface{}{}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestSplitFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newSplitFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString(",")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestSplitFuncSeparatorNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newSplitFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("a,b"), []interface{}{nil}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestSplitFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newSplitFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("a,,bc"), []interface{}{hipathsys.NewString(",")}, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		col := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 3, col.Count()) {
+			assert.Equal(t, hipathsys.NewString("a"), col.Get(0))
+			assert.Equal(t, hipathsys.NewString(""), col.Get(1))
+			assert.Equal(t, hipathsys.NewString("bc"), col.Get(2))
+		}
+	}
+}
+
+func TestJoinFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newJoinFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString(",")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestJoinFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("a"))
+	col.Add(hipathsys.NewString("b"))
+	col.Add(hipathsys.NewString("c"))
+
+	f := newJoinFunction()
+	res, err := f.Execute(ctx, col, []interface{}{hipathsys.NewString(", ")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("a, b, c"), res)
+}
+
+func TestJoinFuncNoSeparator(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("a"))
+	col.Add(hipathsys.NewString("b"))
+
+	f := newJoinFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("ab"), res)
+}
+
+func TestJoinFuncInputInvalid(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("a"))
+	col.Add(hipathsys.NewInteger(1))
+
+	f := newJoinFunction()
+	res, err := f.Execute(ctx, col, []interface{}{}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestEncodeFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newEncodeFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString("hex")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestEncodeFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newEncodeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test?>"), []interface{}{hipathsys.NewString("hex")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("746573743f3e"), res)
+	res, err = f.Execute(ctx, hipathsys.NewString("test?>"), []interface{}{hipathsys.NewString("base64")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("dGVzdD8+"), res)
+	res, err = f.Execute(ctx, hipathsys.NewString("test?>"), []interface{}{hipathsys.NewString("urlbase64")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("dGVzdD8-"), res)
+}
+
+func TestEncodeFuncInvalidFormat(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newEncodeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"), []interface{}{hipathsys.NewString("base32")}, nil)
+	assert.EqualError(t, err, "unsupported encoding: base32")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestDecodeFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newDecodeFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString("hex")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestDecodeFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newDecodeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("746573743f3e"), []interface{}{hipathsys.NewString("hex")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("test?>"), res)
+	res, err = f.Execute(ctx, hipathsys.NewString("dGVzdD8+"), []interface{}{hipathsys.NewString("base64")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("test?>"), res)
+	res, err = f.Execute(ctx, hipathsys.NewString("dGVzdD8-"), []interface{}{hipathsys.NewString("urlbase64")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("test?>"), res)
+}
+
+func TestDecodeFuncInvalid(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newDecodeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("xyz"), []interface{}{hipathsys.NewString("hex")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestDecodeFuncInvalidUTF8(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newDecodeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("ff"), []interface{}{hipathsys.NewString("hex")}, nil)
+	assert.EqualError(t, err, "decoded value is not a valid UTF-8 string")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestDecodeFuncInvalidFormat(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newDecodeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"), []interface{}{hipathsys.NewString("base32")}, nil)
+	assert.EqualError(t, err, "unsupported encoding: base32")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestEscapeFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newEscapeFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString("html")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestEscapeFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newEscapeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("\"1\" < 2\n"), []interface{}{hipathsys.NewString("html")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("&#34;1&#34; &lt; 2\n"), res)
+	res, err = f.Execute(ctx, hipathsys.NewString("\"1\" < 2\n"), []interface{}{hipathsys.NewString("json")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("\\\"1\\\" < 2\\n"), res)
+}
+
+func TestEscapeFuncInvalidTarget(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newEscapeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"), []interface{}{hipathsys.NewString("xml")}, nil)
+	assert.EqualError(t, err, "unsupported escaping: xml")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestUnescapeFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newUnescapeFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{hipathsys.NewString("html")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestUnescapeFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newUnescapeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("&quot;1&quot; &lt; 2"), []interface{}{hipathsys.NewString("html")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("\"1\" < 2"), res)
+	res, err = f.Execute(ctx, hipathsys.NewString("\\\"1\\\" < 2\\n\\u00c1"), []interface{}{hipathsys.NewString("json")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.NewString("\"1\" < 2\nÁ"), res)
+}
+
+func TestUnescapeFuncInvalid(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newUnescapeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("\\x"), []interface{}{hipathsys.NewString("json")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty collection expected")
+}
+
+func TestUnescapeFuncInvalidTarget(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newUnescapeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test"), []interface{}{hipathsys.NewString("xml")}, nil)
+	assert.EqualError(t, err, "unsupported escaping: xml")
+	assert.Nil(t, res, "empty collection expected")
+}
@@ -48,6 +48,10 @@ func ParseStringLiteral(value string) hipathsys.Evaluator {
 	return &StringLiteral{hipathsys.NewString(parseStringLiteral(value, stringDelimiterChar))}
 }
 
+func UnquoteStringLiteral(value string) string {
+	return parseStringLiteral(value, stringDelimiterChar)
+}
+
 func parseStringLiteral(value string, delimiter byte) string {
 	l := len(value)
 	if l < 2 || value[0] != delimiter || value[l-1] != delimiter {
@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -50,7 +49,7 @@ func (f *singleFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}
 		return nil, nil
 	}
 	if count > 1 {
-		return nil, fmt.Errorf("expected collection with one item: %d", count)
+		return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind, "expected collection with one item: %d", count)
 	}
 	return col.Get(0), nil
 }
@@ -130,7 +129,7 @@ func newSkipFunction() *skipFunction {
 func (f *skipFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
 	var num int
 	if n, ok := unwrapCollection(args[0]).(hipathsys.NumberAccessor); !ok {
-		return nil, fmt.Errorf("argument must be an integer: %T", args[0])
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "argument must be an integer: %T", args[0])
 	} else {
 		num = int(n.Int())
 	}
@@ -165,7 +164,7 @@ func newTakeFunction() *takeFunction {
 func (f *takeFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
 	var num int
 	if n, ok := unwrapCollection(args[0]).(hipathsys.NumberAccessor); !ok {
-		return nil, fmt.Errorf("argument must be an integer: %T", args[0])
+		return nil, hipathsys.NewKindErrorf(hipathsys.TypeMismatchErrorKind, "argument must be an integer: %T", args[0])
 	} else {
 		num = int(n.Int())
 	}
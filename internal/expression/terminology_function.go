@@ -0,0 +1,140 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+)
+
+type memberOfFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newMemberOfFunction() *memberOfFunction {
+	return &memberOfFunction{
+		BaseFunction: hipathsys.NewBaseFunction("memberOf", -1, 1, 1),
+	}
+}
+
+func (f *memberOfFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	valueSet, err := stringNode(args[0])
+	if valueSet == nil || err != nil {
+		return nil, err
+	}
+
+	codings, err := nodeCodings(ctx, node)
+	if len(codings) == 0 || err != nil {
+		return nil, err
+	}
+
+	provider, err := terminologyProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, coding := range codings {
+		member, err := provider.MemberOf(valueSet.String(), coding)
+		if err != nil {
+			return nil, hipathsys.NewTerminologyError(err)
+		}
+		if member {
+			return hipathsys.True, nil
+		}
+	}
+	return hipathsys.False, nil
+}
+
+type subsumesFunction struct {
+	hipathsys.BaseFunction
+	inverse bool
+}
+
+func newSubsumesFunction() *subsumesFunction {
+	return newSubsumesFunctionWithName("subsumes", false)
+}
+
+func newSubsumedByFunction() *subsumesFunction {
+	return newSubsumesFunctionWithName("subsumedBy", true)
+}
+
+func newSubsumesFunctionWithName(name string, inverse bool) *subsumesFunction {
+	return &subsumesFunction{
+		BaseFunction: hipathsys.NewBaseFunction(name, -1, 1, 1),
+		inverse:      inverse,
+	}
+}
+
+func (f *subsumesFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	codings, err := nodeCodings(ctx, node)
+	if len(codings) == 0 || err != nil {
+		return nil, err
+	}
+	others, err := nodeCodings(ctx, args[0])
+	if len(others) == 0 || err != nil {
+		return nil, err
+	}
+
+	provider, err := terminologyProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, coding := range codings {
+		for _, other := range others {
+			var subsumes bool
+			if f.inverse {
+				subsumes, err = provider.Subsumes(other, coding)
+			} else {
+				subsumes, err = provider.Subsumes(coding, other)
+			}
+			if err != nil {
+				return nil, hipathsys.NewTerminologyError(err)
+			}
+			if subsumes {
+				return hipathsys.True, nil
+			}
+		}
+	}
+	return hipathsys.False, nil
+}
+
+func terminologyProvider(ctx hipathsys.ContextAccessor) (hipathsys.TerminologyProvider, error) {
+	provider := hipathsys.ContextTerminologyProvider(ctx)
+	if provider == nil {
+		return nil, hipathsys.NewTerminologyError(fmt.Errorf("no terminology provider has been configured"))
+	}
+	return provider, nil
+}
+
+func nodeCodings(ctx hipathsys.ContextAccessor, node interface{}) ([]hipathsys.Coding, error) {
+	codings, err := hipathsys.ModelCodings(ctx.ModelAdapter(), node)
+	if err != nil {
+		return nil, hipathsys.NewAdapterError(err)
+	}
+	return codings, nil
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package expression
+
+import (
+	"fmt"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testTerminologyProvider struct {
+	valueSets map[string][]hipathsys.Coding
+	parents   map[hipathsys.Coding]hipathsys.Coding
+}
+
+type testTerminologyContext struct {
+	hipathsys.ContextAccessor
+	provider hipathsys.TerminologyProvider
+}
+
+var testDiabetes = hipathsys.Coding{System: "http://example.org/cs", Code: "diabetes"}
+var testDiabetesType1 = hipathsys.Coding{System: "http://example.org/cs", Code: "diabetes-type-1"}
+
+func newTestTerminologyContext(t *testing.T) hipathsys.ContextAccessor {
+	return &testTerminologyContext{
+		ContextAccessor: test.NewTestContext(t),
+		provider: &testTerminologyProvider{
+			valueSets: map[string][]hipathsys.Coding{
+				"http://example.org/vs": {testDiabetes},
+			},
+			parents: map[hipathsys.Coding]hipathsys.Coding{
+				testDiabetesType1: testDiabetes,
+			},
+		},
+	}
+}
+
+func (c *testTerminologyContext) Delegate() hipathsys.ContextAccessor {
+	return c.ContextAccessor
+}
+
+func (c *testTerminologyContext) TerminologyProvider() hipathsys.TerminologyProvider {
+	return c.provider
+}
+
+func (p *testTerminologyProvider) MemberOf(valueSet string, coding hipathsys.Coding) (bool, error) {
+	codings, found := p.valueSets[valueSet]
+	if !found {
+		return false, fmt.Errorf("value set is unknown: %s", valueSet)
+	}
+	for _, c := range codings {
+		if c.Code == coding.Code && (len(coding.System) == 0 || c.System == coding.System) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (p *testTerminologyProvider) Subsumes(coding hipathsys.Coding, other hipathsys.Coding) (bool, error) {
+	if len(coding.System) == 0 || len(other.System) == 0 {
+		return false, fmt.Errorf("code system has not been specified")
+	}
+	return coding == other || p.parents[other] == coding, nil
+}
+
+func testCodingNode(coding hipathsys.Coding) map[string]interface{} {
+	return map[string]interface{}{
+		"coding": nil,
+		"system": hipathsys.NewString(coding.System),
+		"code":   hipathsys.NewString(coding.Code),
+	}
+}
+
+func testConceptNode(ctx hipathsys.ContextAccessor, codings ...hipathsys.Coding) map[string]interface{} {
+	col := ctx.NewCol()
+	for _, c := range codings {
+		col.Add(testCodingNode(c))
+	}
+	return map[string]interface{}{"coding": col}
+}
+
+func TestMemberOfFuncCoding(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+}
+
+func TestMemberOfFuncConcept(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, testConceptNode(ctx, testDiabetesType1, testDiabetes),
+		[]interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+}
+
+func TestMemberOfFuncCode(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("diabetes"), []interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+}
+
+func TestMemberOfFuncNotMember(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetesType1), []interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
+func TestMemberOfFuncEmpty(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, hipathsys.EmptyCol, []interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+
+	res, err = f.Execute(ctx, testCodingNode(hipathsys.Coding{}), []interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+
+	res, err = f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{nil}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestMemberOfFuncInvalidValueSet(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{hipathsys.NewInteger(1)}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestMemberOfFuncUnknownValueSet(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{hipathsys.NewString("http://example.org/x")}, nil)
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, hipathsys.TerminologyErrorKind, hipathsys.ErrorKindOf(err))
+	}
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestMemberOfFuncNoProvider(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, hipathsys.TerminologyErrorKind, hipathsys.ErrorKindOf(err))
+	}
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestMemberOfFuncNavigateError(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newMemberOfFunction()
+	res, err := f.Execute(ctx, map[string]interface{}{}, []interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+
+	res, err = f.Execute(ctx, map[string]interface{}{"coding": nil, "code": hipathsys.NewString("x")},
+		[]interface{}{hipathsys.NewString("http://example.org/vs")}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestSubsumesFunc(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newSubsumesFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{testConceptNode(ctx, testDiabetesType1)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+
+	res, err = f.Execute(ctx, testCodingNode(testDiabetesType1), []interface{}{testCodingNode(testDiabetes)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
+func TestSubsumedByFunc(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newSubsumedByFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetesType1), []interface{}{testCodingNode(testDiabetes)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.True, res)
+
+	res, err = f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{testCodingNode(testDiabetesType1)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Equal(t, hipathsys.False, res)
+}
+
+func TestSubsumesFuncEmpty(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newSubsumesFunction()
+	res, err := f.Execute(ctx, nil, []interface{}{testCodingNode(testDiabetes)}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+
+	res, err = f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{hipathsys.EmptyCol}, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestSubsumesFuncProviderError(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newSubsumesFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("diabetes"), []interface{}{testCodingNode(testDiabetes)}, nil)
+	if assert.Error(t, err, "error expected") {
+		assert.Equal(t, hipathsys.TerminologyErrorKind, hipathsys.ErrorKindOf(err))
+	}
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestSubsumesFuncNoProvider(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newSubsumesFunction()
+	res, err := f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{testCodingNode(testDiabetes)}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestSubsumesFuncNavigateError(t *testing.T) {
+	ctx := newTestTerminologyContext(t)
+
+	f := newSubsumesFunction()
+	res, err := f.Execute(ctx, map[string]interface{}{}, []interface{}{testCodingNode(testDiabetes)}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+
+	res, err = f.Execute(ctx, testCodingNode(testDiabetes), []interface{}{map[string]interface{}{}}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+
+	res, err = f.Execute(ctx, map[string]interface{}{"coding": map[string]interface{}{}}, []interface{}{testCodingNode(testDiabetes)}, nil)
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "empty result expected")
+}
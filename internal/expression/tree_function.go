@@ -53,11 +53,15 @@ func (f *childrenFunction) Execute(ctx hipathsys.ContextAccessor, node interface
 		var children hipathsys.ColModifier
 
 		for i := 0; i < count; i++ {
+			if err := hipathsys.CheckCanceled(ctx); err != nil {
+				return nil, err
+			}
+
 			c := col.Get(i)
 			if c != nil {
 				ccol, err := adapter.Children(c)
 				if err != nil {
-					return nil, err
+					return nil, hipathsys.NewAdapterError(err)
 				}
 				if ccol != nil && !ccol.Empty() {
 					if children == nil {
@@ -71,7 +75,11 @@ func (f *childrenFunction) Execute(ctx hipathsys.ContextAccessor, node interface
 		return children, nil
 	}
 
-	return ctx.ModelAdapter().Children(node)
+	res, err := ctx.ModelAdapter().Children(node)
+	if err != nil {
+		return nil, hipathsys.NewAdapterError(err)
+	}
+	return res, nil
 }
 
 type descendantsFunction struct {
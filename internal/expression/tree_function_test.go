@@ -29,6 +29,7 @@
 package expression
 
 import (
+	"context"
 	"github.com/healthiop/hipath/hipathsys"
 	"github.com/healthiop/hipath/internal/test"
 	"github.com/stretchr/testify/assert"
@@ -301,3 +302,13 @@ func TestDescendantsFuncColDescendantsError(t *testing.T) {
 	assert.Error(t, err, "error expected")
 	assert.Nil(t, res, "empty collection expected")
 }
+
+func TestDescendantsFuncCanceled(t *testing.T) {
+	ctx := test.NewCanceledTestContext(t)
+	col := ctx.NewColWithItem(map[string]interface{}{"a": hipathsys.NewString("test")})
+
+	f := newDescendantsFunction()
+	res, err := f.Execute(ctx, col, nil, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, res, "no result expected")
+}
@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -59,7 +58,7 @@ func (e *AsTypeExpression) Evaluate(ctx hipathsys.ContextAccessor, node interfac
 
 	item := unwrapCollection(value)
 	if _, ok := item.(hipathsys.ColAccessor); ok {
-		return nil, fmt.Errorf("as operator cannot be applied on a collection")
+		return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind, "as operator cannot be applied on a collection")
 	}
 
 	return hipathsys.CastModelType(ctx.ModelAdapter(), item, e.fqName)
@@ -91,7 +90,7 @@ func (e *IsTypeExpression) Evaluate(ctx hipathsys.ContextAccessor, node interfac
 
 	item := unwrapCollection(value)
 	if _, ok := item.(hipathsys.ColAccessor); ok {
-		return nil, fmt.Errorf("is operator cannot be applied on a collection")
+		return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind, "is operator cannot be applied on a collection")
 	}
 
 	return hipathsys.BooleanOf(hipathsys.HasModelType(ctx.ModelAdapter(), item, e.fqName)), nil
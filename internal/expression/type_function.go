@@ -29,7 +29,6 @@
 package expression
 
 import (
-	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 )
 
@@ -56,7 +55,7 @@ func (f *asFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, ar
 
 	item := unwrapCollection(node)
 	if _, ok := item.(hipathsys.ColAccessor); ok {
-		return nil, fmt.Errorf("as function cannot be applied on a collection")
+		return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind, "as function cannot be applied on a collection")
 	}
 
 	return hipathsys.CastModelType(ctx.ModelAdapter(), item, fqName)
@@ -85,8 +84,34 @@ func (f *isFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, ar
 
 	item := unwrapCollection(node)
 	if _, ok := item.(hipathsys.ColAccessor); ok {
-		return nil, fmt.Errorf("is function cannot be applied on a collection")
+		return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind, "is function cannot be applied on a collection")
 	}
 
 	return hipathsys.BooleanOf(hipathsys.HasModelType(ctx.ModelAdapter(), item, fqName)), nil
 }
+
+type typeFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newTypeFunction() *typeFunction {
+	return &typeFunction{
+		BaseFunction: hipathsys.NewBaseFunction("type", -1, 0, 0),
+	}
+}
+
+func (f *typeFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, _ []interface{}, _ hipathsys.Looper) (interface{}, error) {
+	adapter := ctx.ModelAdapter()
+	if col, ok := node.(hipathsys.ColAccessor); ok {
+		count := col.Count()
+		res := ctx.NewCol()
+		for i := 0; i < count; i++ {
+			if ti := hipathsys.ModelTypeInfo(adapter, col.Get(i)); ti != nil {
+				res.Add(ti)
+			}
+		}
+		return res, nil
+	}
+
+	return hipathsys.ModelTypeInfo(adapter, node), nil
+}
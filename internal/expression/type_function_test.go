@@ -226,3 +226,42 @@ func TestIsTypeFuncColMultipleItems(t *testing.T) {
 	assert.Error(t, err, "error expected")
 	assert.Nil(t, res, "no result expected")
 }
+
+func TestTypeFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newTypeFunction()
+	res, err := f.Execute(ctx, hipathsys.NewString("test1"), nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.SimpleTypeInfoAccessor)(nil), res) {
+		ti := res.(hipathsys.SimpleTypeInfoAccessor)
+		assert.Equal(t, hipathsys.NewString("System"), ti.Namespace())
+		assert.Equal(t, hipathsys.NewString("String"), ti.Name())
+		assert.Equal(t, hipathsys.NewString("System.Any"), ti.BaseType())
+	}
+}
+
+func TestTypeFuncNil(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	f := newTypeFunction()
+	res, err := f.Execute(ctx, nil, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	assert.Nil(t, res, "empty result expected")
+}
+
+func TestTypeFuncCol(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	col := ctx.NewCol()
+	col.Add(hipathsys.NewString("test1"))
+	col.Add(hipathsys.NewInteger(10))
+
+	f := newTypeFunction()
+	res, err := f.Execute(ctx, col, nil, nil)
+	assert.NoError(t, err, "no error expected")
+	if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+		c := res.(hipathsys.ColAccessor)
+		if assert.Equal(t, 2, c.Count()) {
+			assert.Equal(t, hipathsys.NewString("String"), c.Get(0).(hipathsys.SimpleTypeInfoAccessor).Name())
+			assert.Equal(t, hipathsys.NewString("Integer"), c.Get(1).(hipathsys.SimpleTypeInfoAccessor).Name())
+		}
+	}
+}
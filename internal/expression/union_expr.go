@@ -49,5 +49,11 @@ func (e *UnionExpression) Evaluate(ctx hipathsys.ContextAccessor, node interface
 		return nil, err
 	}
 
-	return uniteCollections(ctx, left, right), nil
+	res := uniteCollections(ctx, left, right)
+	if res != nil {
+		if err := hipathsys.CheckCollectionSize(ctx, res.Count()); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
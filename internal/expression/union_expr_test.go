@@ -49,6 +49,14 @@ func TestUnionExpressionLiteral(t *testing.T) {
 	}
 }
 
+func TestUnionExpressionCollectionSizeLimit(t *testing.T) {
+	ctx := test.NewLimitedTestContext(t, hipathsys.Limits{MaxCollectionSize: 1})
+	e := NewUnionExpression(ParseStringLiteral("test1"), ParseStringLiteral("test2"))
+	res, err := e.Evaluate(ctx, nil, nil)
+	assert.EqualError(t, err, "evaluation limit exceeded: maximum collection size of 1")
+	assert.Nil(t, res, "no result expected")
+}
+
 func TestUnionExpressionCollection(t *testing.T) {
 	ctx := test.NewTestContext(t)
 	c1 := ctx.NewCol()
@@ -110,6 +110,15 @@ func unwrapCollection(node interface{}) interface{} {
 	}
 }
 
+func unwrapSingleton(node interface{}) (interface{}, error) {
+	node = unwrapCollection(node)
+	if _, ok := node.(hipathsys.ColAccessor); ok {
+		return nil, hipathsys.NewKindErrorf(hipathsys.SingletonExpectedErrorKind,
+			"collection with multiple items cannot be used as single value")
+	}
+	return node, nil
+}
+
 func wrapCollection(ctx hipathsys.ContextAccessor, node interface{}) hipathsys.ColAccessor {
 	if node == nil {
 		return hipathsys.EmptyCol
@@ -29,10 +29,47 @@
 package expression
 
 import (
+	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 	"time"
 )
 
+type defineVariableFunction struct {
+	hipathsys.BaseFunction
+}
+
+func newDefineVariableFunction() *defineVariableFunction {
+	return &defineVariableFunction{
+		BaseFunction: hipathsys.NewLazyBaseFunction("defineVariable", -1, 1, 2, 1),
+	}
+}
+
+func (f *defineVariableFunction) Execute(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, loop hipathsys.Looper) (interface{}, error) {
+	res, _, err := f.ExecuteContext(ctx, node, args, loop)
+	return res, err
+}
+
+func (f *defineVariableFunction) ExecuteContext(ctx hipathsys.ContextAccessor, node interface{}, args []interface{}, _ hipathsys.Looper) (interface{}, hipathsys.ContextAccessor, error) {
+	name, err := stringNode(args[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	if name == nil || len(name.String()) == 0 {
+		return nil, nil, fmt.Errorf("variable name must not be empty")
+	}
+	if _, found := ctx.EnvVar(name.String()); found {
+		return nil, nil, fmt.Errorf("variable has already been defined: %s", name.String())
+	}
+
+	value := node
+	if len(args) > 1 {
+		if value, err = hipathsys.EvaluateArg(args[1]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return node, hipathsys.NewVariableContext(ctx, name.String(), value), nil
+}
+
 type traceFunction struct {
 	hipathsys.BaseFunction
 }
@@ -36,6 +36,86 @@ import (
 	"time"
 )
 
+func TestDefineVariableFunc(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	node := hipathsys.NewString("value")
+
+	f := newDefineVariableFunction()
+	res, resCtx, err := f.ExecuteContext(ctx, node, []interface{}{hipathsys.NewString("v"),
+		hipathsys.NewLazyArg(ParseStringLiteral("'test'"), ctx, node, nil)}, nil)
+
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, node, res, "unchanged node expected")
+	if assert.NotNil(t, resCtx, "context expected") {
+		v, found := resCtx.EnvVar("v")
+		assert.True(t, found)
+		assert.Equal(t, hipathsys.NewString("test"), v)
+	}
+	_, found := ctx.EnvVar("v")
+	assert.False(t, found)
+}
+
+func TestDefineVariableFuncInput(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	node := hipathsys.NewString("value")
+
+	f := newDefineVariableFunction()
+	res, resCtx, err := f.ExecuteContext(ctx, node, []interface{}{hipathsys.NewString("v")}, nil)
+
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, node, res, "unchanged node expected")
+	if assert.NotNil(t, resCtx, "context expected") {
+		v, found := resCtx.EnvVar("v")
+		assert.True(t, found)
+		assert.Same(t, node, v)
+	}
+}
+
+func TestDefineVariableFuncExecute(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	node := hipathsys.NewString("value")
+
+	f := newDefineVariableFunction()
+	res, err := f.Execute(ctx, node, []interface{}{hipathsys.NewString("v")}, nil)
+
+	assert.NoError(t, err, "no error expected")
+	assert.Same(t, node, res, "unchanged node expected")
+}
+
+func TestDefineVariableFuncEmptyName(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newDefineVariableFunction()
+	res, resCtx, err := f.ExecuteContext(ctx, nil, []interface{}{nil}, nil)
+
+	assert.EqualError(t, err, "variable name must not be empty")
+	assert.Nil(t, res, "no result expected")
+	assert.Nil(t, resCtx, "no context expected")
+}
+
+func TestDefineVariableFuncDefined(t *testing.T) {
+	ctx := hipathsys.NewVariableContext(test.NewTestContext(t), "v", hipathsys.NewString("test"))
+
+	f := newDefineVariableFunction()
+	res, resCtx, err := f.ExecuteContext(ctx, nil, []interface{}{hipathsys.NewString("v")}, nil)
+
+	assert.EqualError(t, err, "variable has already been defined: v")
+	assert.Nil(t, res, "no result expected")
+	assert.Nil(t, resCtx, "no context expected")
+}
+
+func TestDefineVariableFuncValueError(t *testing.T) {
+	ctx := test.NewTestContext(t)
+
+	f := newDefineVariableFunction()
+	res, resCtx, err := f.ExecuteContext(ctx, nil, []interface{}{hipathsys.NewString("v"),
+		hipathsys.NewLazyArg(newTestErrorExpression(), ctx, nil, nil)}, nil)
+
+	assert.Error(t, err, "error expected")
+	assert.Nil(t, res, "no result expected")
+	assert.Nil(t, resCtx, "no context expected")
+}
+
 func TestTraceFuncNoTracer(t *testing.T) {
 	ctx := test.NewTestContext(t)
 	node := hipathsys.NewString("value")
@@ -33,11 +33,12 @@ import (
 	"github.com/healthiop/hipath/internal/expression"
 	"github.com/healthiop/hipath/internal/test"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
 func TestParseNegatorExpression(t *testing.T) {
-	res, errorItemCollection := testParse("-123.45")
+	res, errorItemCollection := testParse("-(123.45)")
 
 	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
 		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
@@ -52,6 +53,18 @@ func TestParseNegatorExpression(t *testing.T) {
 	}
 }
 
+func TestParseNegatorExpressionNumberLiteral(t *testing.T) {
+	res, errorItemCollection := testParse("-2147483648")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.NumberLiteral)(nil), res) {
+		res, _ := res.(hipathsys.Evaluator).Evaluate(nil, nil, nil)
+		assert.Equal(t, hipathsys.NewInteger(math.MinInt32), res)
+	}
+}
+
 func TestParseNegatorExpressionPos(t *testing.T) {
 	res, errorItemCollection := testParse("+123.45")
 
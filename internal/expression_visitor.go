@@ -41,9 +41,35 @@ func (v *Visitor) VisitTermExpression(ctx *parser.TermExpressionContext) interfa
 }
 
 func (v *Visitor) VisitPolarityExpression(ctx *parser.PolarityExpressionContext) interface{} {
+	if literal := negativeNumberLiteral(ctx); literal != nil {
+		return v.visit(ctx, func(antlr.ParserRuleContext) (hipathsys.Evaluator, error) {
+			return expression.ParseNumberLiteral("-" + literal.GetText())
+		})
+	}
 	return v.visitTree(ctx, 2, visitPolarityExpression)
 }
 
+func negativeNumberLiteral(ctx *parser.PolarityExpressionContext) antlr.ParserRuleContext {
+	if ctx.GetChildCount() < 2 || ctx.GetChild(0).(antlr.ParseTree).GetText() != "-" {
+		return nil
+	}
+	term, ok := ctx.Expression().(*parser.TermExpressionContext)
+	if !ok {
+		return nil
+	}
+	literalTerm, ok := term.Term().(*parser.LiteralTermContext)
+	if !ok {
+		return nil
+	}
+	switch literal := literalTerm.Literal().(type) {
+	case *parser.NumberLiteralContext:
+		return literal
+	case *parser.LongNumberLiteralContext:
+		return literal
+	}
+	return nil
+}
+
 func visitPolarityExpression(_ antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
 	op := args[0].(string)
 	evaluator := args[1].(hipathsys.Evaluator)
@@ -211,3 +237,19 @@ func visitTypeExpression(_ antlr.ParserRuleContext, args []interface{}) (hipaths
 		return nil, fmt.Errorf("invalid type expression operator: %s", op)
 	}
 }
+
+func (v *Visitor) VisitLambdaExpression(ctx *parser.LambdaExpressionContext) interface{} {
+	if _, ok := ctx.GetParent().(*parser.ParamListContext); !ok {
+		return v.AddError(ctx, "lambda expression is only supported as function parameter")
+	}
+
+	evaluator, ok := v.VisitChild(ctx, ctx.GetChildCount()-1).(hipathsys.Evaluator)
+	if !ok {
+		return nil
+	}
+	var name string
+	if ctx.IDENTIFIER() != nil {
+		name = ctx.IDENTIFIER().GetText()
+	}
+	return expression.NewLambdaExpression(name, evaluator)
+}
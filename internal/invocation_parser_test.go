@@ -118,3 +118,198 @@ func TestParseIsInvocation(t *testing.T) {
 		}
 	}
 }
+
+func TestParseOfTypeInvocation(t *testing.T) {
+	res, errorItemCollection := testParse("('my test' | 10).ofType(System.String)")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := test.NewTestContextWithNode(t, hipathsys.NewString("test"))
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+			col := res.(hipathsys.ColAccessor)
+			if assert.Equal(t, 1, col.Count()) {
+				assert.Equal(t, hipathsys.NewString("my test"), col.Get(0))
+			}
+		}
+	}
+}
+
+func TestParseOfTypeInvocationStringLiteral(t *testing.T) {
+	res, errorItemCollection := testParse("('a' | 1).ofType('System.String')")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := test.NewTestContext(t)
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+			col := res.(hipathsys.ColAccessor)
+			if assert.Equal(t, 1, col.Count()) {
+				assert.Equal(t, hipathsys.NewString("a"), col.Get(0))
+			}
+		}
+	}
+}
+
+func TestParseLambdaSelect(t *testing.T) {
+	res, errorItemCollection := testParse("(10 | 14).select(x => x + 1)")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := test.NewTestContext(t)
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+			col := res.(hipathsys.ColAccessor)
+			if assert.Equal(t, 2, col.Count()) {
+				assert.Equal(t, hipathsys.NewInteger(11), col.Get(0))
+				assert.Equal(t, hipathsys.NewInteger(15), col.Get(1))
+			}
+		}
+	}
+}
+
+func TestParseLambdaNested(t *testing.T) {
+	res, errorItemCollection := testParse("(1 | 2).select(x => (10 | 20).select(y => x * y))")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := test.NewTestContext(t)
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+			col := res.(hipathsys.ColAccessor)
+			if assert.Equal(t, 4, col.Count()) {
+				assert.Equal(t, hipathsys.NewInteger(10), col.Get(0))
+				assert.Equal(t, hipathsys.NewInteger(20), col.Get(1))
+				assert.Equal(t, hipathsys.NewInteger(20), col.Get(2))
+				assert.Equal(t, hipathsys.NewInteger(40), col.Get(3))
+			}
+		}
+	}
+}
+
+func TestParseLambdaShadowed(t *testing.T) {
+	res, errorItemCollection := testParse("(1 | 2).select(x => (10 | 20).select(x => x + 1))")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := test.NewTestContext(t)
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+			col := res.(hipathsys.ColAccessor)
+			if assert.Equal(t, 4, col.Count()) {
+				assert.Equal(t, hipathsys.NewInteger(11), col.Get(0))
+				assert.Equal(t, hipathsys.NewInteger(21), col.Get(1))
+				assert.Equal(t, hipathsys.NewInteger(11), col.Get(2))
+				assert.Equal(t, hipathsys.NewInteger(21), col.Get(3))
+			}
+		}
+	}
+}
+
+func TestParseLambdaUnnamed(t *testing.T) {
+	res, errorItemCollection := testParse("(10 | 14).where(=> $this > 10)")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := test.NewTestContext(t)
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+			col := res.(hipathsys.ColAccessor)
+			if assert.Equal(t, 1, col.Count()) {
+				assert.Equal(t, hipathsys.NewInteger(14), col.Get(0))
+			}
+		}
+	}
+}
+
+func TestParseLambdaNotLoopParam(t *testing.T) {
+	_, errorItemCollection := testParse("true.iif(x => true, 1)")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		if assert.True(t, errorItemCollection.HasErrors(), "errors expected") {
+			assert.Equal(t, "executor iif does not accept a lambda expression as parameter 0",
+				errorItemCollection.Items()[0].Msg())
+		}
+	}
+}
+
+func TestParseMethodInvocation(t *testing.T) {
+	res, errorItemCollection := testParse("%terminologies.expand('http://example.org/vs')")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := hipathsys.NewVariableContext(test.NewTestContext(t), "terminologies", hipathsys.NewString("test"))
+		_, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		if assert.Error(t, err, "evaluation error expected") {
+			assert.Equal(t, "executor has not been defined: expand", err.Error())
+		}
+	}
+}
+
+func TestParseMethodInvocationUndefinedFunction(t *testing.T) {
+	_, errorItemCollection := testParse("%ucum.foo()")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		if assert.True(t, errorItemCollection.HasErrors(), "errors expected") {
+			assert.Equal(t, "executor has not been defined: foo", errorItemCollection.Items()[0].Msg())
+		}
+	}
+}
+
+func TestParseMethodInvocationFunctionParams(t *testing.T) {
+	_, errorItemCollection := testParse("%ucum.substring()")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.True(t, errorItemCollection.HasErrors(), "errors expected")
+	}
+}
+
+func TestParseMethodInvocationQuotedName(t *testing.T) {
+	_, errorItemCollection := testParse("%`terminologies`.foo()")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+}
+
+func TestParseMethodInvocationFunction(t *testing.T) {
+	res, errorItemCollection := testParse("%ucum.exists()")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.InvocationExpression)(nil), res) {
+		ctx := test.NewTestContext(t)
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		assert.Equal(t, hipathsys.True, res)
+	}
+}
+
+func TestParseFunctionUndefined(t *testing.T) {
+	_, errorItemCollection := testParse("'test'.expand('http://example.org/vs')")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.True(t, errorItemCollection.HasErrors(), "errors expected")
+	}
+}
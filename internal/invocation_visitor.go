@@ -33,6 +33,7 @@ import (
 	"github.com/healthiop/hipath/hipathsys"
 	"github.com/healthiop/hipath/internal/expression"
 	"github.com/healthiop/hipath/internal/parser"
+	"strings"
 )
 
 func (v *Visitor) VisitFunctionInvocation(ctx *parser.FunctionInvocationContext) interface{} {
@@ -40,10 +41,10 @@ func (v *Visitor) VisitFunctionInvocation(ctx *parser.FunctionInvocationContext)
 }
 
 func (v *Visitor) VisitFunction(ctx *parser.FunctionContext) interface{} {
-	return v.visitTree(ctx, 3, visitFunction)
+	return v.visitTree(ctx, 3, v.visitFunction)
 }
 
-func visitFunction(_ antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
+func (v *Visitor) visitFunction(ctx antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
 	name := args[0].(string)
 
 	var paramEvaluators []hipathsys.Evaluator
@@ -52,6 +53,10 @@ func visitFunction(_ antlr.ParserRuleContext, args []interface{}) (hipathsys.Eva
 	} else if name == "as" || name == "is" {
 		typeSpec := args[2].(string)
 		paramEvaluators = []hipathsys.Evaluator{expression.NewRawStringLiteral(typeSpec)}
+	} else if name == "ofType" && len(args[2].([]interface{})) == 1 &&
+		typeSpecifierExpression(ctx.(*parser.FunctionContext).ParamList().(*parser.ParamListContext).Expression(0)) {
+		typeSpec := ctx.(*parser.FunctionContext).ParamList().GetText()
+		paramEvaluators = []hipathsys.Evaluator{expression.NewRawStringLiteral(typeSpec)}
 	} else {
 		paramList := args[2].([]interface{})
 		// commas need to removed from argument list
@@ -63,7 +68,65 @@ func visitFunction(_ antlr.ParserRuleContext, args []interface{}) (hipathsys.Eva
 		}
 	}
 
-	return expression.LookupFunctionInvocation(expression.ExtractIdentifier(name), paramEvaluators)
+	name = expression.ExtractIdentifier(name)
+	var fi *expression.FunctionInvocation
+	var err error
+	if v.functions != nil {
+		fi, err = v.functions.LookupFunctionInvocation(name, paramEvaluators)
+	} else {
+		fi, err = expression.LookupFunctionInvocation(name, paramEvaluators)
+	}
+	receiver, external := externalConstantReceiver(ctx)
+	method := external && hipathsys.MethodProviderEnvVarName(receiver)
+	if err != nil && !method {
+		return nil, err
+	}
+
+	if name == defineVariableFunctionName {
+		if err := v.defineVariable(ctx.(*parser.FunctionContext)); err != nil {
+			return nil, err
+		}
+	}
+	if method {
+		return expression.NewMethodInvocation(name, paramEvaluators, fi, err), nil
+	}
+	return fi, nil
+}
+
+func typeSpecifierExpression(ctx parser.IExpressionContext) bool {
+	switch e := ctx.(type) {
+	case *parser.TermExpressionContext:
+		if term, ok := e.Term().(*parser.InvocationTermContext); ok {
+			_, ok = term.Invocation().(*parser.MemberInvocationContext)
+			return ok
+		}
+	case *parser.InvocationExpressionContext:
+		if _, ok := e.Invocation().(*parser.MemberInvocationContext); ok {
+			return typeSpecifierExpression(e.Expression())
+		}
+	}
+	return false
+}
+
+func externalConstantReceiver(ctx antlr.ParserRuleContext) (string, bool) {
+	invocation, ok := ctx.GetParent().(*parser.FunctionInvocationContext)
+	if !ok {
+		return "", false
+	}
+	expr, ok := invocation.GetParent().(*parser.InvocationExpressionContext)
+	if !ok {
+		return "", false
+	}
+	term, ok := expr.Expression().(*parser.TermExpressionContext)
+	if !ok {
+		return "", false
+	}
+	constant, ok := term.Term().(*parser.ExternalConstantTermContext)
+	if !ok {
+		return "", false
+	}
+	name := strings.TrimPrefix(constant.ExternalConstant().GetText(), "%")
+	return expression.ExtractIdentifier(name), true
 }
 
 func (v *Visitor) VisitParamList(ctx *parser.ParamListContext) interface{} {
@@ -83,10 +146,23 @@ func (v *Visitor) VisitTotalInvocation(*parser.TotalInvocationContext) interface
 }
 
 func (v *Visitor) VisitMemberInvocation(ctx *parser.MemberInvocationContext) interface{} {
-	return v.visitTree(ctx, 1, visitMemberInvocation)
+	return v.visitTree(ctx, 1, v.visitMemberInvocation)
 }
 
-func visitMemberInvocation(_ antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
-	name := args[0].(string)
-	return expression.NewMemberInvocation(expression.ExtractIdentifier(name)), nil
+func (v *Visitor) visitMemberInvocation(ctx antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
+	name := expression.ExtractIdentifier(args[0].(string))
+	if _, ok := ctx.GetParent().(*parser.InvocationTermContext); ok && lambdaVariable(ctx, name) {
+		return expression.NewVariableInvocation(name), nil
+	}
+	return expression.NewMemberInvocation(name), nil
+}
+
+func lambdaVariable(ctx antlr.ParserRuleContext, name string) bool {
+	for c := ctx.GetParent(); c != nil; c = c.GetParent() {
+		if lambda, ok := c.(*parser.LambdaExpressionContext); ok &&
+			lambda.IDENTIFIER() != nil && lambda.IDENTIFIER().GetText() == name {
+			return true
+		}
+	}
+	return false
 }
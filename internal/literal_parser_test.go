@@ -81,6 +81,20 @@ func TestParseNumberLiteral(t *testing.T) {
 	}
 }
 
+func TestParseLongNumberLiteral(t *testing.T) {
+	res, errorItemCollection := testParse("8000000000L")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.IsType(t, (*expression.NumberLiteral)(nil), res) {
+		res, _ := res.(hipathsys.Evaluator).Evaluate(nil, nil, nil)
+		if assert.Implements(t, (*hipathsys.LongAccessor)(nil), res) {
+			assert.Equal(t, int64(8_000_000_000), res.(hipathsys.LongAccessor).Int64())
+		}
+	}
+}
+
 func TestParseDateTimeLiteral(t *testing.T) {
 	res, errorItemCollection := testParse("@2014-05-25T14:30:14.559Z")
 
@@ -59,6 +59,10 @@ func visitNumberLiteral(ctx antlr.ParserRuleContext) (hipathsys.Evaluator, error
 	return expression.ParseNumberLiteral(ctx.GetText())
 }
 
+func (v *Visitor) VisitLongNumberLiteral(ctx *parser.LongNumberLiteralContext) interface{} {
+	return v.visit(ctx, visitNumberLiteral)
+}
+
 func (v *Visitor) VisitDateLiteral(ctx *parser.DateLiteralContext) interface{} {
 	return v.visit(ctx, visitDateLiteral)
 }
@@ -63,6 +63,10 @@ func (v *BaseFHIRPathVisitor) VisitTypeExpression(ctx *TypeExpressionContext) in
 	return v.VisitChildren(ctx)
 }
 
+func (v *BaseFHIRPathVisitor) VisitLambdaExpression(ctx *LambdaExpressionContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *BaseFHIRPathVisitor) VisitInvocationTerm(ctx *InvocationTermContext) interface{} {
 	return v.VisitChildren(ctx)
 }
@@ -95,6 +99,10 @@ func (v *BaseFHIRPathVisitor) VisitNumberLiteral(ctx *NumberLiteralContext) inte
 	return v.VisitChildren(ctx)
 }
 
+func (v *BaseFHIRPathVisitor) VisitLongNumberLiteral(ctx *LongNumberLiteralContext) interface{} {
+	return v.VisitChildren(ctx)
+}
+
 func (v *BaseFHIRPathVisitor) VisitDateLiteral(ctx *DateLiteralContext) interface{} {
 	return v.VisitChildren(ctx)
 }
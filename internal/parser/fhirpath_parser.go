@@ -15,7 +15,7 @@ var _ = reflect.Copy
 var _ = strconv.Itoa
 
 var parserATN = []uint16{
-	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 3, 66, 164,
+	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 3, 68, 170,
 	4, 2, 9, 2, 4, 3, 9, 3, 4, 4, 9, 4, 4, 5, 9, 5, 4, 6, 9, 6, 4, 7, 9, 7,
 	4, 8, 9, 8, 4, 9, 9, 9, 4, 10, 9, 10, 4, 11, 9, 11, 4, 12, 9, 12, 4, 13,
 	9, 13, 4, 14, 9, 14, 4, 15, 9, 15, 3, 2, 3, 2, 3, 2, 3, 2, 5, 2, 35, 10,
@@ -31,81 +31,84 @@ var parserATN = []uint16{
 	12, 8, 14, 8, 137, 11, 8, 3, 9, 3, 9, 5, 9, 141, 10, 9, 3, 10, 3, 10, 3,
 	10, 5, 10, 146, 10, 10, 3, 11, 3, 11, 3, 12, 3, 12, 3, 13, 3, 13, 3, 14,
 	3, 14, 3, 14, 7, 14, 157, 10, 14, 12, 14, 14, 14, 160, 11, 14, 3, 15, 3,
-	15, 3, 15, 2, 3, 2, 16, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26,
-	28, 2, 14, 3, 2, 6, 7, 3, 2, 8, 11, 4, 2, 6, 7, 12, 12, 3, 2, 16, 19, 3,
-	2, 20, 23, 3, 2, 24, 25, 3, 2, 27, 28, 3, 2, 13, 14, 3, 2, 34, 35, 3, 2,
-	41, 48, 3, 2, 49, 56, 5, 2, 13, 14, 24, 25, 60, 61, 2, 185, 2, 34, 3, 2,
-	2, 2, 4, 86, 3, 2, 2, 2, 6, 97, 3, 2, 2, 2, 8, 99, 3, 2, 2, 2, 10, 109,
-	3, 2, 2, 2, 12, 128, 3, 2, 2, 2, 14, 130, 3, 2, 2, 2, 16, 138, 3, 2, 2,
-	2, 18, 145, 3, 2, 2, 2, 20, 147, 3, 2, 2, 2, 22, 149, 3, 2, 2, 2, 24, 151,
-	3, 2, 2, 2, 26, 153, 3, 2, 2, 2, 28, 161, 3, 2, 2, 2, 30, 31, 8, 2, 1,
-	2, 31, 35, 5, 4, 3, 2, 32, 33, 9, 2, 2, 2, 33, 35, 5, 2, 2, 13, 34, 30,
-	3, 2, 2, 2, 34, 32, 3, 2, 2, 2, 35, 76, 3, 2, 2, 2, 36, 37, 12, 12, 2,
-	2, 37, 38, 9, 3, 2, 2, 38, 75, 5, 2, 2, 13, 39, 40, 12, 11, 2, 2, 40, 41,
-	9, 4, 2, 2, 41, 75, 5, 2, 2, 12, 42, 43, 12, 9, 2, 2, 43, 44, 7, 15, 2,
-	2, 44, 75, 5, 2, 2, 10, 45, 46, 12, 8, 2, 2, 46, 47, 9, 5, 2, 2, 47, 75,
-	5, 2, 2, 9, 48, 49, 12, 7, 2, 2, 49, 50, 9, 6, 2, 2, 50, 75, 5, 2, 2, 8,
-	51, 52, 12, 6, 2, 2, 52, 53, 9, 7, 2, 2, 53, 75, 5, 2, 2, 7, 54, 55, 12,
-	5, 2, 2, 55, 56, 7, 26, 2, 2, 56, 75, 5, 2, 2, 6, 57, 58, 12, 4, 2, 2,
-	58, 59, 9, 8, 2, 2, 59, 75, 5, 2, 2, 5, 60, 61, 12, 3, 2, 2, 61, 62, 7,
-	29, 2, 2, 62, 75, 5, 2, 2, 4, 63, 64, 12, 15, 2, 2, 64, 65, 7, 3, 2, 2,
-	65, 75, 5, 10, 6, 2, 66, 67, 12, 14, 2, 2, 67, 68, 7, 4, 2, 2, 68, 69,
-	5, 2, 2, 2, 69, 70, 7, 5, 2, 2, 70, 75, 3, 2, 2, 2, 71, 72, 12, 10, 2,
-	2, 72, 73, 9, 9, 2, 2, 73, 75, 5, 24, 13, 2, 74, 36, 3, 2, 2, 2, 74, 39,
-	3, 2, 2, 2, 74, 42, 3, 2, 2, 2, 74, 45, 3, 2, 2, 2, 74, 48, 3, 2, 2, 2,
-	74, 51, 3, 2, 2, 2, 74, 54, 3, 2, 2, 2, 74, 57, 3, 2, 2, 2, 74, 60, 3,
-	2, 2, 2, 74, 63, 3, 2, 2, 2, 74, 66, 3, 2, 2, 2, 74, 71, 3, 2, 2, 2, 75,
-	78, 3, 2, 2, 2, 76, 74, 3, 2, 2, 2, 76, 77, 3, 2, 2, 2, 77, 3, 3, 2, 2,
-	2, 78, 76, 3, 2, 2, 2, 79, 87, 5, 10, 6, 2, 80, 87, 5, 6, 4, 2, 81, 87,
-	5, 8, 5, 2, 82, 83, 7, 30, 2, 2, 83, 84, 5, 2, 2, 2, 84, 85, 7, 31, 2,
-	2, 85, 87, 3, 2, 2, 2, 86, 79, 3, 2, 2, 2, 86, 80, 3, 2, 2, 2, 86, 81,
-	3, 2, 2, 2, 86, 82, 3, 2, 2, 2, 87, 5, 3, 2, 2, 2, 88, 89, 7, 32, 2, 2,
-	89, 98, 7, 33, 2, 2, 90, 98, 9, 10, 2, 2, 91, 98, 7, 62, 2, 2, 92, 98,
-	7, 63, 2, 2, 93, 98, 7, 57, 2, 2, 94, 98, 7, 58, 2, 2, 95, 98, 7, 59, 2,
-	2, 96, 98, 5, 16, 9, 2, 97, 88, 3, 2, 2, 2, 97, 90, 3, 2, 2, 2, 97, 91,
-	3, 2, 2, 2, 97, 92, 3, 2, 2, 2, 97, 93, 3, 2, 2, 2, 97, 94, 3, 2, 2, 2,
-	97, 95, 3, 2, 2, 2, 97, 96, 3, 2, 2, 2, 98, 7, 3, 2, 2, 2, 99, 102, 7,
-	36, 2, 2, 100, 103, 5, 28, 15, 2, 101, 103, 7, 62, 2, 2, 102, 100, 3, 2,
-	2, 2, 102, 101, 3, 2, 2, 2, 103, 9, 3, 2, 2, 2, 104, 110, 5, 28, 15, 2,
-	105, 110, 5, 12, 7, 2, 106, 110, 7, 37, 2, 2, 107, 110, 7, 38, 2, 2, 108,
-	110, 7, 39, 2, 2, 109, 104, 3, 2, 2, 2, 109, 105, 3, 2, 2, 2, 109, 106,
-	3, 2, 2, 2, 109, 107, 3, 2, 2, 2, 109, 108, 3, 2, 2, 2, 110, 11, 3, 2,
-	2, 2, 111, 112, 7, 14, 2, 2, 112, 113, 7, 30, 2, 2, 113, 114, 5, 24, 13,
-	2, 114, 115, 7, 31, 2, 2, 115, 129, 3, 2, 2, 2, 116, 117, 7, 13, 2, 2,
-	117, 118, 7, 30, 2, 2, 118, 119, 5, 24, 13, 2, 119, 120, 7, 31, 2, 2, 120,
-	129, 3, 2, 2, 2, 121, 122, 5, 28, 15, 2, 122, 124, 7, 30, 2, 2, 123, 125,
-	5, 14, 8, 2, 124, 123, 3, 2, 2, 2, 124, 125, 3, 2, 2, 2, 125, 126, 3, 2,
-	2, 2, 126, 127, 7, 31, 2, 2, 127, 129, 3, 2, 2, 2, 128, 111, 3, 2, 2, 2,
-	128, 116, 3, 2, 2, 2, 128, 121, 3, 2, 2, 2, 129, 13, 3, 2, 2, 2, 130, 135,
-	5, 2, 2, 2, 131, 132, 7, 40, 2, 2, 132, 134, 5, 2, 2, 2, 133, 131, 3, 2,
-	2, 2, 134, 137, 3, 2, 2, 2, 135, 133, 3, 2, 2, 2, 135, 136, 3, 2, 2, 2,
-	136, 15, 3, 2, 2, 2, 137, 135, 3, 2, 2, 2, 138, 140, 7, 63, 2, 2, 139,
-	141, 5, 18, 10, 2, 140, 139, 3, 2, 2, 2, 140, 141, 3, 2, 2, 2, 141, 17,
-	3, 2, 2, 2, 142, 146, 5, 20, 11, 2, 143, 146, 5, 22, 12, 2, 144, 146, 7,
-	62, 2, 2, 145, 142, 3, 2, 2, 2, 145, 143, 3, 2, 2, 2, 145, 144, 3, 2, 2,
-	2, 146, 19, 3, 2, 2, 2, 147, 148, 9, 11, 2, 2, 148, 21, 3, 2, 2, 2, 149,
-	150, 9, 12, 2, 2, 150, 23, 3, 2, 2, 2, 151, 152, 5, 26, 14, 2, 152, 25,
-	3, 2, 2, 2, 153, 158, 5, 28, 15, 2, 154, 155, 7, 3, 2, 2, 155, 157, 5,
-	28, 15, 2, 156, 154, 3, 2, 2, 2, 157, 160, 3, 2, 2, 2, 158, 156, 3, 2,
-	2, 2, 158, 159, 3, 2, 2, 2, 159, 27, 3, 2, 2, 2, 160, 158, 3, 2, 2, 2,
-	161, 162, 9, 13, 2, 2, 162, 29, 3, 2, 2, 2, 15, 34, 74, 76, 86, 97, 102,
-	109, 124, 128, 135, 140, 145, 158,
+	15, 3, 15, 5, 2, 165, 10, 2, 3, 2, 3, 2, 3, 2, 3, 4, 2, 3, 2, 16, 2, 4,
+	6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 2, 14, 3, 2, 6, 7, 3, 2,
+	8, 11, 4, 2, 6, 7, 12, 12, 3, 2, 16, 19, 3, 2, 20, 23, 3, 2, 24, 25, 3,
+	2, 27, 28, 3, 2, 13, 14, 3, 2, 35, 36, 3, 2, 42, 49, 3, 2, 50, 57, 5, 2,
+	13, 14, 24, 25, 61, 62, 2, 194, 2, 34, 3, 2, 2, 2, 4, 86, 3, 2, 2, 2, 6,
+	97, 3, 2, 2, 2, 8, 99, 3, 2, 2, 2, 10, 109, 3, 2, 2, 2, 12, 128, 3, 2,
+	2, 2, 14, 130, 3, 2, 2, 2, 16, 138, 3, 2, 2, 2, 18, 145, 3, 2, 2, 2, 20,
+	147, 3, 2, 2, 2, 22, 149, 3, 2, 2, 2, 24, 151, 3, 2, 2, 2, 26, 153, 3,
+	2, 2, 2, 28, 161, 3, 2, 2, 2, 30, 31, 8, 2, 1, 2, 31, 35, 5, 4, 3, 2, 32,
+	33, 9, 2, 2, 2, 33, 35, 5, 2, 2, 14, 34, 30, 3, 2, 2, 2, 34, 32, 3, 2,
+	2, 2, 34, 164, 3, 2, 2, 2, 35, 76, 3, 2, 2, 2, 36, 37, 12, 13, 2, 2, 37,
+	38, 9, 3, 2, 2, 38, 75, 5, 2, 2, 14, 39, 40, 12, 12, 2, 2, 40, 41, 9, 4,
+	2, 2, 41, 75, 5, 2, 2, 13, 42, 43, 12, 10, 2, 2, 43, 44, 7, 15, 2, 2, 44,
+	75, 5, 2, 2, 11, 45, 46, 12, 9, 2, 2, 46, 47, 9, 5, 2, 2, 47, 75, 5, 2,
+	2, 10, 48, 49, 12, 8, 2, 2, 49, 50, 9, 6, 2, 2, 50, 75, 5, 2, 2, 9, 51,
+	52, 12, 7, 2, 2, 52, 53, 9, 7, 2, 2, 53, 75, 5, 2, 2, 8, 54, 55, 12, 6,
+	2, 2, 55, 56, 7, 26, 2, 2, 56, 75, 5, 2, 2, 7, 57, 58, 12, 5, 2, 2, 58,
+	59, 9, 8, 2, 2, 59, 75, 5, 2, 2, 6, 60, 61, 12, 4, 2, 2, 61, 62, 7, 29,
+	2, 2, 62, 75, 5, 2, 2, 5, 63, 64, 12, 16, 2, 2, 64, 65, 7, 3, 2, 2, 65,
+	75, 5, 10, 6, 2, 66, 67, 12, 15, 2, 2, 67, 68, 7, 4, 2, 2, 68, 69, 5, 2,
+	2, 2, 69, 70, 7, 5, 2, 2, 70, 75, 3, 2, 2, 2, 71, 72, 12, 11, 2, 2, 72,
+	73, 9, 9, 2, 2, 73, 75, 5, 24, 13, 2, 74, 36, 3, 2, 2, 2, 74, 39, 3, 2,
+	2, 2, 74, 42, 3, 2, 2, 2, 74, 45, 3, 2, 2, 2, 74, 48, 3, 2, 2, 2, 74, 51,
+	3, 2, 2, 2, 74, 54, 3, 2, 2, 2, 74, 57, 3, 2, 2, 2, 74, 60, 3, 2, 2, 2,
+	74, 63, 3, 2, 2, 2, 74, 66, 3, 2, 2, 2, 74, 71, 3, 2, 2, 2, 75, 78, 3,
+	2, 2, 2, 76, 74, 3, 2, 2, 2, 76, 77, 3, 2, 2, 2, 77, 3, 3, 2, 2, 2, 78,
+	76, 3, 2, 2, 2, 79, 87, 5, 10, 6, 2, 80, 87, 5, 6, 4, 2, 81, 87, 5, 8,
+	5, 2, 82, 83, 7, 31, 2, 2, 83, 84, 5, 2, 2, 2, 84, 85, 7, 32, 2, 2, 85,
+	87, 3, 2, 2, 2, 86, 79, 3, 2, 2, 2, 86, 80, 3, 2, 2, 2, 86, 81, 3, 2, 2,
+	2, 86, 82, 3, 2, 2, 2, 87, 5, 3, 2, 2, 2, 88, 89, 7, 33, 2, 2, 89, 98,
+	7, 34, 2, 2, 90, 98, 9, 10, 2, 2, 91, 98, 7, 63, 2, 2, 92, 98, 7, 64, 2,
+	2, 93, 98, 7, 58, 2, 2, 94, 98, 7, 59, 2, 2, 95, 98, 7, 60, 2, 2, 96, 98,
+	5, 16, 9, 2, 97, 88, 3, 2, 2, 2, 97, 90, 3, 2, 2, 2, 97, 91, 3, 2, 2, 2,
+	97, 92, 3, 2, 2, 2, 97, 169, 3, 2, 2, 2, 97, 93, 3, 2, 2, 2, 97, 94, 3,
+	2, 2, 2, 97, 95, 3, 2, 2, 2, 97, 96, 3, 2, 2, 2, 98, 7, 3, 2, 2, 2, 99,
+	102, 7, 37, 2, 2, 100, 103, 5, 28, 15, 2, 101, 103, 7, 63, 2, 2, 102, 100,
+	3, 2, 2, 2, 102, 101, 3, 2, 2, 2, 103, 9, 3, 2, 2, 2, 104, 110, 5, 28,
+	15, 2, 105, 110, 5, 12, 7, 2, 106, 110, 7, 38, 2, 2, 107, 110, 7, 39, 2,
+	2, 108, 110, 7, 40, 2, 2, 109, 104, 3, 2, 2, 2, 109, 105, 3, 2, 2, 2, 109,
+	106, 3, 2, 2, 2, 109, 107, 3, 2, 2, 2, 109, 108, 3, 2, 2, 2, 110, 11, 3,
+	2, 2, 2, 111, 112, 7, 14, 2, 2, 112, 113, 7, 31, 2, 2, 113, 114, 5, 24,
+	13, 2, 114, 115, 7, 32, 2, 2, 115, 129, 3, 2, 2, 2, 116, 117, 7, 13, 2,
+	2, 117, 118, 7, 31, 2, 2, 118, 119, 5, 24, 13, 2, 119, 120, 7, 32, 2, 2,
+	120, 129, 3, 2, 2, 2, 121, 122, 5, 28, 15, 2, 122, 124, 7, 31, 2, 2, 123,
+	125, 5, 14, 8, 2, 124, 123, 3, 2, 2, 2, 124, 125, 3, 2, 2, 2, 125, 126,
+	3, 2, 2, 2, 126, 127, 7, 32, 2, 2, 127, 129, 3, 2, 2, 2, 128, 111, 3, 2,
+	2, 2, 128, 116, 3, 2, 2, 2, 128, 121, 3, 2, 2, 2, 129, 13, 3, 2, 2, 2,
+	130, 135, 5, 2, 2, 2, 131, 132, 7, 41, 2, 2, 132, 134, 5, 2, 2, 2, 133,
+	131, 3, 2, 2, 2, 134, 137, 3, 2, 2, 2, 135, 133, 3, 2, 2, 2, 135, 136,
+	3, 2, 2, 2, 136, 15, 3, 2, 2, 2, 137, 135, 3, 2, 2, 2, 138, 140, 7, 64,
+	2, 2, 139, 141, 5, 18, 10, 2, 140, 139, 3, 2, 2, 2, 140, 141, 3, 2, 2,
+	2, 141, 17, 3, 2, 2, 2, 142, 146, 5, 20, 11, 2, 143, 146, 5, 22, 12, 2,
+	144, 146, 7, 63, 2, 2, 145, 142, 3, 2, 2, 2, 145, 143, 3, 2, 2, 2, 145,
+	144, 3, 2, 2, 2, 146, 19, 3, 2, 2, 2, 147, 148, 9, 11, 2, 2, 148, 21, 3,
+	2, 2, 2, 149, 150, 9, 12, 2, 2, 150, 23, 3, 2, 2, 2, 151, 152, 5, 26, 14,
+	2, 152, 25, 3, 2, 2, 2, 153, 158, 5, 28, 15, 2, 154, 155, 7, 3, 2, 2, 155,
+	157, 5, 28, 15, 2, 156, 154, 3, 2, 2, 2, 157, 160, 3, 2, 2, 2, 158, 156,
+	3, 2, 2, 2, 158, 159, 3, 2, 2, 2, 159, 27, 3, 2, 2, 2, 160, 158, 3, 2,
+	2, 2, 161, 162, 9, 13, 2, 2, 162, 29, 3, 2, 2, 2, 164, 166, 3, 2, 2, 2,
+	164, 165, 3, 2, 2, 2, 166, 165, 7, 61, 2, 2, 165, 167, 3, 2, 2, 2, 167,
+	168, 7, 30, 2, 2, 168, 35, 5, 2, 2, 3, 169, 98, 7, 65, 2, 2, 16, 34, 74,
+	76, 86, 97, 102, 109, 124, 128, 135, 140, 145, 158, 164,
 }
 var literalNames = []string{
 	"", "'.'", "'['", "']'", "'+'", "'-'", "'*'", "'/'", "'div'", "'mod'",
 	"'&'", "'is'", "'as'", "'|'", "'<='", "'<'", "'>'", "'>='", "'='", "'~'",
 	"'!='", "'!~'", "'in'", "'contains'", "'and'", "'or'", "'xor'", "'implies'",
-	"'('", "')'", "'{'", "'}'", "'true'", "'false'", "'%'", "'$this'", "'$index'",
-	"'$total'", "','", "'year'", "'month'", "'week'", "'day'", "'hour'", "'minute'",
-	"'second'", "'millisecond'", "'years'", "'months'", "'weeks'", "'days'",
-	"'hours'", "'minutes'", "'seconds'", "'milliseconds'",
+	"'=>'", "'('", "')'", "'{'", "'}'", "'true'", "'false'", "'%'", "'$this'",
+	"'$index'", "'$total'", "','", "'year'", "'month'", "'week'", "'day'",
+	"'hour'", "'minute'", "'second'", "'millisecond'", "'years'", "'months'",
+	"'weeks'", "'days'", "'hours'", "'minutes'", "'seconds'", "'milliseconds'",
 }
 var symbolicNames = []string{
 	"", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "",
 	"", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "",
 	"", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "", "",
-	"", "DATE", "DATETIME", "TIME", "IDENTIFIER", "DELIMITEDIDENTIFIER", "STRING",
-	"NUMBER", "WS", "COMMENT", "LINE_COMMENT",
+	"", "", "DATE", "DATETIME", "TIME", "IDENTIFIER", "DELIMITEDIDENTIFIER",
+	"STRING", "NUMBER", "LONGNUMBER", "WS", "COMMENT", "LINE_COMMENT",
 }
 
 var ruleNames = []string{
@@ -200,16 +203,18 @@ const (
 	FHIRPathParserT__51               = 52
 	FHIRPathParserT__52               = 53
 	FHIRPathParserT__53               = 54
-	FHIRPathParserDATE                = 55
-	FHIRPathParserDATETIME            = 56
-	FHIRPathParserTIME                = 57
-	FHIRPathParserIDENTIFIER          = 58
-	FHIRPathParserDELIMITEDIDENTIFIER = 59
-	FHIRPathParserSTRING              = 60
-	FHIRPathParserNUMBER              = 61
-	FHIRPathParserWS                  = 62
-	FHIRPathParserCOMMENT             = 63
-	FHIRPathParserLINE_COMMENT        = 64
+	FHIRPathParserT__54               = 55
+	FHIRPathParserDATE                = 56
+	FHIRPathParserDATETIME            = 57
+	FHIRPathParserTIME                = 58
+	FHIRPathParserIDENTIFIER          = 59
+	FHIRPathParserDELIMITEDIDENTIFIER = 60
+	FHIRPathParserSTRING              = 61
+	FHIRPathParserNUMBER              = 62
+	FHIRPathParserLONGNUMBER          = 63
+	FHIRPathParserWS                  = 64
+	FHIRPathParserCOMMENT             = 65
+	FHIRPathParserLINE_COMMENT        = 66
 )
 
 // FHIRPathParser rules.
@@ -962,6 +967,48 @@ func (s *TypeExpressionContext) Accept(visitor antlr.ParseTreeVisitor) interface
 	}
 }
 
+type LambdaExpressionContext struct {
+	*ExpressionContext
+}
+
+func NewLambdaExpressionContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *LambdaExpressionContext {
+	var p = new(LambdaExpressionContext)
+
+	p.ExpressionContext = NewEmptyExpressionContext()
+	p.parser = parser
+	p.CopyFrom(ctx.(*ExpressionContext))
+
+	return p
+}
+
+func (s *LambdaExpressionContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *LambdaExpressionContext) Expression() IExpressionContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IExpressionContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IExpressionContext)
+}
+
+func (s *LambdaExpressionContext) IDENTIFIER() antlr.TerminalNode {
+	return s.GetToken(FHIRPathParserIDENTIFIER, 0)
+}
+
+func (s *LambdaExpressionContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case FHIRPathVisitor:
+		return t.VisitLambdaExpression(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
 func (p *FHIRPathParser) Expression() (localctx IExpressionContext) {
 	return p.expression(0)
 }
@@ -997,9 +1044,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 	p.EnterOuterAlt(localctx, 1)
 	p.SetState(32)
 	p.GetErrorHandler().Sync(p)
-
-	switch p.GetTokenStream().LA(1) {
-	case FHIRPathParserT__10, FHIRPathParserT__11, FHIRPathParserT__21, FHIRPathParserT__22, FHIRPathParserT__27, FHIRPathParserT__29, FHIRPathParserT__31, FHIRPathParserT__32, FHIRPathParserT__33, FHIRPathParserT__34, FHIRPathParserT__35, FHIRPathParserT__36, FHIRPathParserDATE, FHIRPathParserDATETIME, FHIRPathParserTIME, FHIRPathParserIDENTIFIER, FHIRPathParserDELIMITEDIDENTIFIER, FHIRPathParserSTRING, FHIRPathParserNUMBER:
+	switch p.GetInterpreter().AdaptivePredict(p.GetTokenStream(), 0, p.GetParserRuleContext()) {
+	case 1:
 		localctx = NewTermExpressionContext(p, localctx)
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
@@ -1009,7 +1055,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 			p.Term()
 		}
 
-	case FHIRPathParserT__3, FHIRPathParserT__4:
+	case 2:
 		localctx = NewPolarityExpressionContext(p, localctx)
 		p.SetParserRuleContext(localctx)
 		_prevctx = localctx
@@ -1026,11 +1072,33 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 		}
 		{
 			p.SetState(31)
-			p.expression(11)
+			p.expression(12)
+		}
+
+	case 3:
+		localctx = NewLambdaExpressionContext(p, localctx)
+		p.SetParserRuleContext(localctx)
+		_prevctx = localctx
+		p.SetState(162)
+		p.GetErrorHandler().Sync(p)
+		_la = p.GetTokenStream().LA(1)
+
+		if _la == FHIRPathParserIDENTIFIER {
+			{
+				p.SetState(164)
+				p.Match(FHIRPathParserIDENTIFIER)
+			}
+
+		}
+		{
+			p.SetState(165)
+			p.Match(FHIRPathParserT__27)
+		}
+		{
+			p.SetState(166)
+			p.expression(1)
 		}
 
-	default:
-		panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
 	}
 	p.GetParserRuleContext().SetStop(p.GetTokenStream().LT(-1))
 	p.SetState(74)
@@ -1051,8 +1119,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(34)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 10)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 10)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 11)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 11)", ""))
 				}
 				{
 					p.SetState(35)
@@ -1067,7 +1135,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(36)
-					p.expression(11)
+					p.expression(12)
 				}
 
 			case 2:
@@ -1075,8 +1143,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(37)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 9)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 9)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 10)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 10)", ""))
 				}
 				{
 					p.SetState(38)
@@ -1091,7 +1159,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(39)
-					p.expression(10)
+					p.expression(11)
 				}
 
 			case 3:
@@ -1099,8 +1167,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(40)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 7)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 7)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 8)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 8)", ""))
 				}
 				{
 					p.SetState(41)
@@ -1108,7 +1176,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(42)
-					p.expression(8)
+					p.expression(9)
 				}
 
 			case 4:
@@ -1116,8 +1184,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(43)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 6)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 6)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 7)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 7)", ""))
 				}
 				{
 					p.SetState(44)
@@ -1132,7 +1200,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(45)
-					p.expression(7)
+					p.expression(8)
 				}
 
 			case 5:
@@ -1140,8 +1208,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(46)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 5)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 5)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 6)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 6)", ""))
 				}
 				{
 					p.SetState(47)
@@ -1156,7 +1224,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(48)
-					p.expression(6)
+					p.expression(7)
 				}
 
 			case 6:
@@ -1164,8 +1232,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(49)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 5)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 5)", ""))
 				}
 				{
 					p.SetState(50)
@@ -1180,7 +1248,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(51)
-					p.expression(5)
+					p.expression(6)
 				}
 
 			case 7:
@@ -1188,8 +1256,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(52)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 4)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 4)", ""))
 				}
 				{
 					p.SetState(53)
@@ -1197,7 +1265,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(54)
-					p.expression(4)
+					p.expression(5)
 				}
 
 			case 8:
@@ -1205,8 +1273,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(55)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 3)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 3)", ""))
 				}
 				{
 					p.SetState(56)
@@ -1221,7 +1289,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(57)
-					p.expression(3)
+					p.expression(4)
 				}
 
 			case 9:
@@ -1229,8 +1297,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(58)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 1)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 1)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 2)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 2)", ""))
 				}
 				{
 					p.SetState(59)
@@ -1238,7 +1306,7 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				}
 				{
 					p.SetState(60)
-					p.expression(2)
+					p.expression(3)
 				}
 
 			case 10:
@@ -1246,8 +1314,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(61)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 13)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 13)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 14)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 14)", ""))
 				}
 				{
 					p.SetState(62)
@@ -1263,8 +1331,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(64)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 12)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 12)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 13)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 13)", ""))
 				}
 				{
 					p.SetState(65)
@@ -1284,8 +1352,8 @@ func (p *FHIRPathParser) expression(_p int) (localctx IExpressionContext) {
 				p.PushNewRecursionContext(localctx, _startState, FHIRPathParserRULE_expression)
 				p.SetState(69)
 
-				if !(p.Precpred(p.GetParserRuleContext(), 8)) {
-					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 8)", ""))
+				if !(p.Precpred(p.GetParserRuleContext(), 9)) {
+					panic(antlr.NewFailedPredicateException(p, "p.Precpred(p.GetParserRuleContext(), 9)", ""))
 				}
 				{
 					p.SetState(70)
@@ -1540,7 +1608,7 @@ func (p *FHIRPathParser) Term() (localctx ITermContext) {
 	p.GetErrorHandler().Sync(p)
 
 	switch p.GetTokenStream().LA(1) {
-	case FHIRPathParserT__10, FHIRPathParserT__11, FHIRPathParserT__21, FHIRPathParserT__22, FHIRPathParserT__34, FHIRPathParserT__35, FHIRPathParserT__36, FHIRPathParserIDENTIFIER, FHIRPathParserDELIMITEDIDENTIFIER:
+	case FHIRPathParserT__10, FHIRPathParserT__11, FHIRPathParserT__21, FHIRPathParserT__22, FHIRPathParserT__35, FHIRPathParserT__36, FHIRPathParserT__37, FHIRPathParserIDENTIFIER, FHIRPathParserDELIMITEDIDENTIFIER:
 		localctx = NewInvocationTermContext(p, localctx)
 		p.EnterOuterAlt(localctx, 1)
 		{
@@ -1548,7 +1616,7 @@ func (p *FHIRPathParser) Term() (localctx ITermContext) {
 			p.Invocation()
 		}
 
-	case FHIRPathParserT__29, FHIRPathParserT__31, FHIRPathParserT__32, FHIRPathParserDATE, FHIRPathParserDATETIME, FHIRPathParserTIME, FHIRPathParserSTRING, FHIRPathParserNUMBER:
+	case FHIRPathParserT__30, FHIRPathParserT__32, FHIRPathParserT__33, FHIRPathParserDATE, FHIRPathParserDATETIME, FHIRPathParserTIME, FHIRPathParserSTRING, FHIRPathParserNUMBER, FHIRPathParserLONGNUMBER:
 		localctx = NewLiteralTermContext(p, localctx)
 		p.EnterOuterAlt(localctx, 2)
 		{
@@ -1556,7 +1624,7 @@ func (p *FHIRPathParser) Term() (localctx ITermContext) {
 			p.Literal()
 		}
 
-	case FHIRPathParserT__33:
+	case FHIRPathParserT__34:
 		localctx = NewExternalConstantTermContext(p, localctx)
 		p.EnterOuterAlt(localctx, 3)
 		{
@@ -1564,12 +1632,12 @@ func (p *FHIRPathParser) Term() (localctx ITermContext) {
 			p.ExternalConstant()
 		}
 
-	case FHIRPathParserT__27:
+	case FHIRPathParserT__28:
 		localctx = NewParenthesizedTermContext(p, localctx)
 		p.EnterOuterAlt(localctx, 4)
 		{
 			p.SetState(80)
-			p.Match(FHIRPathParserT__27)
+			p.Match(FHIRPathParserT__28)
 		}
 		{
 			p.SetState(81)
@@ -1577,7 +1645,7 @@ func (p *FHIRPathParser) Term() (localctx ITermContext) {
 		}
 		{
 			p.SetState(82)
-			p.Match(FHIRPathParserT__28)
+			p.Match(FHIRPathParserT__29)
 		}
 
 	default:
@@ -1821,6 +1889,38 @@ func (s *BooleanLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface
 	}
 }
 
+type LongNumberLiteralContext struct {
+	*LiteralContext
+}
+
+func NewLongNumberLiteralContext(parser antlr.Parser, ctx antlr.ParserRuleContext) *LongNumberLiteralContext {
+	var p = new(LongNumberLiteralContext)
+
+	p.LiteralContext = NewEmptyLiteralContext()
+	p.parser = parser
+	p.CopyFrom(ctx.(*LiteralContext))
+
+	return p
+}
+
+func (s *LongNumberLiteralContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *LongNumberLiteralContext) LONGNUMBER() antlr.TerminalNode {
+	return s.GetToken(FHIRPathParserLONGNUMBER, 0)
+}
+
+func (s *LongNumberLiteralContext) Accept(visitor antlr.ParseTreeVisitor) interface{} {
+	switch t := visitor.(type) {
+	case FHIRPathVisitor:
+		return t.VisitLongNumberLiteral(s)
+
+	default:
+		return t.VisitChildren(s)
+	}
+}
+
 type NumberLiteralContext struct {
 	*LiteralContext
 }
@@ -1920,11 +2020,11 @@ func (p *FHIRPathParser) Literal() (localctx ILiteralContext) {
 		p.EnterOuterAlt(localctx, 1)
 		{
 			p.SetState(86)
-			p.Match(FHIRPathParserT__29)
+			p.Match(FHIRPathParserT__30)
 		}
 		{
 			p.SetState(87)
-			p.Match(FHIRPathParserT__30)
+			p.Match(FHIRPathParserT__31)
 		}
 
 	case 2:
@@ -1934,7 +2034,7 @@ func (p *FHIRPathParser) Literal() (localctx ILiteralContext) {
 			p.SetState(88)
 			_la = p.GetTokenStream().LA(1)
 
-			if !(_la == FHIRPathParserT__31 || _la == FHIRPathParserT__32) {
+			if !(_la == FHIRPathParserT__32 || _la == FHIRPathParserT__33) {
 				p.GetErrorHandler().RecoverInline(p)
 			} else {
 				p.GetErrorHandler().ReportMatch(p)
@@ -1959,32 +2059,40 @@ func (p *FHIRPathParser) Literal() (localctx ILiteralContext) {
 		}
 
 	case 5:
-		localctx = NewDateLiteralContext(p, localctx)
+		localctx = NewLongNumberLiteralContext(p, localctx)
 		p.EnterOuterAlt(localctx, 5)
+		{
+			p.SetState(167)
+			p.Match(FHIRPathParserLONGNUMBER)
+		}
+
+	case 6:
+		localctx = NewDateLiteralContext(p, localctx)
+		p.EnterOuterAlt(localctx, 6)
 		{
 			p.SetState(91)
 			p.Match(FHIRPathParserDATE)
 		}
 
-	case 6:
+	case 7:
 		localctx = NewDateTimeLiteralContext(p, localctx)
-		p.EnterOuterAlt(localctx, 6)
+		p.EnterOuterAlt(localctx, 7)
 		{
 			p.SetState(92)
 			p.Match(FHIRPathParserDATETIME)
 		}
 
-	case 7:
+	case 8:
 		localctx = NewTimeLiteralContext(p, localctx)
-		p.EnterOuterAlt(localctx, 7)
+		p.EnterOuterAlt(localctx, 8)
 		{
 			p.SetState(93)
 			p.Match(FHIRPathParserTIME)
 		}
 
-	case 8:
+	case 9:
 		localctx = NewQuantityLiteralContext(p, localctx)
-		p.EnterOuterAlt(localctx, 8)
+		p.EnterOuterAlt(localctx, 9)
 		{
 			p.SetState(94)
 			p.Quantity()
@@ -2088,7 +2196,7 @@ func (p *FHIRPathParser) ExternalConstant() (localctx IExternalConstantContext)
 	p.EnterOuterAlt(localctx, 1)
 	{
 		p.SetState(97)
-		p.Match(FHIRPathParserT__33)
+		p.Match(FHIRPathParserT__34)
 	}
 	p.SetState(100)
 	p.GetErrorHandler().Sync(p)
@@ -2367,7 +2475,7 @@ func (p *FHIRPathParser) Invocation() (localctx IInvocationContext) {
 		p.EnterOuterAlt(localctx, 3)
 		{
 			p.SetState(104)
-			p.Match(FHIRPathParserT__34)
+			p.Match(FHIRPathParserT__35)
 		}
 
 	case 4:
@@ -2375,7 +2483,7 @@ func (p *FHIRPathParser) Invocation() (localctx IInvocationContext) {
 		p.EnterOuterAlt(localctx, 4)
 		{
 			p.SetState(105)
-			p.Match(FHIRPathParserT__35)
+			p.Match(FHIRPathParserT__36)
 		}
 
 	case 5:
@@ -2383,7 +2491,7 @@ func (p *FHIRPathParser) Invocation() (localctx IInvocationContext) {
 		p.EnterOuterAlt(localctx, 5)
 		{
 			p.SetState(106)
-			p.Match(FHIRPathParserT__36)
+			p.Match(FHIRPathParserT__37)
 		}
 
 	}
@@ -2509,7 +2617,7 @@ func (p *FHIRPathParser) Function() (localctx IFunctionContext) {
 		}
 		{
 			p.SetState(110)
-			p.Match(FHIRPathParserT__27)
+			p.Match(FHIRPathParserT__28)
 		}
 		{
 			p.SetState(111)
@@ -2517,7 +2625,7 @@ func (p *FHIRPathParser) Function() (localctx IFunctionContext) {
 		}
 		{
 			p.SetState(112)
-			p.Match(FHIRPathParserT__28)
+			p.Match(FHIRPathParserT__29)
 		}
 
 	case 2:
@@ -2528,7 +2636,7 @@ func (p *FHIRPathParser) Function() (localctx IFunctionContext) {
 		}
 		{
 			p.SetState(115)
-			p.Match(FHIRPathParserT__27)
+			p.Match(FHIRPathParserT__28)
 		}
 		{
 			p.SetState(116)
@@ -2536,7 +2644,7 @@ func (p *FHIRPathParser) Function() (localctx IFunctionContext) {
 		}
 		{
 			p.SetState(117)
-			p.Match(FHIRPathParserT__28)
+			p.Match(FHIRPathParserT__29)
 		}
 
 	case 3:
@@ -2547,13 +2655,13 @@ func (p *FHIRPathParser) Function() (localctx IFunctionContext) {
 		}
 		{
 			p.SetState(120)
-			p.Match(FHIRPathParserT__27)
+			p.Match(FHIRPathParserT__28)
 		}
 		p.SetState(122)
 		p.GetErrorHandler().Sync(p)
 		_la = p.GetTokenStream().LA(1)
 
-		if (((_la)&-(0x1f+1)) == 0 && ((1<<uint(_la))&((1<<FHIRPathParserT__3)|(1<<FHIRPathParserT__4)|(1<<FHIRPathParserT__10)|(1<<FHIRPathParserT__11)|(1<<FHIRPathParserT__21)|(1<<FHIRPathParserT__22)|(1<<FHIRPathParserT__27)|(1<<FHIRPathParserT__29))) != 0) || (((_la-32)&-(0x1f+1)) == 0 && ((1<<uint((_la-32)))&((1<<(FHIRPathParserT__31-32))|(1<<(FHIRPathParserT__32-32))|(1<<(FHIRPathParserT__33-32))|(1<<(FHIRPathParserT__34-32))|(1<<(FHIRPathParserT__35-32))|(1<<(FHIRPathParserT__36-32))|(1<<(FHIRPathParserDATE-32))|(1<<(FHIRPathParserDATETIME-32))|(1<<(FHIRPathParserTIME-32))|(1<<(FHIRPathParserIDENTIFIER-32))|(1<<(FHIRPathParserDELIMITEDIDENTIFIER-32))|(1<<(FHIRPathParserSTRING-32))|(1<<(FHIRPathParserNUMBER-32)))) != 0) {
+		if (((_la)&-(0x1f+1)) == 0 && ((1<<uint(_la))&((1<<FHIRPathParserT__3)|(1<<FHIRPathParserT__4)|(1<<FHIRPathParserT__10)|(1<<FHIRPathParserT__11)|(1<<FHIRPathParserT__21)|(1<<FHIRPathParserT__22)|(1<<FHIRPathParserT__27)|(1<<FHIRPathParserT__28)|(1<<FHIRPathParserT__30))) != 0) || (((_la-33)&-(0x1f+1)) == 0 && ((1<<uint((_la-33)))&((1<<(FHIRPathParserT__32-33))|(1<<(FHIRPathParserT__33-33))|(1<<(FHIRPathParserT__34-33))|(1<<(FHIRPathParserT__35-33))|(1<<(FHIRPathParserT__36-33))|(1<<(FHIRPathParserT__37-33))|(1<<(FHIRPathParserDATE-33))|(1<<(FHIRPathParserDATETIME-33))|(1<<(FHIRPathParserTIME-33))|(1<<(FHIRPathParserIDENTIFIER-33))|(1<<(FHIRPathParserDELIMITEDIDENTIFIER-33))|(1<<(FHIRPathParserSTRING-33))|(1<<(FHIRPathParserNUMBER-33))|(1<<(FHIRPathParserLONGNUMBER-33)))) != 0) {
 			{
 				p.SetState(121)
 				p.ParamList()
@@ -2562,7 +2670,7 @@ func (p *FHIRPathParser) Function() (localctx IFunctionContext) {
 		}
 		{
 			p.SetState(124)
-			p.Match(FHIRPathParserT__28)
+			p.Match(FHIRPathParserT__29)
 		}
 
 	}
@@ -2679,10 +2787,10 @@ func (p *FHIRPathParser) ParamList() (localctx IParamListContext) {
 	p.GetErrorHandler().Sync(p)
 	_la = p.GetTokenStream().LA(1)
 
-	for _la == FHIRPathParserT__37 {
+	for _la == FHIRPathParserT__38 {
 		{
 			p.SetState(129)
-			p.Match(FHIRPathParserT__37)
+			p.Match(FHIRPathParserT__38)
 		}
 		{
 			p.SetState(130)
@@ -2910,14 +3018,14 @@ func (p *FHIRPathParser) Unit() (localctx IUnitContext) {
 	p.GetErrorHandler().Sync(p)
 
 	switch p.GetTokenStream().LA(1) {
-	case FHIRPathParserT__38, FHIRPathParserT__39, FHIRPathParserT__40, FHIRPathParserT__41, FHIRPathParserT__42, FHIRPathParserT__43, FHIRPathParserT__44, FHIRPathParserT__45:
+	case FHIRPathParserT__39, FHIRPathParserT__40, FHIRPathParserT__41, FHIRPathParserT__42, FHIRPathParserT__43, FHIRPathParserT__44, FHIRPathParserT__45, FHIRPathParserT__46:
 		p.EnterOuterAlt(localctx, 1)
 		{
 			p.SetState(140)
 			p.DateTimePrecision()
 		}
 
-	case FHIRPathParserT__46, FHIRPathParserT__47, FHIRPathParserT__48, FHIRPathParserT__49, FHIRPathParserT__50, FHIRPathParserT__51, FHIRPathParserT__52, FHIRPathParserT__53:
+	case FHIRPathParserT__47, FHIRPathParserT__48, FHIRPathParserT__49, FHIRPathParserT__50, FHIRPathParserT__51, FHIRPathParserT__52, FHIRPathParserT__53, FHIRPathParserT__54:
 		p.EnterOuterAlt(localctx, 2)
 		{
 			p.SetState(141)
@@ -3019,7 +3127,7 @@ func (p *FHIRPathParser) DateTimePrecision() (localctx IDateTimePrecisionContext
 		p.SetState(145)
 		_la = p.GetTokenStream().LA(1)
 
-		if !(((_la-39)&-(0x1f+1)) == 0 && ((1<<uint((_la-39)))&((1<<(FHIRPathParserT__38-39))|(1<<(FHIRPathParserT__39-39))|(1<<(FHIRPathParserT__40-39))|(1<<(FHIRPathParserT__41-39))|(1<<(FHIRPathParserT__42-39))|(1<<(FHIRPathParserT__43-39))|(1<<(FHIRPathParserT__44-39))|(1<<(FHIRPathParserT__45-39)))) != 0) {
+		if !(((_la-40)&-(0x1f+1)) == 0 && ((1<<uint((_la-40)))&((1<<(FHIRPathParserT__39-40))|(1<<(FHIRPathParserT__40-40))|(1<<(FHIRPathParserT__41-40))|(1<<(FHIRPathParserT__42-40))|(1<<(FHIRPathParserT__43-40))|(1<<(FHIRPathParserT__44-40))|(1<<(FHIRPathParserT__45-40))|(1<<(FHIRPathParserT__46-40)))) != 0) {
 			p.GetErrorHandler().RecoverInline(p)
 		} else {
 			p.GetErrorHandler().ReportMatch(p)
@@ -3111,7 +3219,7 @@ func (p *FHIRPathParser) PluralDateTimePrecision() (localctx IPluralDateTimePrec
 		p.SetState(147)
 		_la = p.GetTokenStream().LA(1)
 
-		if !(((_la-47)&-(0x1f+1)) == 0 && ((1<<uint((_la-47)))&((1<<(FHIRPathParserT__46-47))|(1<<(FHIRPathParserT__47-47))|(1<<(FHIRPathParserT__48-47))|(1<<(FHIRPathParserT__49-47))|(1<<(FHIRPathParserT__50-47))|(1<<(FHIRPathParserT__51-47))|(1<<(FHIRPathParserT__52-47))|(1<<(FHIRPathParserT__53-47)))) != 0) {
+		if !(((_la-48)&-(0x1f+1)) == 0 && ((1<<uint((_la-48)))&((1<<(FHIRPathParserT__47-48))|(1<<(FHIRPathParserT__48-48))|(1<<(FHIRPathParserT__49-48))|(1<<(FHIRPathParserT__50-48))|(1<<(FHIRPathParserT__51-48))|(1<<(FHIRPathParserT__52-48))|(1<<(FHIRPathParserT__53-48))|(1<<(FHIRPathParserT__54-48)))) != 0) {
 			p.GetErrorHandler().RecoverInline(p)
 		} else {
 			p.GetErrorHandler().ReportMatch(p)
@@ -3465,40 +3573,40 @@ func (p *FHIRPathParser) Sempred(localctx antlr.RuleContext, ruleIndex, predInde
 func (p *FHIRPathParser) Expression_Sempred(localctx antlr.RuleContext, predIndex int) bool {
 	switch predIndex {
 	case 0:
-		return p.Precpred(p.GetParserRuleContext(), 10)
+		return p.Precpred(p.GetParserRuleContext(), 11)
 
 	case 1:
-		return p.Precpred(p.GetParserRuleContext(), 9)
+		return p.Precpred(p.GetParserRuleContext(), 10)
 
 	case 2:
-		return p.Precpred(p.GetParserRuleContext(), 7)
+		return p.Precpred(p.GetParserRuleContext(), 8)
 
 	case 3:
-		return p.Precpred(p.GetParserRuleContext(), 6)
+		return p.Precpred(p.GetParserRuleContext(), 7)
 
 	case 4:
-		return p.Precpred(p.GetParserRuleContext(), 5)
+		return p.Precpred(p.GetParserRuleContext(), 6)
 
 	case 5:
-		return p.Precpred(p.GetParserRuleContext(), 4)
+		return p.Precpred(p.GetParserRuleContext(), 5)
 
 	case 6:
-		return p.Precpred(p.GetParserRuleContext(), 3)
+		return p.Precpred(p.GetParserRuleContext(), 4)
 
 	case 7:
-		return p.Precpred(p.GetParserRuleContext(), 2)
+		return p.Precpred(p.GetParserRuleContext(), 3)
 
 	case 8:
-		return p.Precpred(p.GetParserRuleContext(), 1)
+		return p.Precpred(p.GetParserRuleContext(), 2)
 
 	case 9:
-		return p.Precpred(p.GetParserRuleContext(), 13)
+		return p.Precpred(p.GetParserRuleContext(), 14)
 
 	case 10:
-		return p.Precpred(p.GetParserRuleContext(), 12)
+		return p.Precpred(p.GetParserRuleContext(), 13)
 
 	case 11:
-		return p.Precpred(p.GetParserRuleContext(), 8)
+		return p.Precpred(p.GetParserRuleContext(), 9)
 
 	default:
 		panic("No predicate with index: " + fmt.Sprint(predIndex))
@@ -49,6 +49,9 @@ type FHIRPathVisitor interface {
 	// Visit a parse tree produced by FHIRPathParser#typeExpression.
 	VisitTypeExpression(ctx *TypeExpressionContext) interface{}
 
+	// Visit a parse tree produced by FHIRPathParser#lambdaExpression.
+	VisitLambdaExpression(ctx *LambdaExpressionContext) interface{}
+
 	// Visit a parse tree produced by FHIRPathParser#invocationTerm.
 	VisitInvocationTerm(ctx *InvocationTermContext) interface{}
 
@@ -73,6 +76,9 @@ type FHIRPathVisitor interface {
 	// Visit a parse tree produced by FHIRPathParser#numberLiteral.
 	VisitNumberLiteral(ctx *NumberLiteralContext) interface{}
 
+	// Visit a parse tree produced by FHIRPathParser#longNumberLiteral.
+	VisitLongNumberLiteral(ctx *LongNumberLiteralContext) interface{}
+
 	// Visit a parse tree produced by FHIRPathParser#dateLiteral.
 	VisitDateLiteral(ctx *DateLiteralContext) interface{}
 
@@ -42,6 +42,7 @@ func testParse(pathString string) (res interface{}, errorItemCollection *ErrorIt
 	errorItemCollection = NewErrorItemCollection()
 	v := NewVisitor(errorItemCollection)
 	res = p.Expression().Accept(v)
+	v.VerifyVariables()
 
 	return
 }
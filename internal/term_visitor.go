@@ -48,12 +48,13 @@ func (v *Visitor) VisitExternalConstantTerm(ctx *parser.ExternalConstantTermCont
 }
 
 func (v *Visitor) VisitExternalConstant(ctx *parser.ExternalConstantContext) interface{} {
-	return v.visitTree(ctx, 2, visitExternalConstant)
+	return v.visitTree(ctx, 2, v.visitExternalConstant)
 }
 
-func visitExternalConstant(_ antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
-	name := args[1].(string)
-	return expression.ParseExtConstantTerm(expression.ExtractIdentifier(name)), nil
+func (v *Visitor) visitExternalConstant(ctx antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
+	name := expression.ExtractIdentifier(args[1].(string))
+	v.useVariable(ctx, name)
+	return expression.ParseExtConstantTerm(name), nil
 }
 
 func (v *Visitor) VisitInvocationTerm(ctx *parser.InvocationTermContext) interface{} {
@@ -29,6 +29,7 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"github.com/healthiop/hipath/hipathsys"
 	"sort"
@@ -246,6 +247,16 @@ func NewTestContextWithNodeAndTracer(t *testing.T, node interface{}, tracer hipa
 	}
 }
 
+func NewCanceledTestContext(t *testing.T) hipathsys.ContextAccessor {
+	goCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return hipathsys.NewCancelableContext(NewTestContext(t), goCtx)
+}
+
+func NewLimitedTestContext(t *testing.T, limits hipathsys.Limits) hipathsys.ContextAccessor {
+	return hipathsys.NewLimitedContext(NewTestContext(t), limits)
+}
+
 func (t *testContext) EnvVar(name string) (interface{}, bool) {
 	if name == "ucum" {
 		return hipathsys.UCUMSystemURI, true
@@ -0,0 +1,135 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package internal
+
+import (
+	"fmt"
+	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/expression"
+	"github.com/healthiop/hipath/internal/parser"
+)
+
+const defineVariableFunctionName = "defineVariable"
+
+type variableUse struct {
+	name   string
+	line   int
+	column int
+}
+
+func (v *Visitor) VerifyVariables() {
+	for _, use := range v.variableUses {
+		if v.variables[use.name] {
+			v.errorItemCollection.AddError(use.line, use.column,
+				fmt.Sprintf("variable is used before it has been defined: %s", use.name))
+		}
+	}
+}
+
+func (v *Visitor) defineVariable(ctx *parser.FunctionContext) error {
+	name, ok := definedVariableName(ctx)
+	if !ok {
+		return fmt.Errorf("variable name must be a string literal")
+	}
+	if hipathsys.SystemEnvVarName(name) || variableInScope(ctx, name) {
+		return fmt.Errorf("variable has already been defined: %s", name)
+	}
+
+	if v.variables == nil {
+		v.variables = make(map[string]bool)
+	}
+	v.variables[name] = true
+	return nil
+}
+
+func (v *Visitor) useVariable(ctx antlr.ParserRuleContext, name string) {
+	if !variableInScope(ctx, name) {
+		v.variableUses = append(v.variableUses, variableUse{
+			name, ctx.GetStart().GetLine(), ctx.GetStart().GetColumn()})
+	}
+}
+
+func variableInScope(ctx antlr.Tree, name string) bool {
+	for c := ctx; c != nil; c = c.GetParent() {
+		if p, ok := c.GetParent().(*parser.InvocationExpressionContext); ok &&
+			antlr.Tree(p.Invocation()) == c && chainDefinesVariable(p.Expression(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+func chainDefinesVariable(ctx parser.IExpressionContext, name string) bool {
+	switch c := ctx.(type) {
+	case *parser.InvocationExpressionContext:
+		return invocationDefinesVariable(c.Invocation(), name) || chainDefinesVariable(c.Expression(), name)
+	case *parser.IndexerExpressionContext:
+		return chainDefinesVariable(c.Expression(0), name)
+	case *parser.TermExpressionContext:
+		if t, ok := c.Term().(*parser.InvocationTermContext); ok {
+			return invocationDefinesVariable(t.Invocation(), name)
+		}
+	}
+	return false
+}
+
+func invocationDefinesVariable(ctx parser.IInvocationContext, name string) bool {
+	fi, ok := ctx.(*parser.FunctionInvocationContext)
+	if !ok {
+		return false
+	}
+	f, ok := fi.Function().(*parser.FunctionContext)
+	if !ok || f.Identifier() == nil ||
+		expression.ExtractIdentifier(f.Identifier().GetText()) != defineVariableFunctionName {
+		return false
+	}
+	n, ok := definedVariableName(f)
+	return ok && n == name
+}
+
+func definedVariableName(ctx *parser.FunctionContext) (string, bool) {
+	paramList, ok := ctx.ParamList().(*parser.ParamListContext)
+	if !ok || len(paramList.AllExpression()) == 0 {
+		return "", false
+	}
+	term, ok := paramList.Expression(0).(*parser.TermExpressionContext)
+	if !ok {
+		return "", false
+	}
+	literalTerm, ok := term.Term().(*parser.LiteralTermContext)
+	if !ok {
+		return "", false
+	}
+	literal, ok := literalTerm.Literal().(*parser.StringLiteralContext)
+	if !ok {
+		return "", false
+	}
+	return expression.UnquoteStringLiteral(literal.GetText()), true
+}
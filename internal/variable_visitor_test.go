@@ -0,0 +1,107 @@
+// Copyright (c) 2020-2021, Volker Schmidt (volker@volsch.eu)
+// All rights reserved.
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions are met:
+//
+// 1. Redistributions of source code must retain the above copyright notice, this
+//    list of conditions and the following disclaimer.
+//
+// 2. Redistributions in binary form must reproduce the above copyright notice,
+//    this list of conditions and the following disclaimer in the documentation
+//    and/or other materials provided with the distribution.
+//
+// 3. Neither the name of the copyright holder nor the names of its
+//    contributors may be used to endorse or promote products derived from
+//    this software without specific prior written permission.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS"
+// AND ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE
+// DISCLAIMED. IN NO EVENT SHALL THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS OR
+// SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION) HOWEVER
+// CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT LIABILITY,
+// OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF THE USE
+// OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package internal
+
+import (
+	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseDefineVariable(t *testing.T) {
+	res, errorItemCollection := testParse("(10 | 14).defineVariable('v', first())[1].select(x => x + %v)")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+	if assert.Implements(t, (*hipathsys.Evaluator)(nil), res) {
+		ctx := test.NewTestContext(t)
+		res, err := res.(hipathsys.Evaluator).Evaluate(ctx, nil, nil)
+		assert.NoError(t, err, "no evaluation error expected")
+		if assert.Implements(t, (*hipathsys.ColAccessor)(nil), res) {
+			col := res.(hipathsys.ColAccessor)
+			if assert.Equal(t, 1, col.Count()) {
+				assert.Equal(t, hipathsys.NewInteger(24), col.Get(0))
+			}
+		}
+	}
+}
+
+func TestParseDefineVariableNested(t *testing.T) {
+	_, errorItemCollection := testParse("defineVariable('v', 1).select(defineVariable('w', %v).select(%w + %v))")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+}
+
+func TestParseDefineVariableNoLiteral(t *testing.T) {
+	_, errorItemCollection := testParse("defineVariable('v' & 'w')")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") &&
+		assert.True(t, errorItemCollection.HasErrors(), "errors expected") {
+		assert.Equal(t, "variable name must be a string literal", errorItemCollection.Items()[0].Msg())
+	}
+}
+
+func TestParseDefineVariableRedefined(t *testing.T) {
+	_, errorItemCollection := testParse("defineVariable('v').where(true).defineVariable('v')")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") &&
+		assert.True(t, errorItemCollection.HasErrors(), "errors expected") {
+		assert.Equal(t, "variable has already been defined: v", errorItemCollection.Items()[0].Msg())
+	}
+}
+
+func TestParseDefineVariableSelfReference(t *testing.T) {
+	_, errorItemCollection := testParse("defineVariable('v', %v)")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") &&
+		assert.True(t, errorItemCollection.HasErrors(), "errors expected") {
+		assert.Equal(t, "variable is used before it has been defined: v", errorItemCollection.Items()[0].Msg())
+	}
+}
+
+func TestParseDefineVariableUsedBefore(t *testing.T) {
+	_, errorItemCollection := testParse("%v.defineVariable('v')")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") &&
+		assert.True(t, errorItemCollection.HasErrors(), "errors expected") {
+		assert.Equal(t, "variable is used before it has been defined: v", errorItemCollection.Items()[0].Msg())
+	}
+}
+
+func TestParseUndefinedEnvVar(t *testing.T) {
+	_, errorItemCollection := testParse("%v.exists()")
+
+	if assert.NotNil(t, errorItemCollection, "error item collection must have been initialized") {
+		assert.False(t, errorItemCollection.HasErrors(), "no errors expected")
+	}
+}
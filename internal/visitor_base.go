@@ -31,12 +31,22 @@ package internal
 import (
 	"github.com/antlr/antlr4/runtime/Go/antlr"
 	"github.com/healthiop/hipath/hipathsys"
+	"github.com/healthiop/hipath/internal/expression"
 	"github.com/healthiop/hipath/internal/parser"
 )
 
 type Visitor struct {
 	parser.BaseFHIRPathVisitor
 	errorItemCollection *ErrorItemCollection
+	functions           *expression.FunctionRegistry
+	sourcePositions     bool
+	variables           map[string]bool
+	variableUses        []variableUse
+}
+
+type VisitorOptions struct {
+	Functions       *expression.FunctionRegistry
+	SourcePositions bool
 }
 
 type visitorFunc func(ctx antlr.ParserRuleContext) (hipathsys.Evaluator, error)
@@ -48,6 +58,13 @@ func NewVisitor(errorItemCollection *ErrorItemCollection) *Visitor {
 	return v
 }
 
+func NewVisitorWithOptions(errorItemCollection *ErrorItemCollection, options VisitorOptions) *Visitor {
+	v := NewVisitor(errorItemCollection)
+	v.functions = options.Functions
+	v.sourcePositions = options.SourcePositions
+	return v
+}
+
 func (v *Visitor) AddError(ctx antlr.ParserRuleContext, msg string) hipathsys.Evaluator {
 	v.errorItemCollection.AddError(ctx.GetStart().GetLine(), ctx.GetStart().GetColumn(), msg)
 	return nil
@@ -111,7 +128,18 @@ func (v *Visitor) visitTree(ctx antlr.ParserRuleContext, argCount int, f visitor
 
 	if l, err := f(ctx, args); err != nil {
 		return v.AddError(ctx, err.Error())
+	} else if v.sourcePositions && l != nil {
+		return expression.NewSourceExpression(l, ctx.GetStart().GetLine(),
+			ctx.GetStart().GetColumn(), sourceText(ctx))
 	} else {
 		return l
 	}
 }
+
+func sourceText(ctx antlr.ParserRuleContext) string {
+	start, stop := ctx.GetStart(), ctx.GetStop()
+	if start == nil || stop == nil || start.GetInputStream() == nil {
+		return ctx.GetText()
+	}
+	return start.GetInputStream().GetText(start.GetStart(), stop.GetStop())
+}
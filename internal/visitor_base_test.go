@@ -436,3 +436,21 @@ func (e otherNodeMock) GetChildCount() int {
 func (e otherNodeMock) GetChildren() []antlr.Tree {
 	panic("implement me")
 }
+
+func TestVisitorTreeSourcePositions(t *testing.T) {
+	res := expression.NewEmptyLiteral()
+	children := make([]antlr.Token, 1)
+	children[0] = newTokenMock(32, 2, "first")
+
+	ctx := newRuleContextWithChildren(81, 32, children)
+	c := NewErrorItemCollection()
+	v := NewVisitorWithOptions(c, VisitorOptions{SourcePositions: true})
+	r := v.visitTree(ctx, 1, func(ctx antlr.ParserRuleContext, args []interface{}) (hipathsys.Evaluator, error) {
+		return res, nil
+	})
+
+	assert.False(t, c.HasErrors(), "no errors expected")
+	if assert.IsType(t, (*expression.SourceExpression)(nil), r) {
+		assert.Same(t, res, r.(*expression.SourceExpression).Evaluator())
+	}
+}
@@ -29,55 +29,66 @@
 package gohipath
 
 import (
-	"github.com/antlr/antlr4/runtime/Go/antlr"
+	"context"
+	"errors"
 	"github.com/healthiop/hipath/hipathsys"
-	"github.com/healthiop/hipath/internal"
 	"github.com/healthiop/hipath/internal/expression"
-	"github.com/healthiop/hipath/internal/parser"
 )
 
 type Path struct {
 	evaluator expression.CollectionExpression
+	limits    *hipathsys.Limits
 }
 
 func Compile(pathString string) (*Path, *hipathsys.Error) {
-	errorItemCollection := internal.NewErrorItemCollection()
-	errorListener := internal.NewErrorListener(errorItemCollection)
-
-	is := antlr.NewInputStream(pathString)
-	lexer := parser.NewFHIRPathLexer(is)
-	lexer.RemoveErrorListeners()
-	lexer.AddErrorListener(errorListener)
-
-	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
-	p := parser.NewFHIRPathParser(stream)
-	p.RemoveErrorListeners()
-	p.AddErrorListener(errorListener)
-
-	v := internal.NewVisitor(errorItemCollection)
-	res := p.Expression().Accept(v)
+	return defaultCompiler.Compile(pathString)
+}
 
-	if errorItemCollection.HasErrors() {
-		return nil, hipathsys.NewError(
-			"error when parsing path expression", errorItemCollection.Items())
+func Execute(ctx hipathsys.ContextAccessor, pathString string, node interface{}) (hipathsys.ColAccessor, *hipathsys.Error) {
+	path, err := Compile(pathString)
+	if err != nil {
+		return nil, err
 	}
 
-	return &Path{expression.NewCollectionExpression(res.(hipathsys.Evaluator))}, nil
+	return path.Execute(ctx, node)
 }
 
-func Execute(ctx hipathsys.ContextAccessor, pathString string, node interface{}) (hipathsys.ColAccessor, *hipathsys.Error) {
+func ExecuteContext(goCtx context.Context, ctx hipathsys.ContextAccessor, pathString string, node interface{}) (hipathsys.ColAccessor, *hipathsys.Error) {
 	path, err := Compile(pathString)
 	if err != nil {
 		return nil, err
 	}
 
-	return path.Execute(ctx, node)
+	return path.ExecuteContext(goCtx, ctx, node)
 }
 
 func (p *Path) Execute(ctx hipathsys.ContextAccessor, node interface{}) (hipathsys.ColAccessor, *hipathsys.Error) {
+	return p.execute(ctx, node)
+}
+
+func (p *Path) ExecuteContext(goCtx context.Context, ctx hipathsys.ContextAccessor, node interface{}) (hipathsys.ColAccessor, *hipathsys.Error) {
+	return p.execute(hipathsys.NewCancelableContext(ctx, goCtx), node)
+}
+
+func (p *Path) execute(ctx hipathsys.ContextAccessor, node interface{}) (hipathsys.ColAccessor, *hipathsys.Error) {
+	ctx = hipathsys.NewSystemEnvVarContext(ctx, node)
+	ctx = hipathsys.NewReferenceCacheContext(ctx)
+	if p.limits != nil {
+		ctx = hipathsys.NewLimitedContext(ctx, *p.limits)
+	}
+
 	res, err := p.evaluator.Evaluate(ctx, node, nil)
+	if err == nil {
+		err = hipathsys.CheckCollectionSize(ctx, res.(hipathsys.ColAccessor).Count())
+	}
 	if err != nil {
-		return nil, hipathsys.NewError(err.Error(), nil)
+		var items []*hipathsys.ErrorItem
+		var sourceErr *hipathsys.SourceError
+		if errors.As(err, &sourceErr) {
+			items = []*hipathsys.ErrorItem{hipathsys.NewErrorItem(
+				sourceErr.Line(), sourceErr.Column(), err.Error())}
+		}
+		return nil, hipathsys.NewErrorWithCause(err.Error(), items, err)
 	}
 	return res.(hipathsys.ColAccessor), nil
 }
@@ -29,9 +29,12 @@
 package gohipath
 
 import (
+	"context"
+	"errors"
 	"github.com/healthiop/hipath/hipathsys"
 	"github.com/healthiop/hipath/internal/test"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
 )
 
@@ -112,7 +115,589 @@ func TestExecuteErrorExecute(t *testing.T) {
 	ctx := test.NewTestContext(t)
 	res, err := Execute(ctx, "union('value2' | 'value8').single()", nil)
 	if assert.NotNil(t, err, "error expected") {
-		assert.Nil(t, err.Items(), "no items expected")
+		assert.ErrorIs(t, err, hipathsys.ErrSingletonExpected)
+		if assert.Len(t, err.Items(), 1) {
+			assert.Equal(t, 1, err.Items()[0].Line())
+			assert.Equal(t, 27, err.Items()[0].Column())
+			assert.Equal(t, err.Error(), err.Items()[0].Msg())
+		}
+		var sourceErr *hipathsys.SourceError
+		if assert.True(t, errors.As(err, &sourceErr)) {
+			assert.Equal(t, "single()", sourceErr.Source())
+		}
 	}
 	assert.Nil(t, res, "no result expected")
 }
+
+func TestExecuteShortCircuit(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "('a' | 'b').count() = 1 and ('a' | 'b').single() = 'a'", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.False, res.Get(0))
+	}
+}
+
+func TestExecuteLazyIIf(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "iif(('a' | 'b').count() > 1, 'multiple', ('a' | 'b').single())", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("multiple"), res.Get(0))
+	}
+}
+
+func TestExecuteErrorPosition(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "true and\n  (1 * 'a') > 0", nil)
+	if assert.NotNil(t, err, "error expected") {
+		assert.ErrorIs(t, err, hipathsys.ErrTypeMismatch)
+		if assert.Len(t, err.Items(), 1) {
+			assert.Equal(t, 2, err.Items()[0].Line())
+			assert.Equal(t, 3, err.Items()[0].Column())
+		}
+		var sourceErr *hipathsys.SourceError
+		if assert.True(t, errors.As(err, &sourceErr)) {
+			assert.Equal(t, "1 * 'a'", sourceErr.Source())
+		}
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteErrorUnknownEnvVar(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "%undefined.exists()", nil)
+	if assert.NotNil(t, err, "error expected") {
+		assert.ErrorIs(t, err, hipathsys.ErrUnknownEnvVar)
+		assert.Equal(t, hipathsys.UnknownEnvVarErrorKind, hipathsys.ErrorKindOf(err))
+		if assert.Len(t, err.Items(), 1) {
+			assert.Equal(t, 0, err.Items()[0].Column())
+		}
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteLoopFunctionWithoutArgs(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(1 | 2).where((3 | 4).exists())", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") {
+		assert.Equal(t, 2, res.Count())
+	}
+}
+
+func TestExecuteLambda(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(1 | 2 | 3).where(x => (2 | 3 | 4).where(y => y = x + 1).exists())", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewInteger(1), res.Get(0))
+		assert.Equal(t, hipathsys.NewInteger(3), res.Get(2))
+	}
+}
+
+func TestExecuteLambdaOutsideFunction(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(x => x)", nil)
+	if assert.NotNil(t, err, "error expected") && assert.Len(t, err.Items(), 1) {
+		assert.Equal(t, "lambda expression is only supported as function parameter", err.Items()[0].Msg())
+		assert.Equal(t, 1, err.Items()[0].Column())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteContextBuilder(t *testing.T) {
+	adapter := test.NewTestContext(t).ModelAdapter()
+	ctx := hipathsys.NewContextBuilder(adapter).
+		Node(hipathsys.NewString("test")).
+		EnvVar("suffix", hipathsys.NewString("!")).
+		Build()
+	res, err := Execute(ctx, "%context & %suffix & ' ' & %ucum", ctx.ContextNode())
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("test! http://unitsofmeasure.org"), res.Get(0))
+	}
+}
+
+func TestExecuteContext(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := ExecuteContext(context.Background(), ctx, "(1 | 2 | 3).where($this > 1)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") {
+		assert.Equal(t, 2, res.Count())
+	}
+}
+
+func TestExecuteContextCompileError(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := ExecuteContext(context.Background(), ctx, "xxx$#@yyy", nil)
+	assert.NotNil(t, err, "error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteContextCanceled(t *testing.T) {
+	goCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx := test.NewTestContext(t)
+	res, err := ExecuteContext(goCtx, ctx, "(1 | 2 | 3).where($this > 1)", nil)
+	if assert.NotNil(t, err, "error expected") {
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Equal(t, "evaluation has been canceled: context canceled", err.Error())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteContextDeadlineExceeded(t *testing.T) {
+	goCtx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	ctx := test.NewTestContext(t)
+	res, err := ExecuteContext(goCtx, ctx, "(1 | 2 | 3).select($this + 1).aggregate($total + $this, 0)", nil)
+	if assert.NotNil(t, err, "error expected") {
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteErrorCause(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "%undefined", nil)
+	if assert.NotNil(t, err, "error expected") {
+		assert.NotNil(t, err.Unwrap(), "cause expected")
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteDefineVariable(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(1 | 2).defineVariable('v', count()).select($this + %v).where(x => x > %v)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 2, res.Count()) {
+		assert.Equal(t, hipathsys.NewInteger(3), res.Get(0))
+		assert.Equal(t, hipathsys.NewInteger(4), res.Get(1))
+	}
+}
+
+func TestExecuteDefineVariableInput(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "'a'.defineVariable('v').defineVariable('w', %v & 'b').select(%w & %v)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("aba"), res.Get(0))
+	}
+}
+
+func TestExecuteDefineVariableNotInScope(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(1 | 2).where(defineVariable('v', 1).exists()) | %v", nil)
+	if assert.NotNil(t, err, "error expected") && assert.Len(t, err.Items(), 1) {
+		assert.Equal(t, "variable is used before it has been defined: v", err.Items()[0].Msg())
+		assert.Equal(t, 49, err.Items()[0].Column())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteDefineVariableRedefined(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "defineVariable('v', 1).select(defineVariable('v', 2))", nil)
+	if assert.NotNil(t, err, "error expected") && assert.Len(t, err.Items(), 1) {
+		assert.Equal(t, "variable has already been defined: v", err.Items()[0].Msg())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteDefineVariableSystem(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "defineVariable('context', 1)", nil)
+	if assert.NotNil(t, err, "error expected") && assert.Len(t, err.Items(), 1) {
+		assert.Equal(t, "variable has already been defined: context", err.Items()[0].Msg())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteDefineVariableEnvVar(t *testing.T) {
+	adapter := test.NewTestContext(t).ModelAdapter()
+	ctx := hipathsys.NewContextBuilder(adapter).
+		EnvVar("v", hipathsys.NewString("test")).
+		Build()
+	res, err := Execute(ctx, "defineVariable('v', 1)", nil)
+	if assert.NotNil(t, err, "error expected") {
+		assert.Equal(t, "variable has already been defined: v", err.Error())
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteLong(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(8000000000L + 2) | (2 * 3L) | (1.5 + 1L) | 8000000000L.is(System.Long)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 4, res.Count()) {
+		if assert.Implements(t, (*hipathsys.LongAccessor)(nil), res.Get(0)) {
+			assert.Equal(t, int64(8_000_000_002), res.Get(0).(hipathsys.LongAccessor).Int64())
+		}
+		if assert.Implements(t, (*hipathsys.LongAccessor)(nil), res.Get(1)) {
+			assert.Equal(t, int64(6), res.Get(1).(hipathsys.LongAccessor).Int64())
+		}
+		if assert.Implements(t, (*hipathsys.DecimalAccessor)(nil), res.Get(2)) {
+			assert.Equal(t, 2.5, res.Get(2).(hipathsys.DecimalAccessor).Float64())
+		}
+		assert.Equal(t, hipathsys.True, res.Get(3))
+	}
+}
+
+func TestExecuteNumberOverflow(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(9223372036854775807L + 1L) | (2147483647 * 2)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") {
+		assert.Equal(t, 0, res.Count())
+	}
+}
+
+func TestExecuteNumberBounds(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "-2147483648 | -9223372036854775808L | (-2147483648).is(System.Integer)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewInteger(math.MinInt32), res.Get(0))
+		assert.Equal(t, hipathsys.NewLong(math.MinInt64), res.Get(1))
+		assert.Equal(t, hipathsys.True, res.Get(2))
+	}
+}
+
+func TestExecuteNumberBoundsOverflow(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(-2147483647 - 1).abs() | -(-2147483647 - 1) | "+
+		"(-9223372036854775807L - 1).abs() | -(-9223372036854775807L - 1)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") {
+		assert.Equal(t, 0, res.Count())
+	}
+}
+
+func TestExecuteNumberLiteralOutOfRange(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	_, err := Execute(ctx, "2147483648", nil)
+	assert.NotNil(t, err, "error expected")
+	_, err = Execute(ctx, "-2147483649", nil)
+	assert.NotNil(t, err, "error expected")
+}
+
+func TestExecuteLongCompare(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(8000000000L > 1) and (1L = 1) and (1 < 2L) and (2L <= 2.5) and '8000000000'.toLong() = 8000000000L", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteType(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "1.type().namespace | 1.type().name | 'a'.type().baseType | 1.type().element", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("System"), res.Get(0))
+		assert.Equal(t, hipathsys.NewString("Integer"), res.Get(1))
+		assert.Equal(t, hipathsys.NewString("System.Any"), res.Get(2))
+	}
+}
+
+func TestExecuteSort(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(3 | 1 | 2).sort()", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewInteger(1), res.Get(0))
+		assert.Equal(t, hipathsys.NewInteger(2), res.Get(1))
+		assert.Equal(t, hipathsys.NewInteger(3), res.Get(2))
+	}
+
+	res, err = Execute(ctx, "(3 | 1 | 2).sort(-$this)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewInteger(3), res.Get(0))
+		assert.Equal(t, hipathsys.NewInteger(2), res.Get(1))
+		assert.Equal(t, hipathsys.NewInteger(1), res.Get(2))
+	}
+}
+
+func TestExecuteSortNegatedString(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "('b' | 'a' | 'c').sort(-$this)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("c"), res.Get(0))
+		assert.Equal(t, hipathsys.NewString("b"), res.Get(1))
+		assert.Equal(t, hipathsys.NewString("a"), res.Get(2))
+	}
+
+	res, err = Execute(ctx, "('b' | 'a' | 'c').sort(x => -x)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("c"), res.Get(0))
+		assert.Equal(t, hipathsys.NewString("b"), res.Get(1))
+		assert.Equal(t, hipathsys.NewString("a"), res.Get(2))
+	}
+}
+
+func TestExecuteSortNegatedDate(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(@2020-02-01 | @2021-01-01 | @2019-12-31).sort(-$this)", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.True(t, hipathsys.NewDateYMD(2021, 1, 1).Equal(res.Get(0)))
+		assert.True(t, hipathsys.NewDateYMD(2020, 2, 1).Equal(res.Get(1)))
+		assert.True(t, hipathsys.NewDateYMD(2019, 12, 31).Equal(res.Get(2)))
+	}
+}
+
+func TestExecuteSortEmptyKey(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(2 | 3 | 1).sort(iif($this = 3, {}, $this))", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewInteger(3), res.Get(0))
+		assert.Equal(t, hipathsys.NewInteger(1), res.Get(1))
+		assert.Equal(t, hipathsys.NewInteger(2), res.Get(2))
+	}
+
+	res, err = Execute(ctx, "(2 | 3 | 1).sort(iif($this = 3, {}, $this), 'desc')", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 3, res.Count()) {
+		assert.Equal(t, hipathsys.NewInteger(2), res.Get(0))
+		assert.Equal(t, hipathsys.NewInteger(1), res.Get(1))
+		assert.Equal(t, hipathsys.NewInteger(3), res.Get(2))
+	}
+}
+
+func TestExecuteSortKeys(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "('b1' | 'a2' | 'b2' | 'c' | 'a1').sort(x => x.substring(0, 1), x => -x.substring(1).toInteger())", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 5, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("a2"), res.Get(0))
+		assert.Equal(t, hipathsys.NewString("a1"), res.Get(1))
+		assert.Equal(t, hipathsys.NewString("b2"), res.Get(2))
+		assert.Equal(t, hipathsys.NewString("b1"), res.Get(3))
+		assert.Equal(t, hipathsys.NewString("c"), res.Get(4))
+	}
+}
+
+func TestExecuteSortStable(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "('bb' | 'a' | 'cc' | 'd').sort(length(), 'desc')", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 4, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("bb"), res.Get(0))
+		assert.Equal(t, hipathsys.NewString("cc"), res.Get(1))
+		assert.Equal(t, hipathsys.NewString("a"), res.Get(2))
+		assert.Equal(t, hipathsys.NewString("d"), res.Get(3))
+	}
+}
+
+func TestCompileSortDirectionIdentifier(t *testing.T) {
+	_, err := Compile("(2 | 1).sort(desc)")
+	if assert.NotNil(t, err, "error expected") && assert.Equal(t, 1, len(err.Items())) {
+		assert.Equal(t, "sort direction must be specified as string literal 'desc' in parameter 0", err.Items()[0].Msg())
+	}
+}
+
+func TestExecuteStringFunctions(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "' a,b,c '.trim().split(',').join('|') = 'a|b|c' and "+
+		"'test'.encode('base64').decode('base64') = 'test' and "+
+		"'a<b'.escape('html') = 'a&lt;b' and 'abab'.lastIndexOf('ab') = 2 and "+
+		"'abc'.matchesFull('b') = false", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteBoundaries(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "1.58700.precision() = 5 and @2014-03.precision() = 6 and "+
+		"@2014-03.lowBoundary() >= @2014-01-01 and @2014-03.highBoundary() <= @2014-03-31 and "+
+		"1.587.highBoundary(2) = 1.59", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteDateTimeFunctions(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "@1974-12-25.duration(@2020-12-24, 'years') = 45 and "+
+		"@2020-03-01T22:00.difference(@2020-03-04T08:00, 'days') = 3 and "+
+		"@2020-01-31.duration(@2020-02-29, 'month') = 1 and @2020-03-31.duration(@2020-04-30, 'months') = 1 and "+
+		"@2014-03-12T10:20.hourOf() = 10 and @2014-03-12.yearOf() = 2014 and @2014.monthOf().empty() and "+
+		"@2014-01-05T10:30:00.timezoneOffsetOf().empty() and @2014-01-05T10:30:00-05:30.timezoneOffsetOf() = -5.5", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteAggregateFunctions(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(1 | 2 | 3 | 4).sum() = 10 and (1 | 2 | 6).avg() = 3 and "+
+		"(4 | 1 | 3 | 2).median() = 2.5 and (3 | 1 | 2).min() = 1 and ('a' | 'c' | 'b').max() = 'c' and "+
+		"(1 'minute' | 30 seconds).sum() = 90 seconds and {}.sum().empty() and "+
+		"(1 'mg' | 0.002 'g').sum() = 3 'mg' and (1 'mg' | 0.002 'g').avg() = 1.5 'mg' and "+
+		"(1 'mg' | 0.003 'g' | 2 'mg').median() = 2 'mg'", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteUCUMQuantities(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "5 'mg' = 0.005 'g' and 1 '[in_i]' = 2.54 'cm' and 1 'L' ~ 1000 'mL' and "+
+		"37 'Cel' > 98 '[degF]' and 1 'mg/dL' = 10 'mg/L' and (1 'kg' = 1 'L') = false", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteCalendarUCUMQuantities(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "1 day = 24 'h' and 1 second = 1 's' and 1 'd' = 1 day and 1 day > 23 'h' and "+
+		"(1 year = 1 'a').empty() and (1 month = 1 'mo').empty() and (1 year > 1 'a').empty() and 1 year ~ 1 'a'", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteQuantityDimensionalAnalysis(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "(70 'kg' / (1.75 'm' * 1.75 'm')).toString()", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("22.85714285714285714285714286 'kg/m2'"), res.Get(0))
+	}
+}
+
+func TestExecuteQuantityConcentration(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "1 'mg' + 2 'g' = 2001 'mg' and 1 'L' - 250 'mL' = 0.75 'L' and "+
+		"10 'mg' / 2 'mL' = 5 'mg/mL' and 5 'mg/mL' = 5 'g/L' and 10 'mg' / 5 'mg' = 2 '1' and "+
+		"2 / 1 'mg' = 2 '/mg' and 2 / 1 'mg' * 1 'mg' = 2 '1' and 2 * 3 '/mg' * 1 'g' = 6000 '1' and "+
+		"2 'h' + 30 'min' = 2.5 'h' and 1 'd' + 1 'wk' = 8 'd' and 1 'wk' + 1 'd' = 8 'd'", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteToQuantityUnit(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "1.5 'g'.toQuantity('mg') = 1500 'mg' and 3 days.toQuantity('h') = 72 'h' and "+
+		"120 'min'.toQuantity('hours') = 2 hours and 1 year.toQuantity('a').empty() and "+
+		"(1 'wk').toQuantity('day').toString() = '7 \\'day\\'' and "+
+		"5 'mg'.toQuantity('mL').empty() and '5 \\'mg\\''.convertsToQuantity('g') and "+
+		"'5 \\'mg\\''.convertsToQuantity('mL') = false", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteSystemEnvVars(t *testing.T) {
+	ctx := test.NewTestContext(t)
+	res, err := Execute(ctx, "%ucum = 'http://unitsofmeasure.org' and %sct = 'http://snomed.info/sct' and "+
+		"%loinc = 'http://loinc.org' and %`vs-administrative-gender` = 'http://hl7.org/fhir/ValueSet/administrative-gender' and "+
+		"%`ext-patient-birthTime` = 'http://hl7.org/fhir/StructureDefinition/patient-birthTime' and "+
+		"%resource.empty() and %context.empty()", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteSystemEnvVarOverride(t *testing.T) {
+	ctx := hipathsys.NewContextBuilder(test.NewTestContext(t).ModelAdapter()).
+		EnvVar("sct", hipathsys.NewString("custom")).Build()
+	res, err := Execute(ctx, "%sct", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.NewString("custom"), res.Get(0))
+	}
+}
+
+type testCountingResolver struct {
+	count int
+}
+
+func (r *testCountingResolver) Resolve(_ hipathsys.ContextAccessor, _ interface{}, reference string) (interface{}, error) {
+	r.count++
+	return hipathsys.NewString("resolved " + reference), nil
+}
+
+func TestExecuteResolveMemoized(t *testing.T) {
+	resolver := &testCountingResolver{}
+	ctx := hipathsys.NewContextBuilder(test.NewTestContext(t).ModelAdapter()).
+		ReferenceResolver(resolver).Build()
+	path, err := Compile("('Patient/1' | 'Patient/2').resolve() | 'Patient/1'.resolve() | 'Patient/2'.resolve()")
+	if !assert.Nil(t, err, "no error expected") {
+		return
+	}
+
+	for i := 1; i <= 2; i++ {
+		res, err := path.Execute(ctx, nil)
+		assert.Nil(t, err, "no error expected")
+		if assert.NotNil(t, res, "result expected") && assert.Equal(t, 2, res.Count()) {
+			assert.Equal(t, hipathsys.NewString("resolved Patient/1"), res.Get(0))
+			assert.Equal(t, hipathsys.NewString("resolved Patient/2"), res.Get(1))
+		}
+		assert.Equal(t, 2*i, resolver.count)
+	}
+}
+
+func TestExecuteResolveDefault(t *testing.T) {
+	res, err := Execute(test.NewTestContext(t), "'Patient/1'.resolve().empty()", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteHasValueGetValue(t *testing.T) {
+	res, err := Execute(test.NewTestContext(t), "'test'.hasValue() and 'test'.getValue() = 'test' and "+
+		"('a' | 'b').hasValue() = false and {}.getValue().empty()", nil)
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}
+
+func TestExecuteMemberOfNoTerminologyProvider(t *testing.T) {
+	res, err := Execute(test.NewTestContext(t), "'diabetes'.memberOf(%`vs-conditions`)", nil)
+	if assert.NotNil(t, err, "error expected") {
+		assert.True(t, errors.Is(err, hipathsys.ErrTerminology))
+	}
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteTerminologiesNoService(t *testing.T) {
+	res, err := Execute(test.NewTestContext(t), "%terminologies.expand('http://example.org/vs')", nil)
+	assert.NotNil(t, err, "error expected")
+	assert.Nil(t, res, "no result expected")
+}
+
+func TestExecuteExternalConstantFunctionInput(t *testing.T) {
+	adapter := test.NewTestContext(t).ModelAdapter()
+	ctx := hipathsys.NewContextBuilder(adapter).
+		Node(hipathsys.NewString("context")).
+		EnvVar("values", hipathsys.NewColWithItem(adapter, hipathsys.NewInteger(5))).
+		Build()
+	res, err := Execute(ctx, "%values.where($this > 3).select($this + 1) = 6 and %values.exists($this = 5)", ctx.ContextNode())
+	assert.Nil(t, err, "no error expected")
+	if assert.NotNil(t, res, "result expected") && assert.Equal(t, 1, res.Count()) {
+		assert.Equal(t, hipathsys.True, res.Get(0))
+	}
+}